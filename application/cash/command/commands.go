@@ -0,0 +1,30 @@
+package command
+
+import (
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+)
+
+type DepositCashCommand struct {
+	PortfolioId string
+	Amount      domain.Money
+	Date        string
+}
+
+func NewDepositCashCommand(portfolioId shared.PortfolioId, amount domain.Money, date shared.CommandDate) DepositCashCommand {
+	command := DepositCashCommand{portfolioId.Get(), amount, date.Get()}
+
+	return command
+}
+
+type WithdrawCashCommand struct {
+	PortfolioId string
+	Amount      domain.Money
+	Date        string
+}
+
+func NewWithdrawCashCommand(portfolioId shared.PortfolioId, amount domain.Money, date shared.CommandDate) WithdrawCashCommand {
+	command := WithdrawCashCommand{portfolioId.Get(), amount, date.Get()}
+
+	return command
+}
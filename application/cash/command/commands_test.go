@@ -0,0 +1,26 @@
+package command_test
+
+import (
+	"reflect"
+	"stock-monitor/application/cash/command"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestDepositCashCommand(t *testing.T) {
+	depositCashCommand := command.NewDepositCashCommand("default", domain.NewMoneyFromFloat(1000, "EUR"), "2001-01-01")
+	expected := command.DepositCashCommand{"default", domain.NewMoneyFromFloat(1000, "EUR"), "2001-01-01"}
+
+	if reflect.DeepEqual(depositCashCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", depositCashCommand, expected)
+	}
+}
+
+func TestWithdrawCashCommand(t *testing.T) {
+	withdrawCashCommand := command.NewWithdrawCashCommand("default", domain.NewMoneyFromFloat(500, "EUR"), "2001-01-01")
+	expected := command.WithdrawCashCommand{"default", domain.NewMoneyFromFloat(500, "EUR"), "2001-01-01"}
+
+	if reflect.DeepEqual(withdrawCashCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", withdrawCashCommand, expected)
+	}
+}
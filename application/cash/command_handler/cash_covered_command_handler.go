@@ -0,0 +1,38 @@
+package command_handler
+
+import (
+	"stock-monitor/application/cash/persistence"
+	"stock-monitor/application/event"
+	"stock-monitor/application/portfolio/command"
+	portfolioCommandHandler "stock-monitor/application/portfolio/command_handler"
+	portfolioPersistence "stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain/cash"
+)
+
+// NewCashCoveredCommandHandler makes a portfolio command handler that rejects buys costing more than the cash of the portfolio.
+// The cash is checked on every attempt and the buy is only published while none of the streams the cash came from changed.
+func NewCashCoveredCommandHandler(repository portfolioPersistence.PortfolioRepository, publisher event.EventPublisher, cashRepository persistence.CashRepository) portfolioCommandHandler.PortfolioCommandHandlerInterface {
+	return portfolioCommandHandler.NewGuardedCommandHandler(repository, publisher, NewCashCoveredBuyGuard(cashRepository))
+}
+
+// NewCashCoveredBuyGuard rejects buys costing more than the cash and pins the streams the cash came from,
+// for every command handler publishing buys
+func NewCashCoveredBuyGuard(cashRepository persistence.CashRepository) portfolioCommandHandler.BuyGuard {
+	return func(command command.AddSharesToPortfolioCommand) ([]event.Publication, error) {
+		pins, err := cashRepository.Pins()
+		if err != nil {
+			return nil, err
+		}
+		c, err := cashRepository.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.CanPay(cash.BuyCost(command.Price, command.NumberOfShares, command.Fee, command.Taxes))
+		if err != nil {
+			return nil, err
+		}
+
+		return pins, nil
+	}
+}
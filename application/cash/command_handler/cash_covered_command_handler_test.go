@@ -0,0 +1,124 @@
+package command_handler_test
+
+import (
+	"stock-monitor/application/cash/command_handler"
+	"stock-monitor/application/cash/persistence"
+	"stock-monitor/application/event"
+	portfolioCommand "stock-monitor/application/portfolio/command"
+	portfolioCommandHandler "stock-monitor/application/portfolio/command_handler"
+	portfolioPersistence "stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func makeCashCoveredCommandHandler(cashEventStream infrastructure.EventStream, portfolioEventStream infrastructure.EventStream) portfolioCommandHandler.PortfolioCommandHandlerInterface {
+	cashRepository := persistence.NewEventSourcedCashRepository(cashEventStream, portfolioEventStream, &infrastructure.InMemoryEventStream{})
+
+	return makeCashCoveredCommandHandlerWithRepository(portfolioEventStream, &cashRepository)
+}
+
+func makeCashCoveredCommandHandlerWithRepository(portfolioEventStream infrastructure.EventStream, cashRepository persistence.CashRepository) portfolioCommandHandler.PortfolioCommandHandlerInterface {
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(portfolioEventStream)
+
+	return command_handler.NewCashCoveredCommandHandler(&portfolioRepository, event.NewEventPublisher(portfolioEventStream), cashRepository)
+}
+
+// racingCashRepository appends an event right after the cash was loaded for the first time, like a concurrent command would
+type racingCashRepository struct {
+	persistence.CashRepository
+	eventStream infrastructure.EventStream
+	event       infrastructure.Event
+	raced       bool
+}
+
+func (repository *racingCashRepository) Load() (cash.Cash, error) {
+	c, err := repository.CashRepository.Load()
+	if !repository.raced {
+		repository.raced = true
+		repository.eventStream.Add(repository.event)
+	}
+
+	return c, err
+}
+
+func TestBuysCoveredByCashArePassedOn(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "100", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCashCoveredCommandHandler(&cashEventStream, &portfolioEventStream)
+
+	err := commandHandler.HandleAddSharesToPortfolio(portfolioCommand.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(9), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(portfolioEventStream.Events) != 1 {
+		t.Errorf("Expected one event in portfolio but got %#v", portfolioEventStream.Events)
+	}
+}
+
+func TestBuysOverdrawingCashAreRejected(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "100", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCashCoveredCommandHandler(&cashEventStream, &portfolioEventStream)
+
+	err := commandHandler.HandleAddSharesToPortfolio(portfolioCommand.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0.01, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+	if len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Unexpected events in portfolio: %#v", portfolioEventStream.Events)
+	}
+}
+
+func TestBuysAreRejectedWhenACashWithdrawalRacesThem(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "100", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	cashRepository := persistence.NewEventSourcedCashRepository(&cashEventStream, &portfolioEventStream, &infrastructure.InMemoryEventStream{})
+	commandHandler := makeCashCoveredCommandHandlerWithRepository(&portfolioEventStream, &racingCashRepository{
+		CashRepository: &cashRepository,
+		eventStream:    &cashEventStream,
+		event: infrastructure.Event{
+			cash.CashWithdrawnEventName,
+			map[string]interface{}{"amount": "50", "currency": "EUR", "date": "2000-01-02"},
+			map[string]interface{}{"occurred_at": "2000-01-02", "version": 1},
+		},
+	})
+
+	err := commandHandler.HandleAddSharesToPortfolio(portfolioCommand.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(9), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+	if len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Unexpected events in portfolio: %#v", portfolioEventStream.Events)
+	}
+}
@@ -0,0 +1,75 @@
+package command_handler
+
+import (
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/cash/persistence"
+	"stock-monitor/application/event"
+	"stock-monitor/domain/cash"
+	"stock-monitor/infrastructure"
+)
+
+const maxConcurrencyConflictRetries = 3
+
+type CashCommandHandlerInterface interface {
+	HandleDepositCash(command command.DepositCashCommand) error
+	HandleWithdrawCash(command command.WithdrawCashCommand) error
+}
+
+type CashCommandHandler struct {
+	repository persistence.CashRepository
+	publisher  event.EventPublisher
+}
+
+func NewCashCommandHandler(repository persistence.CashRepository, publisher event.EventPublisher) CashCommandHandlerInterface {
+	return &CashCommandHandler{repository: repository, publisher: publisher}
+}
+
+func (commandHandler *CashCommandHandler) HandleDepositCash(command command.DepositCashCommand) error {
+	// a deposit can't overdraw the cash, so it only depends on the cash stream
+	return commandHandler.handle(command.Date, func(c *cash.Cash) error {
+		return c.Deposit(command.Amount, command.Date)
+	}, false)
+}
+
+func (commandHandler *CashCommandHandler) HandleWithdrawCash(command command.WithdrawCashCommand) error {
+	return commandHandler.handle(command.Date, func(c *cash.Cash) error {
+		return c.Withdraw(command.Amount, command.Date)
+	}, true)
+}
+
+// handle pins the portfolio and dividend streams as well when the balance has to stay covered,
+// a buy or dividend reinvestment published in between then makes the attempt start over
+func (commandHandler *CashCommandHandler) handle(date string, execute func(c *cash.Cash) error, pinAllStreams bool) error {
+	var err error
+	for attempt := 0; attempt < maxConcurrencyConflictRetries; attempt++ {
+		var pins []event.Publication
+		pins, err = commandHandler.repository.Pins()
+		if err != nil {
+			return err
+		}
+		var c cash.Cash
+		c, err = commandHandler.repository.Load()
+		if err != nil {
+			return err
+		}
+
+		err = execute(&c)
+
+		if err != nil {
+			return err
+		}
+
+		if !pinAllStreams {
+			pins = pins[:1]
+		}
+		pins[0] = event.Publication{commandHandler.publisher, c.GetRecordedEvents(), pins[0].ExpectedVersion, ""}
+		err = event.PublishAtomically(pins, date)
+
+		_, conflict := err.(*infrastructure.ConcurrencyConflictError)
+		if !conflict {
+			return err
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,134 @@
+package command_handler_test
+
+import (
+	"reflect"
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/cash/command_handler"
+	"stock-monitor/application/cash/persistence"
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func makeCashCommandHandler(cashEventStream infrastructure.EventStream, portfolioEventStream infrastructure.EventStream) command_handler.CashCommandHandlerInterface {
+	repository := persistence.NewEventSourcedCashRepository(cashEventStream, portfolioEventStream, &infrastructure.InMemoryEventStream{})
+	return command_handler.NewCashCommandHandler(&repository, event.NewEventPublisher(cashEventStream))
+}
+
+func TestItHandlesDepositCashCommand(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCashCommandHandler(&cashEventStream, &infrastructure.InMemoryEventStream{})
+
+	err := commandHandler.HandleDepositCash(command.NewDepositCashCommand("default", domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01"))
+
+	expectedEvent := infrastructure.Event{
+		cash.CashDepositedEventName,
+		map[string]interface{}{
+			"amount":   "1000",
+			"currency": "EUR",
+			"date":     "2000-01-01",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(cashEventStream.Events) != 1 || reflect.DeepEqual(cashEventStream.Events[0], expectedEvent) == false {
+		t.Errorf("Unexpected event published. Expected:%#v Got:%#v", expectedEvent, cashEventStream.Events)
+	}
+}
+
+func TestItHandlesWithdrawCashCommand(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "1000", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	commandHandler := makeCashCommandHandler(&cashEventStream, &infrastructure.InMemoryEventStream{})
+
+	err := commandHandler.HandleWithdrawCash(command.NewWithdrawCashCommand("default", domain.NewMoneyFromFloat(400, "EUR"), "2000-01-02"))
+
+	expectedEvent := infrastructure.Event{
+		cash.CashWithdrawnEventName,
+		map[string]interface{}{
+			"amount":   "400",
+			"currency": "EUR",
+			"date":     "2000-01-02",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 1},
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(cashEventStream.Events) != 2 || reflect.DeepEqual(cashEventStream.Events[1], expectedEvent) == false {
+		t.Errorf("Unexpected event published. Expected:%#v Got:%#v", expectedEvent, cashEventStream.Events)
+	}
+}
+
+func TestItReturnsErrorWhenWithdrawCashCommandFails(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCashCommandHandler(&cashEventStream, &infrastructure.InMemoryEventStream{})
+
+	err := commandHandler.HandleWithdrawCash(command.NewWithdrawCashCommand("default", domain.NewMoneyFromFloat(400, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+	if len(cashEventStream.Events) != 0 {
+		t.Errorf("Unexpected events published: %#v", cashEventStream.Events)
+	}
+}
+
+func TestItReturnsErrorWhenPublishingEventAfterDepositCashCommandFails(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCashCommandHandler(&cashEventStream, &infrastructure.InMemoryEventStream{})
+
+	err := commandHandler.HandleDepositCash(command.NewDepositCashCommand("default", domain.NewMoneyFromFloat(1000, "EUR"), "FOO"))
+
+	if err == nil {
+		t.Errorf("Expected Error but got none")
+	}
+}
+
+func TestWithdrawalsAreRejectedWhenABuyRacesThem(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "1000", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	repository := persistence.NewEventSourcedCashRepository(&cashEventStream, &portfolioEventStream, &infrastructure.InMemoryEventStream{})
+	commandHandler := command_handler.NewCashCommandHandler(&racingCashRepository{
+		CashRepository: &repository,
+		eventStream:    &portfolioEventStream,
+		event: infrastructure.Event{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "shares": "10", "price": "50", "fee": "0", "taxes": "0", "currency": "EUR", "date": "2000-01-02"},
+			map[string]interface{}{"occurred_at": "2000-01-02", "version": 4},
+		},
+	}, event.NewEventPublisher(&cashEventStream))
+
+	err := commandHandler.HandleWithdrawCash(command.NewWithdrawCashCommand("default", domain.NewMoneyFromFloat(600, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+	if len(cashEventStream.Events) != 1 {
+		t.Errorf("Unexpected events published: %#v", cashEventStream.Events)
+	}
+}
@@ -0,0 +1,42 @@
+package command_handler
+
+import (
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/shared"
+)
+
+// CashCommandRouter passes each command to the command handler of the portfolio it belongs to
+type CashCommandRouter struct {
+	commandHandlers map[string]CashCommandHandlerInterface
+}
+
+func NewCashCommandRouter(commandHandlers map[string]CashCommandHandlerInterface) CashCommandHandlerInterface {
+	return &CashCommandRouter{commandHandlers: commandHandlers}
+}
+
+func (router *CashCommandRouter) HandleDepositCash(command command.DepositCashCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleDepositCash(command)
+}
+
+func (router *CashCommandRouter) HandleWithdrawCash(command command.WithdrawCashCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleWithdrawCash(command)
+}
+
+func (router *CashCommandRouter) commandHandler(portfolioId string) (CashCommandHandlerInterface, error) {
+	commandHandler, found := router.commandHandlers[portfolioId]
+	if !found {
+		return nil, shared.NewUnknownPortfolioError(portfolioId)
+	}
+
+	return commandHandler, nil
+}
@@ -0,0 +1,42 @@
+package command_handler_test
+
+import (
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/cash/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func TestCashCommandsAreRoutedToTheirPortfolio(t *testing.T) {
+	defaultEventStream := infrastructure.InMemoryEventStream{}
+	retirementEventStream := infrastructure.InMemoryEventStream{}
+	router := command_handler.NewCashCommandRouter(map[string]command_handler.CashCommandHandlerInterface{
+		"default":    makeCashCommandHandler(&defaultEventStream, &infrastructure.InMemoryEventStream{}),
+		"retirement": makeCashCommandHandler(&retirementEventStream, &infrastructure.InMemoryEventStream{}),
+	})
+
+	err := router.HandleDepositCash(command.NewDepositCashCommand("retirement", domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(defaultEventStream.Events) != 0 {
+		t.Errorf("Unexpected events in default portfolio: %#v", defaultEventStream.Events)
+	}
+	if len(retirementEventStream.Events) != 1 {
+		t.Errorf("Expected one event in retirement portfolio but got %#v", retirementEventStream.Events)
+	}
+}
+
+func TestCashCommandsForUnknownPortfoliosFail(t *testing.T) {
+	router := command_handler.NewCashCommandRouter(map[string]command_handler.CashCommandHandlerInterface{})
+
+	err := router.HandleWithdrawCash(command.NewWithdrawCashCommand("foo", domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01"))
+
+	_, ok := err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/infrastructure"
+)
+
+type CashRepository interface {
+	Load() (cash.Cash, error)
+	Version() (int, error)
+	Pins() ([]event.Publication, error)
+}
+
+// EventSourcedCashRepository settles buys, sales and dividends from the portfolio and dividend streams against the cash stream
+type EventSourcedCashRepository struct {
+	cashEventStream      infrastructure.EventStream
+	portfolioEventStream infrastructure.EventStream
+	dividendEventStream  infrastructure.EventStream
+}
+
+func NewEventSourcedCashRepository(cashEventStream infrastructure.EventStream, portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream) EventSourcedCashRepository {
+	return EventSourcedCashRepository{cashEventStream: cashEventStream, portfolioEventStream: portfolioEventStream, dividendEventStream: dividendEventStream}
+}
+
+func (repository *EventSourcedCashRepository) Load() (cash.Cash, error) {
+	c := cash.NewCash()
+	for _, eventStream := range repository.eventStreams() {
+		storedEvents, err := eventStream.Get()
+		if err != nil {
+			return cash.Cash{}, err
+		}
+		for _, storedEvent := range storedEvents {
+			domainEvent, err := event.Decode(storedEvent)
+			if err != nil {
+				return cash.Cash{}, err
+			}
+			c.Apply(domainEvent)
+		}
+	}
+	return c, nil
+}
+
+func (repository *EventSourcedCashRepository) Version() (int, error) {
+	return repository.cashEventStream.Version()
+}
+
+// Pins returns empty publications pinning every stream the cash is loaded from at its current version,
+// the first one pins the cash stream. Pins have to be taken before the cash is loaded.
+func (repository *EventSourcedCashRepository) Pins() ([]event.Publication, error) {
+	pins := []event.Publication{}
+	for _, eventStream := range repository.eventStreams() {
+		version, err := eventStream.Version()
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, event.Publication{event.NewEventPublisher(eventStream), []domain.DomainEvent{}, version, ""})
+	}
+
+	return pins, nil
+}
+
+func (repository *EventSourcedCashRepository) eventStreams() []infrastructure.EventStream {
+	return []infrastructure.EventStream{repository.cashEventStream, repository.portfolioEventStream, repository.dividendEventStream}
+}
@@ -0,0 +1,105 @@
+package persistence_test
+
+import (
+	"stock-monitor/application/cash/persistence"
+	"stock-monitor/application/event"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func TestCashIsLoadedFromCashPortfolioAndDividendEvents(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "1000", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	portfolioEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "10", "price": "50", "fee": "5", "taxes": "0", "currency": "EUR", "date": "2000-01-02"},
+				map[string]interface{}{"occurred_at": "2000-01-02", "version": 4},
+			},
+		},
+	}
+	dividendEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				dividend.DividendRecordedEventName,
+				map[string]interface{}{"ticker": "MO", "net": "7.5", "gross": "10", "currency": "EUR", "date": "2000-01-03"},
+				map[string]interface{}{"occurred_at": "2000-01-03", "version": 2},
+			},
+		},
+	}
+	repository := persistence.NewEventSourcedCashRepository(&cashEventStream, &portfolioEventStream, &dividendEventStream)
+
+	c, err := repository.Load()
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	expected := "502.5 EUR"
+	got := c.Balance("EUR").String()
+
+	if expected != got {
+		t.Errorf("Unexpected balance. Expected:%#v Got:%#v", expected, got)
+	}
+	version, _ := repository.Version()
+	if version != 1 {
+		t.Errorf("Expected version of the cash stream but got %#v", version)
+	}
+}
+
+func TestPinsCoverEveryStreamTheCashIsLoadedFrom(t *testing.T) {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "1000", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	repository := persistence.NewEventSourcedCashRepository(&cashEventStream, &infrastructure.InMemoryEventStream{}, &infrastructure.InMemoryEventStream{})
+
+	pins, err := repository.Pins()
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(pins) != 3 {
+		t.Fatalf("Expected pins of the cash, portfolio and dividend streams but got %#v", pins)
+	}
+	if pins[0].ExpectedVersion != 1 || pins[1].ExpectedVersion != 0 || pins[2].ExpectedVersion != 0 {
+		t.Errorf("Unexpected versions pinned: %#v", pins)
+	}
+	for _, pin := range pins {
+		if len(pin.Events) != 0 {
+			t.Errorf("Expected pins without events but got %#v", pin.Events)
+		}
+	}
+}
+
+func TestCashCanNotBeLoadedWhenAnEventOfAnyStreamCanNotBeDecoded(t *testing.T) {
+	dividendEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{"Dividend.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}},
+		},
+	}
+	repository := persistence.NewEventSourcedCashRepository(&infrastructure.InMemoryEventStream{}, &infrastructure.InMemoryEventStream{}, &dividendEventStream)
+
+	_, err := repository.Load()
+
+	_, ok := err.(*event.UnknownEventError)
+	if !ok {
+		t.Errorf("Expected UnknownEventError but got %#v", err)
+	}
+}
@@ -2,17 +2,35 @@ package command
 
 import (
 	"stock-monitor/application/shared"
+	"stock-monitor/domain"
 )
 
 type RecordDividendCommand struct {
-	Ticker string
-	Net    float32
-	Gross  float32
-	Date   string
+	PortfolioId string
+	Ticker      string
+	Net         domain.Money
+	Gross       domain.Money
+	Date        string
 }
 
-func NewRecordDividendCommand(ticker string, net float32, gross float32, date shared.CommandDate) RecordDividendCommand {
-	command := RecordDividendCommand{ticker, net, gross, date.Get()}
+func NewRecordDividendCommand(portfolioId shared.PortfolioId, ticker string, net domain.Money, gross domain.Money, date shared.CommandDate) RecordDividendCommand {
+	command := RecordDividendCommand{portfolioId.Get(), ticker, net, gross, date.Get()}
+
+	return command
+}
+
+// ReinvestDividendCommand records a dividend and buys shares of the same ticker with the net dividend at Price
+type ReinvestDividendCommand struct {
+	PortfolioId string
+	Ticker      string
+	Net         domain.Money
+	Gross       domain.Money
+	Price       domain.Money
+	Date        string
+}
+
+func NewReinvestDividendCommand(portfolioId shared.PortfolioId, ticker string, net domain.Money, gross domain.Money, price domain.Money, date shared.CommandDate) ReinvestDividendCommand {
+	command := ReinvestDividendCommand{portfolioId.Get(), ticker, net, gross, price, date.Get()}
 
 	return command
 }
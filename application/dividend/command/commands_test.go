@@ -3,14 +3,24 @@ package command_test
 import (
 	"reflect"
 	"stock-monitor/application/dividend/command"
+	"stock-monitor/domain"
 	"testing"
 )
 
 func TestRecordDividendCommand(t *testing.T) {
-	recordDividendCommand := command.NewRecordDividendCommand("MO", 20.00, 19.99, "2001-01-01")
-	expected := command.RecordDividendCommand{"MO", 20.00, 19.99, "2001-01-01"}
+	recordDividendCommand := command.NewRecordDividendCommand("default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(19.99, "EUR"), "2001-01-01")
+	expected := command.RecordDividendCommand{"default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(19.99, "EUR"), "2001-01-01"}
 
 	if reflect.DeepEqual(recordDividendCommand, expected) == false {
 		t.Errorf("Unexpected command. got: %#v, want: %#v", recordDividendCommand, expected)
 	}
 }
+
+func TestReinvestDividendCommand(t *testing.T) {
+	reinvestDividendCommand := command.NewReinvestDividendCommand("default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(25.00, "EUR"), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-01")
+	expected := command.ReinvestDividendCommand{"default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(25.00, "EUR"), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-01"}
+
+	if reflect.DeepEqual(reinvestDividendCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", reinvestDividendCommand, expected)
+	}
+}
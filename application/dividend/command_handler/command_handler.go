@@ -4,25 +4,37 @@ import (
 	"stock-monitor/application/dividend/command"
 	"stock-monitor/application/dividend/persistence"
 	"stock-monitor/application/event"
+	portfolioPersistence "stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
 )
 
+const maxConcurrencyConflictRetries = 3
+
 type DividendCommandHandlerInterface interface {
 	HandleRecordDividend(command command.RecordDividendCommand) error
+	HandleReinvestDividend(command command.ReinvestDividendCommand) error
 }
 
 type DividendCommandHandler struct {
-	repository persistence.DividendRepository
-	publisher  event.EventPublisher
+	repository          persistence.DividendRepository
+	publisher           event.EventPublisher
+	portfolioRepository portfolioPersistence.PortfolioRepository
+	portfolioPublisher  event.EventPublisher
 }
 
-func NewDividendCommandHandler(repository persistence.DividendRepository, publisher event.EventPublisher) DividendCommandHandlerInterface {
-	return &DividendCommandHandler{repository: repository, publisher: publisher}
+func NewDividendCommandHandler(repository persistence.DividendRepository, publisher event.EventPublisher, portfolioRepository portfolioPersistence.PortfolioRepository, portfolioPublisher event.EventPublisher) DividendCommandHandlerInterface {
+	return &DividendCommandHandler{repository: repository, publisher: publisher, portfolioRepository: portfolioRepository, portfolioPublisher: portfolioPublisher}
 }
 
 func (commandHandler *DividendCommandHandler) HandleRecordDividend(command command.RecordDividendCommand) error {
-	d := commandHandler.repository.Load()
+	d, err := commandHandler.repository.Load()
+	if err != nil {
+		return err
+	}
 
-	err := d.RecordDividend(command.Ticker, command.Net, command.Gross, command.Date)
+	err = d.RecordDividend(command.Ticker, command.Net, command.Gross, command.Date)
 
 	if err != nil {
 		return err
@@ -36,3 +48,47 @@ func (commandHandler *DividendCommandHandler) HandleRecordDividend(command comma
 
 	return nil
 }
+
+// the dividend and the buy are published together or not at all
+func (commandHandler *DividendCommandHandler) HandleReinvestDividend(command command.ReinvestDividendCommand) error {
+	var err error
+	for attempt := 0; attempt < maxConcurrencyConflictRetries; attempt++ {
+		var version int
+		version, err = commandHandler.portfolioRepository.Version()
+		if err != nil {
+			return err
+		}
+		var d dividend.Dividend
+		d, err = commandHandler.repository.Load()
+		if err != nil {
+			return err
+		}
+		var p portfolio.Portfolio
+		p, err = commandHandler.portfolioRepository.Load()
+		if err != nil {
+			return err
+		}
+
+		err = d.RecordDividend(command.Ticker, command.Net, command.Gross, command.Date)
+		if err != nil {
+			return err
+		}
+
+		err = p.ReinvestDividend(command.Ticker, command.Net, command.Price, command.Date)
+		if err != nil {
+			return err
+		}
+
+		err = event.PublishAtomically([]event.Publication{
+			{commandHandler.publisher, d.GetRecordedEvents(), infrastructure.AnyVersion, ""},
+			{commandHandler.portfolioPublisher, p.GetRecordedEvents(), version, ""},
+		}, command.Date)
+
+		_, conflict := err.(*infrastructure.ConcurrencyConflictError)
+		if !conflict {
+			return err
+		}
+	}
+
+	return err
+}
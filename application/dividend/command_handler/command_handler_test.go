@@ -6,6 +6,8 @@ import (
 	"stock-monitor/application/dividend/command_handler"
 	"stock-monitor/application/dividend/persistence"
 	"stock-monitor/application/event"
+	portfolioPersistence "stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain"
 	"stock-monitor/domain/dividend"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
@@ -30,21 +32,23 @@ func TestItHandlesRecordDividendCommand(t *testing.T) {
 	dividendEventStream := infrastructure.InMemoryEventStream{}
 
 	publisher := event.NewEventPublisher(&dividendEventStream)
-	recordDividendCommand := command.NewRecordDividendCommand("MO", 20.00, 21.00, "2000-01-02")
+	recordDividendCommand := command.NewRecordDividendCommand("default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(21.00, "EUR"), "2000-01-02")
 	repository := persistence.NewEventSourcedDividendRepository(&portfolioEventStream)
-	commandHandler := command_handler.NewDividendCommandHandler(&repository, publisher)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&portfolioEventStream)
+	commandHandler := command_handler.NewDividendCommandHandler(&repository, publisher, &portfolioRepository, event.NewEventPublisher(&portfolioEventStream))
 
 	commandHandler.HandleRecordDividend(recordDividendCommand)
 
 	expectedEvent := infrastructure.Event{
 		dividend.DividendRecordedEventName,
 		map[string]interface{}{
-			"ticker": "MO",
-			"net":    float32(20.00),
-			"gross":  float32(21.00),
-			"date":   "2000-01-02",
+			"ticker":   "MO",
+			"net":      "20",
+			"gross":    "21",
+			"currency": "EUR",
+			"date":     "2000-01-02",
 		},
-		map[string]interface{}{"occurred_at": "2000-01-02"},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 2},
 	}
 	got := dividendEventStream.Events[0]
 
@@ -56,9 +60,10 @@ func TestItHandlesRecordDividendCommand(t *testing.T) {
 func TestItReturnsErrorWhenRecordDividendCommandFails(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	recordDividendCommand := command.NewRecordDividendCommand("MO", 0, 9.99, "2000-01-02")
+	recordDividendCommand := command.NewRecordDividendCommand("default", "MO", domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(9.99, "EUR"), "2000-01-02")
 	repository := persistence.NewEventSourcedDividendRepository(&eventStream)
-	commandHandler := command_handler.NewDividendCommandHandler(&repository, publisher)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewDividendCommandHandler(&repository, publisher, &portfolioRepository, publisher)
 
 	err := commandHandler.HandleRecordDividend(recordDividendCommand)
 
@@ -70,9 +75,10 @@ func TestItReturnsErrorWhenRecordDividendCommandFails(t *testing.T) {
 func TestItReturnsErrorWhenPublishingEventAfterRecordDividendCommandFails(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	recordDividendCommand := command.NewRecordDividendCommand("MO", 20.00, 21.00, "FOO")
+	recordDividendCommand := command.NewRecordDividendCommand("default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(21.00, "EUR"), "FOO")
 	repository := persistence.NewEventSourcedDividendRepository(&eventStream)
-	commandHandler := command_handler.NewDividendCommandHandler(&repository, publisher)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewDividendCommandHandler(&repository, publisher, &portfolioRepository, publisher)
 
 	err := commandHandler.HandleRecordDividend(recordDividendCommand)
 
@@ -80,3 +86,96 @@ func TestItReturnsErrorWhenPublishingEventAfterRecordDividendCommandFails(t *tes
 		t.Errorf("Expected Error but got none")
 	}
 }
+
+func TestItHandlesReinvestDividendCommand(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{
+					"ticker": "MO",
+					"shares": 20,
+					"price":  10.00,
+					"date":   "2000-01-01",
+				},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			},
+		},
+	}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+
+	reinvestDividendCommand := command.NewReinvestDividendCommand("default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(25.00, "EUR"), domain.NewMoneyFromFloat(16.00, "EUR"), "2000-01-02")
+	repository := persistence.NewEventSourcedDividendRepository(&portfolioEventStream)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&portfolioEventStream)
+	commandHandler := command_handler.NewDividendCommandHandler(&repository, event.NewEventPublisher(&dividendEventStream), &portfolioRepository, event.NewEventPublisher(&portfolioEventStream))
+
+	err := commandHandler.HandleReinvestDividend(reinvestDividendCommand)
+
+	expectedDividend := infrastructure.Event{
+		dividend.DividendRecordedEventName,
+		map[string]interface{}{
+			"ticker":   "MO",
+			"net":      "20",
+			"gross":    "25",
+			"currency": "EUR",
+			"date":     "2000-01-02",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 2},
+	}
+	expectedBuy := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{
+			"ticker":   "MO",
+			"shares":   "1.25",
+			"price":    "16",
+			"fee":      "0",
+			"taxes":    "0",
+			"currency": "EUR",
+			"date":     "2000-01-02",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 4},
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(dividendEventStream.Events, []infrastructure.Event{expectedDividend}) == false {
+		t.Errorf("Unexpected dividend event published. Expected:%#v Got:%#v", expectedDividend, dividendEventStream.Events)
+	}
+	if reflect.DeepEqual(portfolioEventStream.Events[1:], []infrastructure.Event{expectedBuy}) == false {
+		t.Errorf("Unexpected portfolio event published. Expected:%#v Got:%#v", expectedBuy, portfolioEventStream.Events[1:])
+	}
+}
+
+func TestNoDividendIsRecordedWhenReinvestmentFails(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{
+					"ticker": "MO",
+					"shares": 20,
+					"price":  10.00,
+					"date":   "2000-01-01",
+				},
+				map[string]interface{}{"occurred_at": "2000-01-03"},
+			},
+		},
+	}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+
+	// the buy can not be dated before the last portfolio event
+	reinvestDividendCommand := command.NewReinvestDividendCommand("default", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(25.00, "EUR"), domain.NewMoneyFromFloat(16.00, "EUR"), "2000-01-02")
+	repository := persistence.NewEventSourcedDividendRepository(&portfolioEventStream)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&portfolioEventStream)
+	commandHandler := command_handler.NewDividendCommandHandler(&repository, event.NewEventPublisher(&dividendEventStream), &portfolioRepository, event.NewEventPublisher(&portfolioEventStream))
+
+	err := commandHandler.HandleReinvestDividend(reinvestDividendCommand)
+
+	if err == nil {
+		t.Errorf("Expected Error but got none")
+	}
+	if len(dividendEventStream.Events) != 0 || len(portfolioEventStream.Events) != 1 {
+		t.Errorf("Expected no events to be published but got %#v and %#v", dividendEventStream.Events, portfolioEventStream.Events)
+	}
+}
@@ -0,0 +1,33 @@
+package command_handler
+
+import (
+	"stock-monitor/application/dividend/command"
+	"stock-monitor/application/shared"
+)
+
+// DividendCommandRouter passes each command to the command handler of the portfolio it belongs to
+type DividendCommandRouter struct {
+	commandHandlers map[string]DividendCommandHandlerInterface
+}
+
+func NewDividendCommandRouter(commandHandlers map[string]DividendCommandHandlerInterface) DividendCommandHandlerInterface {
+	return &DividendCommandRouter{commandHandlers: commandHandlers}
+}
+
+func (router *DividendCommandRouter) HandleRecordDividend(command command.RecordDividendCommand) error {
+	commandHandler, found := router.commandHandlers[command.PortfolioId]
+	if !found {
+		return shared.NewUnknownPortfolioError(command.PortfolioId)
+	}
+
+	return commandHandler.HandleRecordDividend(command)
+}
+
+func (router *DividendCommandRouter) HandleReinvestDividend(command command.ReinvestDividendCommand) error {
+	commandHandler, found := router.commandHandlers[command.PortfolioId]
+	if !found {
+		return shared.NewUnknownPortfolioError(command.PortfolioId)
+	}
+
+	return commandHandler.HandleReinvestDividend(command)
+}
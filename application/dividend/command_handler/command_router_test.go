@@ -0,0 +1,31 @@
+package command_handler_test
+
+import (
+	"stock-monitor/application/dividend/command"
+	"stock-monitor/application/dividend/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestRecordDividendCommandsForUnknownPortfoliosFail(t *testing.T) {
+	router := command_handler.NewDividendCommandRouter(map[string]command_handler.DividendCommandHandlerInterface{})
+
+	err := router.HandleRecordDividend(command.NewRecordDividendCommand("foo", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(21.00, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+}
+
+func TestReinvestDividendCommandsForUnknownPortfoliosFail(t *testing.T) {
+	router := command_handler.NewDividendCommandRouter(map[string]command_handler.DividendCommandHandlerInterface{})
+
+	err := router.HandleReinvestDividend(command.NewReinvestDividendCommand("foo", "MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(21.00, "EUR"), domain.NewMoneyFromFloat(40.00, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+}
@@ -1,13 +1,13 @@
 package persistence
 
 import (
+	"stock-monitor/application/event"
 	"stock-monitor/domain/dividend"
-	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 )
 
 type DividendRepository interface {
-	Load() dividend.Dividend
+	Load() (dividend.Dividend, error)
 }
 
 type EventSourcedDividendRepository struct {
@@ -18,26 +18,18 @@ func NewEventSourcedDividendRepository(eventStream infrastructure.EventStream) E
 	return EventSourcedDividendRepository{portfolioEventStream: eventStream}
 }
 
-func (repository *EventSourcedDividendRepository) Load() dividend.Dividend {
+func (repository *EventSourcedDividendRepository) Load() (dividend.Dividend, error) {
 	d := dividend.NewDividend()
-	for _, event := range repository.portfolioEventStream.Get() {
-
-		if event.Name == portfolio.SharesAddedToPortfolioEventName {
-			ticker := event.Payload["ticker"].(string)
-			shares := event.Payload["shares"].(int)
-			date := event.Payload["date"].(string)
-			domainEvent := portfolio.NewSharesAddedToPortfolioEvent(ticker, shares, 0.0, date)
-			d.Apply(&domainEvent)
-			continue
-		}
-
-		if event.Name == portfolio.TickerRenamedEventName {
-			oldSymbol := event.Payload["old"].(string)
-			newSymbol := event.Payload["new"].(string)
-			domainEvent := portfolio.NewTickerRenamedEvent(oldSymbol, newSymbol)
-			d.Apply(&domainEvent)
-			continue
+	storedEvents, err := repository.portfolioEventStream.Get()
+	if err != nil {
+		return dividend.Dividend{}, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return dividend.Dividend{}, err
 		}
+		d.Apply(domainEvent)
 	}
-	return d
+	return d, nil
 }
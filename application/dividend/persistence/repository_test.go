@@ -3,6 +3,8 @@ package persistence_test
 import (
 	"reflect"
 	"stock-monitor/application/dividend/persistence"
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
 	"stock-monitor/domain/dividend"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
@@ -52,10 +54,14 @@ func TestPortfolioEventsWillBeAppliedWhenLoadingDividend(t *testing.T) {
 	}
 	repository := persistence.NewEventSourcedDividendRepository(&eventStream)
 
-	d := repository.Load()
+	d, err := repository.Load()
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
 
 	expectedDividend := dividend.NewDividend()
-	event1 := portfolio.NewSharesAddedToPortfolioEvent("PG", 20, 10.00, "2000-01-01")
+	event1 := portfolio.NewSharesAddedToPortfolioEvent("PG", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(10.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	event2 := portfolio.NewTickerRenamedEvent("MO", "FOO")
 	expectedDividend.Apply(&event1)
 	expectedDividend.Apply(&event2)
@@ -64,3 +70,19 @@ func TestPortfolioEventsWillBeAppliedWhenLoadingDividend(t *testing.T) {
 		t.Errorf("Unexpected portfolio state. Expected:%#v Got:%#v", expectedDividend, d)
 	}
 }
+
+func TestDividendCanNotBeLoadedWhenAnEventCanNotBeDecoded(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}},
+		},
+	}
+	repository := persistence.NewEventSourcedDividendRepository(&eventStream)
+
+	_, err := repository.Load()
+
+	_, ok := err.(*event.UnknownEventError)
+	if !ok {
+		t.Errorf("Expected UnknownEventError but got %#v", err)
+	}
+}
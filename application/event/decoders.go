@@ -0,0 +1,480 @@
+package event
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	"strconv"
+)
+
+func NewDefaultEventRegistry() *EventRegistry {
+	registry := NewEventRegistry()
+
+	registry.Register(portfolio.SharesAddedToPortfolioEventName, portfolio.SharesAddedToPortfolioEventVersion, decodeSharesAddedToPortfolioEvent)
+	registry.Register(portfolio.SharesRemovedFromPortfolioEventName, portfolio.SharesRemovedFromPortfolioEventVersion, decodeSharesRemovedFromPortfolioEvent)
+	registry.Register(portfolio.TickerRenamedEventName, portfolio.TickerRenamedEventVersion, decodeTickerRenamedEvent)
+	registry.Register(portfolio.StockSplitEventName, portfolio.StockSplitEventVersion, decodeStockSplitEvent)
+	registry.Register(portfolio.SharesConvertedEventName, portfolio.SharesConvertedEventVersion, decodeSharesConvertedEvent)
+	registry.Register(portfolio.SharesSpunOffEventName, portfolio.SharesSpunOffEventVersion, decodeSharesSpunOffEvent)
+	registry.Register(dividend.DividendRecordedEventName, dividend.DividendRecordedEventVersion, decodeDividendRecordedEvent)
+	registry.Register(cash.CashDepositedEventName, cash.CashDepositedEventVersion, decodeCashDepositedEvent)
+	registry.Register(cash.CashWithdrawnEventName, cash.CashWithdrawnEventVersion, decodeCashWithdrawnEvent)
+	registry.Register(savings_plan.SavingsPlanDefinedEventName, savings_plan.SavingsPlanDefinedEventVersion, decodeSavingsPlanDefinedEvent)
+	registry.Register(savings_plan.OrderExecutedEventName, savings_plan.OrderExecutedEventVersion, decodeOrderExecutedEvent)
+
+	registry.RegisterUpcaster(portfolio.SharesAddedToPortfolioEventName, 1, convertSharesToDecimal)
+	registry.RegisterUpcaster(portfolio.SharesRemovedFromPortfolioEventName, 1, addDateFromOccurredAt)
+	registry.RegisterUpcaster(portfolio.SharesRemovedFromPortfolioEventName, 2, convertSharesToDecimal)
+	registry.RegisterUpcaster(portfolio.SharesAddedToPortfolioEventName, 2, convertAmountsToMoney("price"))
+	registry.RegisterUpcaster(portfolio.SharesRemovedFromPortfolioEventName, 3, convertAmountsToMoney("price"))
+	registry.RegisterUpcaster(portfolio.SharesRemovedFromPortfolioEventName, 4, addEmptyLots)
+	registry.RegisterUpcaster(portfolio.SharesAddedToPortfolioEventName, 3, addZeroCharges)
+	registry.RegisterUpcaster(portfolio.SharesRemovedFromPortfolioEventName, 5, addZeroCharges)
+	registry.RegisterUpcaster(dividend.DividendRecordedEventName, 1, convertAmountsToMoney("net", "gross"))
+
+	return registry
+}
+
+func decodeSharesAddedToPortfolioEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	shares, err := quantityValue(payload, "shares")
+	if err != nil {
+		return nil, err
+	}
+	price, err := moneyValue(payload, "price")
+	if err != nil {
+		return nil, err
+	}
+	fee, err := moneyValue(payload, "fee")
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := moneyValue(payload, "taxes")
+	if err != nil {
+		return nil, err
+	}
+	date, _ := stringValue(payload, "date")
+
+	event := portfolio.NewSharesAddedToPortfolioEvent(ticker, shares, price, fee, taxes, date)
+
+	return &event, nil
+}
+
+func decodeSharesRemovedFromPortfolioEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	shares, err := quantityValue(payload, "shares")
+	if err != nil {
+		return nil, err
+	}
+	price, err := moneyValue(payload, "price")
+	if err != nil {
+		return nil, err
+	}
+	fee, err := moneyValue(payload, "fee")
+	if err != nil {
+		return nil, err
+	}
+	taxes, err := moneyValue(payload, "taxes")
+	if err != nil {
+		return nil, err
+	}
+	date, _ := stringValue(payload, "date")
+	lots, err := selectedLotsValue(payload, "lots")
+	if err != nil {
+		return nil, err
+	}
+
+	event := portfolio.NewSharesRemovedFromPortfolioEvent(ticker, shares, price, fee, taxes, date, lots)
+
+	return &event, nil
+}
+
+func decodeTickerRenamedEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	old, err := stringValue(payload, "old")
+	if err != nil {
+		return nil, err
+	}
+	new, err := stringValue(payload, "new")
+	if err != nil {
+		return nil, err
+	}
+
+	event := portfolio.NewTickerRenamedEvent(old, new)
+
+	return &event, nil
+}
+
+func decodeStockSplitEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	ratioFrom, err := intValue(payload, "ratio_from")
+	if err != nil {
+		return nil, err
+	}
+	ratioTo, err := intValue(payload, "ratio_to")
+	if err != nil {
+		return nil, err
+	}
+	date, err := stringValue(payload, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	event := portfolio.NewStockSplitEvent(ticker, ratioFrom, ratioTo, date)
+
+	return &event, nil
+}
+
+func decodeSharesConvertedEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	newTicker, err := stringValue(payload, "new_ticker")
+	if err != nil {
+		return nil, err
+	}
+	ratioFrom, err := intValue(payload, "ratio_from")
+	if err != nil {
+		return nil, err
+	}
+	ratioTo, err := intValue(payload, "ratio_to")
+	if err != nil {
+		return nil, err
+	}
+	fractionalShares, err := quantityValue(payload, "fractional_shares")
+	if err != nil {
+		return nil, err
+	}
+	cashInLieu, err := moneyValue(payload, "cash_in_lieu")
+	if err != nil {
+		return nil, err
+	}
+	date, err := stringValue(payload, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	event := portfolio.NewSharesConvertedEvent(ticker, newTicker, ratioFrom, ratioTo, fractionalShares, cashInLieu, date)
+
+	return &event, nil
+}
+
+func decodeSharesSpunOffEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	newTicker, err := stringValue(payload, "new_ticker")
+	if err != nil {
+		return nil, err
+	}
+	ratioFrom, err := intValue(payload, "ratio_from")
+	if err != nil {
+		return nil, err
+	}
+	ratioTo, err := intValue(payload, "ratio_to")
+	if err != nil {
+		return nil, err
+	}
+	costBasisPercentage, err := percentageValue(payload, "cost_basis_percentage")
+	if err != nil {
+		return nil, err
+	}
+	date, err := stringValue(payload, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	event := portfolio.NewSharesSpunOffEvent(ticker, newTicker, ratioFrom, ratioTo, costBasisPercentage, date)
+
+	return &event, nil
+}
+
+func decodeDividendRecordedEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	net, err := moneyValue(payload, "net")
+	if err != nil {
+		return nil, err
+	}
+	gross, err := moneyValue(payload, "gross")
+	if err != nil {
+		return nil, err
+	}
+	date, err := stringValue(payload, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	event := dividend.NewDividendRecordedEvent(ticker, net, gross, date)
+
+	return &event, nil
+}
+
+func decodeCashDepositedEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	amount, err := moneyValue(payload, "amount")
+	if err != nil {
+		return nil, err
+	}
+	date, err := stringValue(payload, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	event := cash.NewCashDepositedEvent(amount, date)
+
+	return &event, nil
+}
+
+func decodeCashWithdrawnEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	amount, err := moneyValue(payload, "amount")
+	if err != nil {
+		return nil, err
+	}
+	date, err := stringValue(payload, "date")
+	if err != nil {
+		return nil, err
+	}
+
+	event := cash.NewCashWithdrawnEvent(amount, date)
+
+	return &event, nil
+}
+
+func decodeSavingsPlanDefinedEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	id, err := intValue(payload, "id")
+	if err != nil {
+		return nil, err
+	}
+	ticker, err := stringValue(payload, "ticker")
+	if err != nil {
+		return nil, err
+	}
+	amount, err := moneyValue(payload, "amount")
+	if err != nil {
+		return nil, err
+	}
+	shares, err := quantityValue(payload, "shares")
+	if err != nil {
+		return nil, err
+	}
+	interval, err := stringValue(payload, "interval")
+	if err != nil {
+		return nil, err
+	}
+	executionDay, err := intValue(payload, "execution_day")
+	if err != nil {
+		return nil, err
+	}
+	start, err := stringValue(payload, "start")
+	if err != nil {
+		return nil, err
+	}
+	end, err := stringValue(payload, "end")
+	if err != nil {
+		return nil, err
+	}
+
+	event := savings_plan.NewSavingsPlanDefinedEvent(id, ticker, amount, shares, savings_plan.Interval(interval), executionDay, start, end)
+
+	return &event, nil
+}
+
+func decodeOrderExecutedEvent(payload map[string]interface{}) (domain.DomainEvent, error) {
+	planId, err := intValue(payload, "plan_id")
+	if err != nil {
+		return nil, err
+	}
+	dueDate, err := stringValue(payload, "due_date")
+	if err != nil {
+		return nil, err
+	}
+	shares, err := quantityValue(payload, "shares")
+	if err != nil {
+		return nil, err
+	}
+	price, err := moneyValue(payload, "price")
+	if err != nil {
+		return nil, err
+	}
+
+	event := savings_plan.NewOrderExecutedEvent(planId, dueDate, shares, price)
+
+	return &event, nil
+}
+
+func addDateFromOccurredAt(event infrastructure.Event) infrastructure.Event {
+	payload := copyValues(event.Payload)
+	payload["date"], _ = stringValue(event.MetaData, "occurred_at")
+
+	return infrastructure.Event{event.Name, payload, event.MetaData}
+}
+
+// sells were not attributed to specific lots before lot selection was introduced
+func addEmptyLots(event infrastructure.Event) infrastructure.Event {
+	payload := copyValues(event.Payload)
+	payload["lots"] = ""
+
+	return infrastructure.Event{event.Name, payload, event.MetaData}
+}
+
+// orders were recorded without fees and taxes before charges were introduced
+func addZeroCharges(event infrastructure.Event) infrastructure.Event {
+	payload := copyValues(event.Payload)
+	payload["fee"] = "0"
+	payload["taxes"] = "0"
+
+	return infrastructure.Event{event.Name, payload, event.MetaData}
+}
+
+func convertSharesToDecimal(event infrastructure.Event) infrastructure.Event {
+	payload := copyValues(event.Payload)
+
+	switch shares := payload["shares"].(type) {
+	case int:
+		payload["shares"] = domain.NewQuantityFromInt(shares).String()
+	case int64:
+		payload["shares"] = domain.NewQuantityFromInt(int(shares)).String()
+	case float64:
+		payload["shares"] = domain.NewQuantityFromFloat(shares).String()
+	}
+
+	return infrastructure.Event{event.Name, payload, event.MetaData}
+}
+
+// amounts were stored as floats without a currency before money was introduced
+func convertAmountsToMoney(keys ...string) Upcaster {
+	return func(event infrastructure.Event) infrastructure.Event {
+		payload := copyValues(event.Payload)
+
+		for _, key := range keys {
+			switch amount := payload[key].(type) {
+			case float32:
+				payload[key] = domain.NewMoneyFromFloat32(amount, domain.DefaultCurrency).Amount()
+			case float64:
+				payload[key] = domain.NewMoneyFromFloat(amount, domain.DefaultCurrency).Amount()
+			case int:
+				payload[key] = domain.NewMoneyFromFloat(float64(amount), domain.DefaultCurrency).Amount()
+			}
+		}
+
+		_, found := payload["currency"]
+		if !found {
+			payload["currency"] = domain.DefaultCurrency
+		}
+
+		return infrastructure.Event{event.Name, payload, event.MetaData}
+	}
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := map[string]interface{}{}
+	for key, value := range values {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+func stringValue(values map[string]interface{}, key string) (string, error) {
+	value, ok := values[key].(string)
+	if !ok {
+		return "", NewInvalidPayloadValueError(key)
+	}
+
+	return value, nil
+}
+
+func quantityValue(values map[string]interface{}, key string) (domain.Quantity, error) {
+	value, err := stringValue(values, key)
+	if err != nil {
+		return domain.Quantity{}, err
+	}
+
+	quantity, err := domain.NewQuantity(value)
+	if err != nil {
+		return domain.Quantity{}, NewInvalidPayloadValueError(key)
+	}
+
+	return quantity, nil
+}
+
+func percentageValue(values map[string]interface{}, key string) (domain.Percentage, error) {
+	value, err := stringValue(values, key)
+	if err != nil {
+		return domain.Percentage{}, err
+	}
+
+	percentage, err := domain.NewPercentage(value)
+	if err != nil {
+		return domain.Percentage{}, NewInvalidPayloadValueError(key)
+	}
+
+	return percentage, nil
+}
+
+func moneyValue(values map[string]interface{}, key string) (domain.Money, error) {
+	amount, err := stringValue(values, key)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	currency, err := stringValue(values, "currency")
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	money, err := domain.NewMoney(amount, currency)
+	if err != nil {
+		return domain.Money{}, NewInvalidPayloadValueError(key)
+	}
+
+	return money, nil
+}
+
+func selectedLotsValue(values map[string]interface{}, key string) ([]portfolio.SelectedLot, error) {
+	value, err := stringValue(values, key)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := portfolio.DecodeSelectedLots(value)
+	if err != nil {
+		return nil, NewInvalidPayloadValueError(key)
+	}
+
+	return lots, nil
+}
+
+func intValue(values map[string]interface{}, key string) (int, error) {
+	switch value := values[key].(type) {
+	case int:
+		return value, nil
+	case int64:
+		return int(value), nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), nil
+		}
+	case string:
+		parsed, err := strconv.Atoi(value)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return 0, NewInvalidPayloadValueError(key)
+}
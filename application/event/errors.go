@@ -0,0 +1,40 @@
+package event
+
+import "strconv"
+
+type UnknownEventError struct {
+	name string
+}
+
+type MissingUpcasterError struct {
+	name        string
+	fromVersion int
+}
+
+type InvalidPayloadValueError struct {
+	key string
+}
+
+func NewUnknownEventError(name string) *UnknownEventError {
+	return &UnknownEventError{name: name}
+}
+
+func NewMissingUpcasterError(name string, fromVersion int) *MissingUpcasterError {
+	return &MissingUpcasterError{name: name, fromVersion: fromVersion}
+}
+
+func NewInvalidPayloadValueError(key string) *InvalidPayloadValueError {
+	return &InvalidPayloadValueError{key: key}
+}
+
+func (e *UnknownEventError) Error() string {
+	return "no decoder registered for event. name: " + e.name
+}
+
+func (e *MissingUpcasterError) Error() string {
+	return "no upcaster registered for event. name: " + e.name + " version: " + strconv.Itoa(e.fromVersion)
+}
+
+func (e *InvalidPayloadValueError) Error() string {
+	return "event payload value is missing or has an unexpected type. key: " + e.key
+}
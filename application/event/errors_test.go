@@ -0,0 +1,33 @@
+package event_test
+
+import (
+	"stock-monitor/application/event"
+	"testing"
+)
+
+func TestUnknownEventError(t *testing.T) {
+	err := event.NewUnknownEventError("Foo.Bar")
+	expected := "no decoder registered for event. name: Foo.Bar"
+	got := err.Error()
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestMissingUpcasterError(t *testing.T) {
+	err := event.NewMissingUpcasterError("Foo.Bar", 2)
+	expected := "no upcaster registered for event. name: Foo.Bar version: 2"
+	got := err.Error()
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidPayloadValueError(t *testing.T) {
+	err := event.NewInvalidPayloadValueError("shares")
+	expected := "event payload value is missing or has an unexpected type. key: shares"
+	got := err.Error()
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
@@ -9,22 +9,54 @@ type EventPublisher struct {
 	eventStream infrastructure.EventStream
 }
 
+// Publication are domain events for the event stream of the publisher, expected at the given version,
+// an empty OccurredAt falls back to the date passed to PublishAtomically
+type Publication struct {
+	Publisher       EventPublisher
+	Events          []domain.DomainEvent
+	ExpectedVersion int
+	OccurredAt      string
+}
+
 func NewEventPublisher(eventStream infrastructure.EventStream) EventPublisher {
 	return EventPublisher{eventStream: eventStream}
 }
 
 func (publisher *EventPublisher) PublishDomainEvents(events []domain.DomainEvent, occurredAt string) error {
+	return publisher.PublishDomainEventsAtVersion(events, occurredAt, infrastructure.AnyVersion)
+}
+
+func (publisher *EventPublisher) PublishDomainEventsAtVersion(events []domain.DomainEvent, occurredAt string, expectedVersion int) error {
+	return publisher.eventStream.AppendBatch(storedEvents(events, occurredAt), expectedVersion)
+}
+
+// PublishAtomically publishes the domain events of all publications or none of them
+func PublishAtomically(publications []Publication, occurredAt string) error {
+	batches := []infrastructure.StreamBatch{}
+	for _, publication := range publications {
+		publicationOccurredAt := publication.OccurredAt
+		if publicationOccurredAt == "" {
+			publicationOccurredAt = occurredAt
+		}
+		batches = append(batches, infrastructure.StreamBatch{
+			publication.Publisher.eventStream,
+			storedEvents(publication.Events, publicationOccurredAt),
+			publication.ExpectedVersion,
+		})
+	}
+
+	return infrastructure.AppendAtomically(batches)
+}
+
+func storedEvents(events []domain.DomainEvent, occurredAt string) []infrastructure.Event {
+	genericEvents := []infrastructure.Event{}
 	for _, event := range events {
-		genericEvent := infrastructure.Event{
+		genericEvents = append(genericEvents, infrastructure.Event{
 			event.Name(),
 			event.Payload(),
-			map[string]interface{}{"occurred_at": occurredAt},
-		}
-		err := publisher.eventStream.Add(genericEvent)
-		if err != nil {
-			return err
-		}
+			map[string]interface{}{"occurred_at": occurredAt, "version": event.Version()},
+		})
 	}
 
-	return nil
+	return genericEvents
 }
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"stock-monitor/application/event"
 	"stock-monitor/domain"
+	"stock-monitor/domain/dividend"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 	"testing"
@@ -12,9 +13,9 @@ import (
 func TestItPublishesMultipleDomainEvents(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 20, 9.99)
-	event2 := portfolio.NewSharesAddedToPortfolioEvent("PG", 20, 9.99, "2000-01-01")
-	event3 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 10, 9.99)
+	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
+	event2 := portfolio.NewSharesAddedToPortfolioEvent("PG", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	event3 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
 
 	publisher.PublishDomainEvents([]domain.DomainEvent{&event1, &event2, &event3}, "2000-01-01")
 
@@ -22,30 +23,43 @@ func TestItPublishesMultipleDomainEvents(t *testing.T) {
 		{
 			portfolio.SharesRemovedFromPortfolioEventName,
 			map[string]interface{}{
-				"ticker": "MO",
-				"shares": 20,
-				"price":  float32(9.99),
+				"ticker":   "MO",
+				"shares":   "20",
+				"price":    "9.99",
+				"fee":      "0",
+				"taxes":    "0",
+				"currency": "EUR",
+				"date":     "2000-01-01",
+				"lots":     "",
 			},
-			map[string]interface{}{"occurred_at": "2000-01-01"},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 6},
 		},
 		{
 			portfolio.SharesAddedToPortfolioEventName,
 			map[string]interface{}{
-				"ticker": "PG",
-				"shares": 20,
-				"price":  float32(9.99),
-				"date":   "2000-01-01",
+				"ticker":   "PG",
+				"shares":   "20",
+				"price":    "9.99",
+				"fee":      "0",
+				"taxes":    "0",
+				"currency": "EUR",
+				"date":     "2000-01-01",
 			},
-			map[string]interface{}{"occurred_at": "2000-01-01"},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 4},
 		},
 		{
 			portfolio.SharesRemovedFromPortfolioEventName,
 			map[string]interface{}{
-				"ticker": "MO",
-				"shares": 10,
-				"price":  float32(9.99),
+				"ticker":   "MO",
+				"shares":   "10",
+				"price":    "9.99",
+				"fee":      "0",
+				"taxes":    "0",
+				"currency": "EUR",
+				"date":     "2000-01-01",
+				"lots":     "",
 			},
-			map[string]interface{}{"occurred_at": "2000-01-01"},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 6},
 		},
 	}
 	got := eventStream.Events
@@ -58,7 +72,7 @@ func TestItPublishesMultipleDomainEvents(t *testing.T) {
 func TestItThrowsAnErrorIfAddingToEventStreamFails(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 20, 9.99)
+	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
 
 	err := publisher.PublishDomainEvents([]domain.DomainEvent{&event1}, "FOO")
 
@@ -66,3 +80,77 @@ func TestItThrowsAnErrorIfAddingToEventStreamFails(t *testing.T) {
 		t.Errorf("Expected Error but got none")
 	}
 }
+
+func TestItThrowsAnErrorIfEventStreamIsNotAtTheExpectedVersion(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{}
+	publisher := event.NewEventPublisher(&eventStream)
+	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
+	publisher.PublishDomainEvents([]domain.DomainEvent{&event1}, "2000-01-01")
+
+	err := publisher.PublishDomainEventsAtVersion([]domain.DomainEvent{&event1}, "2000-01-01", 0)
+
+	_, ok := err.(*infrastructure.ConcurrencyConflictError)
+	if !ok {
+		t.Errorf("Expected ConcurrencyConflictError but got %#v", err)
+	}
+}
+
+func TestItPublishesNoEventIfOneOfThemCanNotBeAddedToEventStream(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{}
+	publisher := event.NewEventPublisher(&eventStream)
+	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
+	event2 := portfolio.NewSharesAddedToPortfolioEvent("PG", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	event3 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
+	publisher.PublishDomainEvents([]domain.DomainEvent{&event1}, "2000-01-02")
+
+	err := publisher.PublishDomainEvents([]domain.DomainEvent{&event2, &event3}, "2000-01-01")
+
+	if err == nil {
+		t.Errorf("Expected Error but got none")
+	}
+	if len(eventStream.Events) != 1 {
+		t.Errorf("Expected no events to be published but got %#v", eventStream.Events[1:])
+	}
+}
+
+func TestItPublishesToNoEventStreamIfOneOfThemIsNotAtTheExpectedVersion(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	event1 := dividend.NewDividendRecordedEvent("MO", domain.NewMoneyFromFloat(1.5, "EUR"), domain.NewMoneyFromFloat(2.0, "EUR"), "2000-01-01")
+	event2 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(1.5, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+
+	err := event.PublishAtomically([]event.Publication{
+		{event.NewEventPublisher(&dividendEventStream), []domain.DomainEvent{&event1}, infrastructure.AnyVersion, ""},
+		{event.NewEventPublisher(&portfolioEventStream), []domain.DomainEvent{&event2}, 1, ""},
+	}, "2000-01-01")
+
+	_, ok := err.(*infrastructure.ConcurrencyConflictError)
+	if !ok {
+		t.Errorf("Expected ConcurrencyConflictError but got %#v", err)
+	}
+	if len(dividendEventStream.Events) != 0 || len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be published but got %#v and %#v", dividendEventStream.Events, portfolioEventStream.Events)
+	}
+}
+
+func TestEachPublicationCanOccurAtItsOwnDate(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	event1 := dividend.NewDividendRecordedEvent("MO", domain.NewMoneyFromFloat(1.5, "EUR"), domain.NewMoneyFromFloat(2.0, "EUR"), "2000-01-01")
+	event2 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(1.5, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+
+	err := event.PublishAtomically([]event.Publication{
+		{event.NewEventPublisher(&dividendEventStream), []domain.DomainEvent{&event1}, infrastructure.AnyVersion, "2000-01-05"},
+		{event.NewEventPublisher(&portfolioEventStream), []domain.DomainEvent{&event2}, infrastructure.AnyVersion, ""},
+	}, "2000-01-01")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if dividendEventStream.Events[0].MetaData["occurred_at"] != "2000-01-05" {
+		t.Errorf("Unexpected occurred at. Expected:%#v Got:%#v", "2000-01-05", dividendEventStream.Events[0].MetaData["occurred_at"])
+	}
+	if portfolioEventStream.Events[0].MetaData["occurred_at"] != "2000-01-01" {
+		t.Errorf("Unexpected occurred at. Expected:%#v Got:%#v", "2000-01-01", portfolioEventStream.Events[0].MetaData["occurred_at"])
+	}
+}
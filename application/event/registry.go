@@ -0,0 +1,84 @@
+package event
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type Decoder func(payload map[string]interface{}) (domain.DomainEvent, error)
+
+type Upcaster func(event infrastructure.Event) infrastructure.Event
+
+type versionedDecoder struct {
+	version int
+	decode  Decoder
+}
+
+type EventRegistry struct {
+	decoders  map[string]versionedDecoder
+	upcasters map[string]map[int]Upcaster
+}
+
+var DefaultRegistry = NewDefaultEventRegistry()
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{map[string]versionedDecoder{}, map[string]map[int]Upcaster{}}
+}
+
+func (registry *EventRegistry) Register(name string, version int, decoder Decoder) {
+	registry.decoders[name] = versionedDecoder{version, decoder}
+}
+
+func (registry *EventRegistry) RegisterUpcaster(name string, fromVersion int, upcaster Upcaster) {
+	_, found := registry.upcasters[name]
+	if !found {
+		registry.upcasters[name] = map[int]Upcaster{}
+	}
+	registry.upcasters[name][fromVersion] = upcaster
+}
+
+func (registry *EventRegistry) Decode(event infrastructure.Event) (domain.DomainEvent, error) {
+	decoder, found := registry.decoders[event.Name]
+	if !found {
+		return nil, NewUnknownEventError(event.Name)
+	}
+
+	version := StoredVersion(event)
+	for version < decoder.version {
+		upcaster, found := registry.upcasters[event.Name][version]
+		if !found {
+			return nil, NewMissingUpcasterError(event.Name, version)
+		}
+		event = upcaster(event)
+		version++
+	}
+
+	return decoder.decode(event.Payload)
+}
+
+// Upcast stores an event the way its current version is published, with the payload of the decoded domain event
+func (registry *EventRegistry) Upcast(event infrastructure.Event) (infrastructure.Event, error) {
+	domainEvent, err := registry.Decode(event)
+	if err != nil {
+		return infrastructure.Event{}, err
+	}
+
+	metaData := copyValues(event.MetaData)
+	metaData["version"] = domainEvent.Version()
+
+	return infrastructure.Event{domainEvent.Name(), domainEvent.Payload(), metaData}, nil
+}
+
+func Decode(event infrastructure.Event) (domain.DomainEvent, error) {
+	return DefaultRegistry.Decode(event)
+}
+
+// events stored before versioning was introduced carry no version and are treated as version 1
+func StoredVersion(event infrastructure.Event) int {
+	version, err := intValue(event.MetaData, "version")
+	if err != nil || version < 1 {
+		return 1
+	}
+
+	return version
+}
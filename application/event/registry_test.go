@@ -0,0 +1,312 @@
+package event_test
+
+import (
+	"reflect"
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func TestItDecodesStoredEventsIntoDomainEvents(t *testing.T) {
+	storedEvents := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "shares": 10, "price": float32(9.99), "date": "2000-01-01"},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "shares": 5, "price": float32(9.99), "date": "2000-01-02"},
+			map[string]interface{}{"occurred_at": "2000-01-02", "version": 2},
+		},
+		{
+			portfolio.TickerRenamedEventName,
+			map[string]interface{}{"old": "MO", "new": "FOO"},
+			map[string]interface{}{"occurred_at": "2000-01-03", "version": 1},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "FOO", "ratio_from": 1, "ratio_to": 4, "date": "2000-01-03"},
+			map[string]interface{}{"occurred_at": "2000-01-03", "version": 1},
+		},
+		{
+			dividend.DividendRecordedEventName,
+			map[string]interface{}{"ticker": "FOO", "net": float32(1.5), "gross": float32(2.0), "date": "2000-01-04"},
+			map[string]interface{}{"occurred_at": "2000-01-04", "version": 1},
+		},
+		{
+			cash.CashDepositedEventName,
+			map[string]interface{}{"amount": "1000", "currency": "EUR", "date": "2000-01-05"},
+			map[string]interface{}{"occurred_at": "2000-01-05", "version": 1},
+		},
+		{
+			cash.CashWithdrawnEventName,
+			map[string]interface{}{"amount": "500", "currency": "EUR", "date": "2000-01-06"},
+			map[string]interface{}{"occurred_at": "2000-01-06", "version": 1},
+		},
+		{
+			portfolio.SharesConvertedEventName,
+			map[string]interface{}{"ticker": "FOO", "new_ticker": "BAR", "ratio_from": 3, "ratio_to": 2, "fractional_shares": "0.5", "cash_in_lieu": "12.5", "currency": "USD", "date": "2000-01-07"},
+			map[string]interface{}{"occurred_at": "2000-01-07", "version": 1},
+		},
+		{
+			portfolio.SharesSpunOffEventName,
+			map[string]interface{}{"ticker": "BAR", "new_ticker": "BAZ", "ratio_from": 2, "ratio_to": 1, "cost_basis_percentage": "12.5", "date": "2000-01-08"},
+			map[string]interface{}{"occurred_at": "2000-01-08", "version": 1},
+		},
+		{
+			savings_plan.SavingsPlanDefinedEventName,
+			map[string]interface{}{"id": float64(1), "ticker": "BAZ", "amount": "100", "currency": "EUR", "shares": "0", "interval": "monthly", "execution_day": float64(15), "start": "2000-01-01", "end": ""},
+			map[string]interface{}{"occurred_at": "2000-01-09", "version": 1},
+		},
+		{
+			savings_plan.OrderExecutedEventName,
+			map[string]interface{}{"plan_id": float64(1), "due_date": "2000-01-15", "shares": "4", "price": "25", "currency": "EUR"},
+			map[string]interface{}{"occurred_at": "2000-01-15", "version": 1},
+		},
+	}
+
+	event1 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	event2 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", []portfolio.SelectedLot{})
+	event3 := portfolio.NewTickerRenamedEvent("MO", "FOO")
+	event4 := portfolio.NewStockSplitEvent("FOO", 1, 4, "2000-01-03")
+	event5 := dividend.NewDividendRecordedEvent("FOO", domain.NewMoneyFromFloat(1.5, "EUR"), domain.NewMoneyFromFloat(2.0, "EUR"), "2000-01-04")
+	event6 := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-05")
+	event7 := cash.NewCashWithdrawnEvent(domain.NewMoneyFromFloat(500, "EUR"), "2000-01-06")
+	fractionalShares, _ := domain.NewQuantity("0.5")
+	event8 := portfolio.NewSharesConvertedEvent("FOO", "BAR", 3, 2, fractionalShares, domain.NewMoneyFromFloat(12.5, "USD"), "2000-01-07")
+	costBasisPercentage, _ := domain.NewPercentage("12.5")
+	event9 := portfolio.NewSharesSpunOffEvent("BAR", "BAZ", 2, 1, costBasisPercentage, "2000-01-08")
+	event10 := savings_plan.NewSavingsPlanDefinedEvent(1, "BAZ", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "")
+	event11 := savings_plan.NewOrderExecutedEvent(1, "2000-01-15", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(25, "EUR"))
+	want := []domain.DomainEvent{&event1, &event2, &event3, &event4, &event5, &event6, &event7, &event8, &event9, &event10, &event11}
+
+	got := []domain.DomainEvent{}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+		got = append(got, domainEvent)
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected domain events. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestItDecodesNumbersOfAnyStoredType(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": float64(10), "price": 9.99, "date": "2000-01-01"},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": float64(1)},
+	}
+
+	got, err := event.Decode(storedEvent)
+
+	want := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+}
+
+func TestItUpcastsIntegerSharesToQuantities(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": 10, "price": float32(9.99), "date": "2000-01-01"},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+	}
+
+	got, err := event.Decode(storedEvent)
+
+	want := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+}
+
+func TestItUpcastsFloatDividendAmountsToMoney(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		dividend.DividendRecordedEventName,
+		map[string]interface{}{"ticker": "MO", "net": float32(0.1), "gross": 0.2, "date": "2000-01-01"},
+		map[string]interface{}{"occurred_at": "2000-01-01"},
+	}
+
+	got, err := event.Decode(storedEvent)
+
+	want := dividend.NewDividendRecordedEvent("MO", domain.NewMoneyFromFloat(0.1, "EUR"), domain.NewMoneyFromFloat(0.2, "EUR"), "2000-01-01")
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+}
+
+func TestItUpcastsSharesRemovedEventsWithoutDate(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesRemovedFromPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": 5, "price": float32(9.99)},
+		map[string]interface{}{"occurred_at": "2000-01-02"},
+	}
+
+	got, err := event.Decode(storedEvent)
+
+	want := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", []portfolio.SelectedLot{})
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+	if _, found := storedEvent.Payload["date"]; found {
+		t.Errorf("Expected stored event to be left untouched but got %#v", storedEvent.Payload)
+	}
+}
+
+func TestItDecodesSelectedLotsOfSharesRemovedEvents(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesRemovedFromPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": "5", "price": "9.99", "currency": "EUR", "date": "2000-01-02", "lots": "1:2,3:3"},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 5},
+	}
+
+	got, err := event.Decode(storedEvent)
+
+	want := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", []portfolio.SelectedLot{portfolio.NewSelectedLot(1, domain.NewQuantityFromInt(2)), portfolio.NewSelectedLot(3, domain.NewQuantityFromInt(3))})
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+}
+
+func TestItDecodesFeesAndTaxesOfOrders(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": "5", "price": "9.99", "fee": "4.95", "taxes": "0.12", "currency": "USD", "date": "2000-01-02"},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 4},
+	}
+
+	got, err := event.Decode(storedEvent)
+
+	want := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(9.99, "USD"), domain.NewMoneyFromFloat(4.95, "USD"), domain.NewMoneyFromFloat(0.12, "USD"), "2000-01-02")
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+}
+
+func TestItAppliesUpcastersInOrder(t *testing.T) {
+	registry := event.NewEventRegistry()
+	registry.Register("Test.Event", 3, func(payload map[string]interface{}) (domain.DomainEvent, error) {
+		domainEvent := portfolio.NewTickerRenamedEvent(payload["old"].(string), payload["new"].(string))
+		return &domainEvent, nil
+	})
+	registry.RegisterUpcaster("Test.Event", 2, func(e infrastructure.Event) infrastructure.Event {
+		return infrastructure.Event{e.Name, map[string]interface{}{"old": e.Payload["old"], "new": "BAR"}, e.MetaData}
+	})
+	registry.RegisterUpcaster("Test.Event", 1, func(e infrastructure.Event) infrastructure.Event {
+		return infrastructure.Event{e.Name, map[string]interface{}{"old": e.Payload["symbol"]}, e.MetaData}
+	})
+
+	got, err := registry.Decode(infrastructure.Event{"Test.Event", map[string]interface{}{"symbol": "FOO"}, map[string]interface{}{}})
+
+	want := portfolio.NewTickerRenamedEvent("FOO", "BAR")
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, &want) == false {
+		t.Errorf("Unexpected domain event. Expected:%#v Got:%#v", &want, got)
+	}
+}
+
+func TestItUpcastsStoredEventsToThePayloadOfTheirCurrentVersion(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": 10, "price": float32(9.99), "date": "2000-01-01"},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+	}
+
+	got, err := event.DefaultRegistry.Upcast(storedEvent)
+
+	domainEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	want := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		domainEvent.Payload(),
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": portfolio.SharesAddedToPortfolioEventVersion},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected stored event. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestItFailsForUnknownEvents(t *testing.T) {
+	_, err := event.Decode(infrastructure.Event{"Unknown", map[string]interface{}{}, map[string]interface{}{}})
+
+	_, ok := err.(*event.UnknownEventError)
+	if !ok {
+		t.Errorf("Expected UnknownEventError but got %#v", err)
+	}
+}
+
+func TestItFailsWhenUpcasterIsMissing(t *testing.T) {
+	registry := event.NewEventRegistry()
+	registry.Register("Test.Event", 2, func(payload map[string]interface{}) (domain.DomainEvent, error) {
+		return nil, nil
+	})
+
+	_, err := registry.Decode(infrastructure.Event{"Test.Event", map[string]interface{}{}, map[string]interface{}{}})
+
+	_, ok := err.(*event.MissingUpcasterError)
+	if !ok {
+		t.Errorf("Expected MissingUpcasterError but got %#v", err)
+	}
+}
+
+func TestItFailsForInvalidPayloadValues(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": "many", "price": 9.99},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 2},
+	}
+
+	_, err := event.Decode(storedEvent)
+
+	_, ok := err.(*event.InvalidPayloadValueError)
+	if !ok {
+		t.Errorf("Expected InvalidPayloadValueError but got %#v", err)
+	}
+}
+
+func TestItFailsForInvalidSelectedLots(t *testing.T) {
+	storedEvent := infrastructure.Event{
+		portfolio.SharesRemovedFromPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": "5", "price": "9.99", "currency": "EUR", "date": "2000-01-02", "lots": "first:5"},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 5},
+	}
+
+	_, err := event.Decode(storedEvent)
+
+	_, ok := err.(*event.InvalidPayloadValueError)
+	if !ok {
+		t.Errorf("Expected InvalidPayloadValueError but got %#v", err)
+	}
+}
@@ -2,42 +2,98 @@ package command
 
 import (
 	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
 )
 
 type AddSharesToPortfolioCommand struct {
+	PortfolioId    string
 	Ticker         string
-	NumberOfShares int
-	Price          float32
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fee            domain.Money
+	Taxes          domain.Money
 	Date           string
 }
 
-func NewAddSharesToPortfolioCommand(ticker string, numberOfShares int, price float32, date shared.CommandDate) AddSharesToPortfolioCommand {
-	command := AddSharesToPortfolioCommand{ticker, numberOfShares, price, date.Get()}
+func NewAddSharesToPortfolioCommand(portfolioId shared.PortfolioId, ticker string, numberOfShares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date shared.CommandDate) AddSharesToPortfolioCommand {
+	command := AddSharesToPortfolioCommand{portfolioId.Get(), ticker, numberOfShares, price, fee, taxes, date.Get()}
 
 	return command
 }
 
 type RemoveSharesFromPortfolioCommand struct {
+	PortfolioId    string
 	Ticker         string
-	NumberOfShares int
-	Price          float32
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fee            domain.Money
+	Taxes          domain.Money
 	Date           string
+	Lots           []portfolio.LotSelection
 }
 
-func NewRemoveSharesFromPortfolioCommand(ticker string, numberOfShares int, price float32, date shared.CommandDate) RemoveSharesFromPortfolioCommand {
-	command := RemoveSharesFromPortfolioCommand{ticker, numberOfShares, price, date.Get()}
+func NewRemoveSharesFromPortfolioCommand(portfolioId shared.PortfolioId, ticker string, numberOfShares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date shared.CommandDate, lots []portfolio.LotSelection) RemoveSharesFromPortfolioCommand {
+	command := RemoveSharesFromPortfolioCommand{portfolioId.Get(), ticker, numberOfShares, price, fee, taxes, date.Get(), lots}
 
 	return command
 }
 
 type RenameTickerCommand struct {
-	Old  string
-	New  string
-	Date string
+	PortfolioId string
+	Old         string
+	New         string
+	Date        string
 }
 
-func NewRenameTickerCommand(old string, new string, date shared.CommandDate) RenameTickerCommand {
-	command := RenameTickerCommand{old, new, date.Get()}
+func NewRenameTickerCommand(portfolioId shared.PortfolioId, old string, new string, date shared.CommandDate) RenameTickerCommand {
+	command := RenameTickerCommand{portfolioId.Get(), old, new, date.Get()}
+
+	return command
+}
+
+type SplitStockCommand struct {
+	PortfolioId string
+	Ticker      string
+	RatioFrom   int
+	RatioTo     int
+	Date        string
+}
+
+func NewSplitStockCommand(portfolioId shared.PortfolioId, ticker string, ratioFrom int, ratioTo int, date shared.CommandDate) SplitStockCommand {
+	command := SplitStockCommand{portfolioId.Get(), ticker, ratioFrom, ratioTo, date.Get()}
+
+	return command
+}
+
+type ConvertSharesCommand struct {
+	PortfolioId string
+	Ticker      string
+	NewTicker   string
+	RatioFrom   int
+	RatioTo     int
+	CashInLieu  domain.Money
+	Date        string
+}
+
+func NewConvertSharesCommand(portfolioId shared.PortfolioId, ticker string, newTicker string, ratioFrom int, ratioTo int, cashInLieu domain.Money, date shared.CommandDate) ConvertSharesCommand {
+	command := ConvertSharesCommand{portfolioId.Get(), ticker, newTicker, ratioFrom, ratioTo, cashInLieu, date.Get()}
+
+	return command
+}
+
+type SpinOffCommand struct {
+	PortfolioId         string
+	Ticker              string
+	NewTicker           string
+	RatioFrom           int
+	RatioTo             int
+	CostBasisPercentage domain.Percentage
+	Date                string
+}
+
+func NewSpinOffCommand(portfolioId shared.PortfolioId, ticker string, newTicker string, ratioFrom int, ratioTo int, costBasisPercentage domain.Percentage, date shared.CommandDate) SpinOffCommand {
+	command := SpinOffCommand{portfolioId.Get(), ticker, newTicker, ratioFrom, ratioTo, costBasisPercentage, date.Get()}
 
 	return command
 }
@@ -3,12 +3,13 @@ package command_test
 import (
 	"reflect"
 	"stock-monitor/application/portfolio/command"
+	"stock-monitor/domain"
 	"testing"
 )
 
 func TestNewAddSharesToPortfolioCommand(t *testing.T) {
-	addSharesToPortfolioCommand := command.NewAddSharesToPortfolioCommand("MO", 20, 19.99, "2001-01-02")
-	expected := command.AddSharesToPortfolioCommand{"MO", 20, 19.99, "2001-01-02"}
+	addSharesToPortfolioCommand := command.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(19.99, "EUR"), domain.NewMoneyFromFloat(4.95, "EUR"), domain.NewMoneyFromFloat(0.5, "EUR"), "2001-01-02")
+	expected := command.AddSharesToPortfolioCommand{"default", "MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(19.99, "EUR"), domain.NewMoneyFromFloat(4.95, "EUR"), domain.NewMoneyFromFloat(0.5, "EUR"), "2001-01-02"}
 
 	if reflect.DeepEqual(addSharesToPortfolioCommand, expected) == false {
 		t.Errorf("Unexpected command. got: %#v, want: %#v", addSharesToPortfolioCommand, expected)
@@ -16,8 +17,8 @@ func TestNewAddSharesToPortfolioCommand(t *testing.T) {
 }
 
 func TestRemoveSharesFromPortfolioCommand(t *testing.T) {
-	removeSharesFromPortfolioCommand := command.NewRemoveSharesFromPortfolioCommand("MO", 20, 19.99, "2001-01-02")
-	expected := command.RemoveSharesFromPortfolioCommand{"MO", 20, 19.99, "2001-01-02"}
+	removeSharesFromPortfolioCommand := command.NewRemoveSharesFromPortfolioCommand("default", "MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(19.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2001-01-02", nil)
+	expected := command.RemoveSharesFromPortfolioCommand{"default", "MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(19.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2001-01-02", nil}
 
 	if reflect.DeepEqual(removeSharesFromPortfolioCommand, expected) == false {
 		t.Errorf("Unexpected command. got: %#v, want: %#v", removeSharesFromPortfolioCommand, expected)
@@ -25,10 +26,38 @@ func TestRemoveSharesFromPortfolioCommand(t *testing.T) {
 }
 
 func TestRenameTickerCommandHasTodayAsDefaultDate(t *testing.T) {
-	renameCommand := command.NewRenameTickerCommand("MO", "FOO", "2001-01-02")
-	expected := command.RenameTickerCommand{"MO", "FOO", "2001-01-02"}
+	renameCommand := command.NewRenameTickerCommand("default", "MO", "FOO", "2001-01-02")
+	expected := command.RenameTickerCommand{"default", "MO", "FOO", "2001-01-02"}
 
 	if reflect.DeepEqual(renameCommand, expected) == false {
 		t.Errorf("Unexpected command. got: %#v, want: %#v", renameCommand, expected)
 	}
 }
+
+func TestSplitStockCommand(t *testing.T) {
+	splitCommand := command.NewSplitStockCommand("default", "MO", 1, 4, "2001-01-02")
+	expected := command.SplitStockCommand{"default", "MO", 1, 4, "2001-01-02"}
+
+	if reflect.DeepEqual(splitCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", splitCommand, expected)
+	}
+}
+
+func TestConvertSharesCommand(t *testing.T) {
+	convertCommand := command.NewConvertSharesCommand("default", "MO", "PM", 3, 2, domain.NewMoneyFromFloat(12.5, "USD"), "2001-01-02")
+	expected := command.ConvertSharesCommand{"default", "MO", "PM", 3, 2, domain.NewMoneyFromFloat(12.5, "USD"), "2001-01-02"}
+
+	if reflect.DeepEqual(convertCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", convertCommand, expected)
+	}
+}
+
+func TestSpinOffCommand(t *testing.T) {
+	costBasisPercentage, _ := domain.NewPercentage("12.5")
+	spinOffCommand := command.NewSpinOffCommand("default", "MO", "PM", 2, 1, costBasisPercentage, "2001-01-02")
+	expected := command.SpinOffCommand{"default", "MO", "PM", 2, 1, costBasisPercentage, "2001-01-02"}
+
+	if reflect.DeepEqual(spinOffCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", spinOffCommand, expected)
+	}
+}
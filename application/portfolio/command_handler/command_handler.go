@@ -4,73 +4,124 @@ import (
 	"stock-monitor/application/event"
 	"stock-monitor/application/portfolio/command"
 	"stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
 )
 
+const maxConcurrencyConflictRetries = 3
+
 type PortfolioCommandHandlerInterface interface {
 	HandleAddSharesToPortfolio(command command.AddSharesToPortfolioCommand) error
 	HandleRemoveSharesFromPortfolio(command command.RemoveSharesFromPortfolioCommand) error
 	HandleRenameTicker(command command.RenameTickerCommand) error
+	HandleSplitStock(command command.SplitStockCommand) error
+	HandleConvertShares(command command.ConvertSharesCommand) error
+	HandleSpinOff(command command.SpinOffCommand) error
 }
 
+// BuyGuard checks a buy against state loaded from other event streams and returns publications pinning these streams
+// at the versions the check was based on, the buy is only published while none of them changed
+type BuyGuard func(command command.AddSharesToPortfolioCommand) ([]event.Publication, error)
+
 type CommandHandler struct {
 	repository persistence.PortfolioRepository
 	publisher  event.EventPublisher
+	buyGuard   BuyGuard
 }
 
 func NewCommandHandler(repository persistence.PortfolioRepository, publisher event.EventPublisher) PortfolioCommandHandlerInterface {
-	return &CommandHandler{repository: repository, publisher: publisher}
+	return NewGuardedCommandHandler(repository, publisher, func(command command.AddSharesToPortfolioCommand) ([]event.Publication, error) {
+		return []event.Publication{}, nil
+	})
 }
 
-func (commandHandler *CommandHandler) HandleAddSharesToPortfolio(command command.AddSharesToPortfolioCommand) error {
-	p := commandHandler.repository.Load()
-
-	err := p.AddSharesToPortfolio(command.Ticker, command.NumberOfShares, command.Price, command.Date)
-
-	if err != nil {
-		return err
-	}
-
-	err = commandHandler.publisher.PublishDomainEvents(p.GetRecordedEvents(), command.Date)
-
-	if err != nil {
-		return err
-	}
+func NewGuardedCommandHandler(repository persistence.PortfolioRepository, publisher event.EventPublisher, buyGuard BuyGuard) PortfolioCommandHandlerInterface {
+	return &CommandHandler{repository: repository, publisher: publisher, buyGuard: buyGuard}
+}
 
-	return nil
+func (commandHandler *CommandHandler) HandleAddSharesToPortfolio(command command.AddSharesToPortfolioCommand) error {
+	return commandHandler.handleGuarded(command.Date, func(p *portfolio.Portfolio) error {
+		return p.AddSharesToPortfolio(command.Ticker, command.NumberOfShares, command.Price, command.Fee, command.Taxes, command.Date)
+	}, func() ([]event.Publication, error) {
+		return commandHandler.buyGuard(command)
+	})
 }
 
 func (commandHandler *CommandHandler) HandleRemoveSharesFromPortfolio(command command.RemoveSharesFromPortfolioCommand) error {
-	p := commandHandler.repository.Load()
-
-	err := p.RemoveSharesFromPortfolio(command.Ticker, command.NumberOfShares, command.Price)
-
-	if err != nil {
-		return err
-	}
-
-	err = commandHandler.publisher.PublishDomainEvents(p.GetRecordedEvents(), command.Date)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return commandHandler.handle(command.Date, func(p *portfolio.Portfolio) error {
+		return p.RemoveSharesFromPortfolio(command.Ticker, command.NumberOfShares, command.Price, command.Fee, command.Taxes, command.Date, command.Lots)
+	})
 }
 
 func (commandHandler *CommandHandler) HandleRenameTicker(command command.RenameTickerCommand) error {
-	p := commandHandler.repository.Load()
+	return commandHandler.handle(command.Date, func(p *portfolio.Portfolio) error {
+		return p.RenameTicker(command.Old, command.New)
+	})
+}
 
-	err := p.RenameTicker(command.Old, command.New)
+func (commandHandler *CommandHandler) HandleSplitStock(command command.SplitStockCommand) error {
+	return commandHandler.handle(command.Date, func(p *portfolio.Portfolio) error {
+		return p.SplitStock(command.Ticker, command.RatioFrom, command.RatioTo, command.Date)
+	})
+}
 
-	if err != nil {
-		return err
-	}
+func (commandHandler *CommandHandler) HandleConvertShares(command command.ConvertSharesCommand) error {
+	return commandHandler.handle(command.Date, func(p *portfolio.Portfolio) error {
+		return p.ConvertShares(command.Ticker, command.NewTicker, command.RatioFrom, command.RatioTo, command.CashInLieu, command.Date)
+	})
+}
 
-	err = commandHandler.publisher.PublishDomainEvents(p.GetRecordedEvents(), command.Date)
+func (commandHandler *CommandHandler) HandleSpinOff(command command.SpinOffCommand) error {
+	return commandHandler.handle(command.Date, func(p *portfolio.Portfolio) error {
+		return p.SpinOff(command.Ticker, command.NewTicker, command.RatioFrom, command.RatioTo, command.CostBasisPercentage, command.Date)
+	})
+}
+
+func (commandHandler *CommandHandler) handle(date string, execute func(p *portfolio.Portfolio) error) error {
+	return commandHandler.handleGuarded(date, execute, func() ([]event.Publication, error) {
+		return []event.Publication{}, nil
+	})
+}
 
-	if err != nil {
-		return err
+// handleGuarded runs the guard on every attempt and publishes the events together with its pins
+func (commandHandler *CommandHandler) handleGuarded(date string, execute func(p *portfolio.Portfolio) error, guard func() ([]event.Publication, error)) error {
+	var err error
+	for attempt := 0; attempt < maxConcurrencyConflictRetries; attempt++ {
+		var version int
+		version, err = commandHandler.repository.Version()
+		if err != nil {
+			return err
+		}
+		var p portfolio.Portfolio
+		p, err = commandHandler.repository.Load()
+		if err != nil {
+			return err
+		}
+
+		err = execute(&p)
+
+		if err != nil {
+			return err
+		}
+
+		var pins []event.Publication
+		pins, err = guard()
+		if err != nil {
+			return err
+		}
+
+		if len(pins) == 0 {
+			err = commandHandler.publisher.PublishDomainEventsAtVersion(p.GetRecordedEvents(), date, version)
+		} else {
+			// the pins go first, a pin of the portfolio stream then conflicts with a changed portfolio version
+			err = event.PublishAtomically(append(pins, event.Publication{commandHandler.publisher, p.GetRecordedEvents(), version, ""}), date)
+		}
+
+		_, conflict := err.(*infrastructure.ConcurrencyConflictError)
+		if !conflict {
+			return err
+		}
 	}
 
-	return nil
+	return err
 }
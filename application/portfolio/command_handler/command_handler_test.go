@@ -6,15 +6,17 @@ import (
 	"stock-monitor/application/portfolio/command"
 	"stock-monitor/application/portfolio/command_handler"
 	"stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
+	"sync"
 	"testing"
 )
 
 func TestItHandlesAddSharesToPortfolioCommand(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	addSharesCommand := command.NewAddSharesToPortfolioCommand("MO", 10, 9.99, "2000-01-01")
+	addSharesCommand := command.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -23,12 +25,15 @@ func TestItHandlesAddSharesToPortfolioCommand(t *testing.T) {
 	expectedEvent := infrastructure.Event{
 		portfolio.SharesAddedToPortfolioEventName,
 		map[string]interface{}{
-			"ticker": "MO",
-			"shares": 10,
-			"price":  float32(9.99),
-			"date":   "2000-01-01",
+			"ticker":   "MO",
+			"shares":   "10",
+			"price":    "9.99",
+			"fee":      "0",
+			"taxes":    "0",
+			"currency": "EUR",
+			"date":     "2000-01-01",
 		},
-		map[string]interface{}{"occurred_at": "2000-01-01"},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 4},
 	}
 	got := eventStream.Events[0]
 
@@ -40,7 +45,7 @@ func TestItHandlesAddSharesToPortfolioCommand(t *testing.T) {
 func TestItReturnsErrorWhenAddSharesToPortfolioCommandFails(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	addSharesCommand := command.NewAddSharesToPortfolioCommand("MO", 0, 9.99, "2001-01-01")
+	addSharesCommand := command.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(0), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2001-01-01")
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -54,7 +59,7 @@ func TestItReturnsErrorWhenAddSharesToPortfolioCommandFails(t *testing.T) {
 func TestItReturnsErrorWhenPublishingEventAfterAddSharesCommandFails(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	addSharesCommand := command.NewAddSharesToPortfolioCommand("MO", 1, 9.99, "FOO")
+	addSharesCommand := command.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "FOO")
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -81,7 +86,7 @@ func TestSharesRemovedFromPortfolioEventCanBePublished(t *testing.T) {
 		},
 	}
 	publisher := event.NewEventPublisher(&eventStream)
-	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand("MO", 10, 9.99, "2000-01-02")
+	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand("default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", nil)
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -90,11 +95,16 @@ func TestSharesRemovedFromPortfolioEventCanBePublished(t *testing.T) {
 	expectedEvent := infrastructure.Event{
 		portfolio.SharesRemovedFromPortfolioEventName,
 		map[string]interface{}{
-			"ticker": "MO",
-			"shares": 10,
-			"price":  float32(9.99),
+			"ticker":   "MO",
+			"shares":   "10",
+			"price":    "9.99",
+			"fee":      "0",
+			"taxes":    "0",
+			"currency": "EUR",
+			"date":     "2000-01-02",
+			"lots":     "",
 		},
-		map[string]interface{}{"occurred_at": "2000-01-02"},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 6},
 	}
 	got := eventStream.Events[1]
 
@@ -119,7 +129,7 @@ func TestItReturnsErrorWhenPublishingEventAfterRemoveSharesCommandFails(t *testi
 		},
 	}
 	publisher := event.NewEventPublisher(&eventStream)
-	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand("MO", 10, 9.99, "FOO")
+	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand("default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "FOO", nil)
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -133,7 +143,7 @@ func TestItReturnsErrorWhenPublishingEventAfterRemoveSharesCommandFails(t *testi
 func TestItReturnsErrorWhenRemoveSharesFromPortfolioCommandFails(t *testing.T) {
 	eventStream := infrastructure.InMemoryEventStream{}
 	publisher := event.NewEventPublisher(&eventStream)
-	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand("MO", 10, 9.99, "")
+	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand("default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "", nil)
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -160,7 +170,7 @@ func TestRenameTickerCommandIsHandled(t *testing.T) {
 		},
 	}
 	publisher := event.NewEventPublisher(&eventStream)
-	renameTickerCommand := command.NewRenameTickerCommand("MO", "FOO", "2000-01-02")
+	renameTickerCommand := command.NewRenameTickerCommand("default", "MO", "FOO", "2000-01-02")
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -172,7 +182,131 @@ func TestRenameTickerCommandIsHandled(t *testing.T) {
 			"old": "MO",
 			"new": "FOO",
 		},
-		map[string]interface{}{"occurred_at": "2000-01-02"},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 1},
+	}
+	got := eventStream.Events[1]
+
+	if reflect.DeepEqual(got, expectedEvent) == false {
+		t.Errorf("Unexpected event published. Expected:%#v Got:%#v", expectedEvent, got)
+	}
+}
+
+func TestSplitStockCommandIsHandled(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{
+					"ticker": "MO",
+					"shares": 20,
+					"price":  10.00,
+					"date":   "2000-01-01",
+				},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			},
+		},
+	}
+	publisher := event.NewEventPublisher(&eventStream)
+	splitStockCommand := command.NewSplitStockCommand("default", "MO", 1, 4, "2000-01-02")
+	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
+
+	commandHandler.HandleSplitStock(splitStockCommand)
+
+	expectedEvent := infrastructure.Event{
+		portfolio.StockSplitEventName,
+		map[string]interface{}{
+			"ticker":     "MO",
+			"ratio_from": 1,
+			"ratio_to":   4,
+			"date":       "2000-01-02",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 1},
+	}
+	got := eventStream.Events[1]
+
+	if reflect.DeepEqual(got, expectedEvent) == false {
+		t.Errorf("Unexpected event published. Expected:%#v Got:%#v", expectedEvent, got)
+	}
+}
+
+func TestConvertSharesCommandIsHandled(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{
+					"ticker": "MO",
+					"shares": 20,
+					"price":  10.00,
+					"date":   "2000-01-01",
+				},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			},
+		},
+	}
+	publisher := event.NewEventPublisher(&eventStream)
+	convertSharesCommand := command.NewConvertSharesCommand("default", "MO", "PM", 3, 2, domain.NewMoneyFromFloat(5, "EUR"), "2000-01-02")
+	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
+
+	commandHandler.HandleConvertShares(convertSharesCommand)
+
+	expectedEvent := infrastructure.Event{
+		portfolio.SharesConvertedEventName,
+		map[string]interface{}{
+			"ticker":            "MO",
+			"new_ticker":        "PM",
+			"ratio_from":        3,
+			"ratio_to":          2,
+			"fractional_shares": "0.33333333",
+			"cash_in_lieu":      "5",
+			"currency":          "EUR",
+			"date":              "2000-01-02",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 1},
+	}
+	got := eventStream.Events[1]
+
+	if reflect.DeepEqual(got, expectedEvent) == false {
+		t.Errorf("Unexpected event published. Expected:%#v Got:%#v", expectedEvent, got)
+	}
+}
+
+func TestSpinOffCommandIsHandled(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{
+					"ticker": "MO",
+					"shares": 20,
+					"price":  10.00,
+					"date":   "2000-01-01",
+				},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			},
+		},
+	}
+	publisher := event.NewEventPublisher(&eventStream)
+	costBasisPercentage, _ := domain.NewPercentage("12.5")
+	spinOffCommand := command.NewSpinOffCommand("default", "MO", "PM", 2, 1, costBasisPercentage, "2000-01-02")
+	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
+
+	commandHandler.HandleSpinOff(spinOffCommand)
+
+	expectedEvent := infrastructure.Event{
+		portfolio.SharesSpunOffEventName,
+		map[string]interface{}{
+			"ticker":                "MO",
+			"new_ticker":            "PM",
+			"ratio_from":            2,
+			"ratio_to":              1,
+			"cost_basis_percentage": "12.5",
+			"date":                  "2000-01-02",
+		},
+		map[string]interface{}{"occurred_at": "2000-01-02", "version": 1},
 	}
 	got := eventStream.Events[1]
 
@@ -197,7 +331,7 @@ func TestItReturnsErrorWhenRenameTickerCommandFails(t *testing.T) {
 		},
 	}
 	publisher := event.NewEventPublisher(&eventStream)
-	renameTickerCommand := command.NewRenameTickerCommand("FOO", "BAR", "2000-01-02")
+	renameTickerCommand := command.NewRenameTickerCommand("default", "FOO", "BAR", "2000-01-02")
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -224,7 +358,7 @@ func TestItReturnsErrorWhenPublishingEventAfterRenameTickerCommandFails(t *testi
 		},
 	}
 	publisher := event.NewEventPublisher(&eventStream)
-	renameTickerCommand := command.NewRenameTickerCommand("MO", "BAR", "FOO")
+	renameTickerCommand := command.NewRenameTickerCommand("default", "MO", "BAR", "FOO")
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
 
@@ -234,3 +368,65 @@ func TestItReturnsErrorWhenPublishingEventAfterRenameTickerCommandFails(t *testi
 		t.Errorf("Expected Error but got none")
 	}
 }
+
+func TestConcurrentRemoveSharesCommandsCanNotSellMoreSharesThanExisting(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{
+					"ticker": "MO",
+					"shares": 10,
+					"price":  10.00,
+					"date":   "2000-01-01",
+				},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			},
+		},
+	}
+	publisher := event.NewEventPublisher(&eventStream)
+	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewCommandHandler(&repository, publisher)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			commandHandler.HandleRemoveSharesFromPortfolio(command.NewRemoveSharesFromPortfolioCommand("default", "MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", nil))
+		}()
+	}
+	wg.Wait()
+
+	soldShares := 0
+	events, _ := eventStream.Get()
+	for _, e := range events {
+		if e.Name == portfolio.SharesRemovedFromPortfolioEventName {
+			soldShares++
+		}
+	}
+
+	if soldShares > 10 {
+		t.Errorf("Sold more shares than existing. Sold: %#v", soldShares)
+	}
+}
+
+func TestCommandsFailWhenThePortfolioCanNotBeLoaded(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}},
+		},
+	}
+	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
+	commandHandler := command_handler.NewCommandHandler(&repository, event.NewEventPublisher(&eventStream))
+
+	err := commandHandler.HandleAddSharesToPortfolio(command.NewAddSharesToPortfolioCommand("default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02"))
+
+	_, ok := err.(*event.UnknownEventError)
+	if !ok {
+		t.Errorf("Expected UnknownEventError but got %#v", err)
+	}
+	if len(eventStream.Events) != 1 {
+		t.Errorf("Unexpected events published: %#v", eventStream.Events[1:])
+	}
+}
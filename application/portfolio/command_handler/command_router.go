@@ -0,0 +1,78 @@
+package command_handler
+
+import (
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/shared"
+)
+
+// CommandRouter passes each command to the command handler of the portfolio it belongs to
+type CommandRouter struct {
+	commandHandlers map[string]PortfolioCommandHandlerInterface
+}
+
+func NewCommandRouter(commandHandlers map[string]PortfolioCommandHandlerInterface) PortfolioCommandHandlerInterface {
+	return &CommandRouter{commandHandlers: commandHandlers}
+}
+
+func (router *CommandRouter) HandleAddSharesToPortfolio(command command.AddSharesToPortfolioCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleAddSharesToPortfolio(command)
+}
+
+func (router *CommandRouter) HandleRemoveSharesFromPortfolio(command command.RemoveSharesFromPortfolioCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleRemoveSharesFromPortfolio(command)
+}
+
+func (router *CommandRouter) HandleRenameTicker(command command.RenameTickerCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleRenameTicker(command)
+}
+
+func (router *CommandRouter) HandleSplitStock(command command.SplitStockCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleSplitStock(command)
+}
+
+func (router *CommandRouter) HandleConvertShares(command command.ConvertSharesCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleConvertShares(command)
+}
+
+func (router *CommandRouter) HandleSpinOff(command command.SpinOffCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleSpinOff(command)
+}
+
+func (router *CommandRouter) commandHandler(portfolioId string) (PortfolioCommandHandlerInterface, error) {
+	commandHandler, found := router.commandHandlers[portfolioId]
+	if !found {
+		return nil, shared.NewUnknownPortfolioError(portfolioId)
+	}
+
+	return commandHandler, nil
+}
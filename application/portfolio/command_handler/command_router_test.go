@@ -0,0 +1,63 @@
+package command_handler_test
+
+import (
+	"stock-monitor/application/event"
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/portfolio/command_handler"
+	"stock-monitor/application/portfolio/persistence"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func makeCommandHandler(eventStream infrastructure.EventStream) command_handler.PortfolioCommandHandlerInterface {
+	repository := persistence.NewEventSourcedPortfolioRepository(eventStream)
+	return command_handler.NewCommandHandler(&repository, event.NewEventPublisher(eventStream))
+}
+
+func TestCommandsAreRoutedToTheirPortfolio(t *testing.T) {
+	defaultEventStream := infrastructure.InMemoryEventStream{}
+	retirementEventStream := infrastructure.InMemoryEventStream{}
+	router := command_handler.NewCommandRouter(map[string]command_handler.PortfolioCommandHandlerInterface{
+		"default":    makeCommandHandler(&defaultEventStream),
+		"retirement": makeCommandHandler(&retirementEventStream),
+	})
+
+	err := router.HandleAddSharesToPortfolio(command.NewAddSharesToPortfolioCommand("retirement", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(defaultEventStream.Events) != 0 {
+		t.Errorf("Unexpected events in default portfolio: %#v", defaultEventStream.Events)
+	}
+	if len(retirementEventStream.Events) != 1 {
+		t.Errorf("Expected one event in retirement portfolio but got %#v", retirementEventStream.Events)
+	}
+}
+
+func TestCommandsForUnknownPortfoliosFail(t *testing.T) {
+	router := command_handler.NewCommandRouter(map[string]command_handler.PortfolioCommandHandlerInterface{})
+
+	err := router.HandleSplitStock(command.NewSplitStockCommand("foo", "MO", 1, 4, "2000-01-01"))
+
+	_, ok := err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+
+	err = router.HandleConvertShares(command.NewConvertSharesCommand("foo", "MO", "PM", 3, 2, domain.Money{}, "2000-01-01"))
+
+	_, ok = err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+
+	err = router.HandleSpinOff(command.NewSpinOffCommand("foo", "MO", "PM", 2, 1, domain.Percentage{}, "2000-01-01"))
+
+	_, ok = err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+}
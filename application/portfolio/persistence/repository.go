@@ -1,12 +1,14 @@
 package persistence
 
 import (
+	"stock-monitor/application/event"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 )
 
 type PortfolioRepository interface {
-	Load() portfolio.Portfolio
+	Load() (portfolio.Portfolio, error)
+	Version() (int, error)
 }
 
 type EventSourcedPortfolioRepository struct {
@@ -17,34 +19,23 @@ func NewEventSourcedPortfolioRepository(eventStream infrastructure.EventStream)
 	return EventSourcedPortfolioRepository{eventStream: eventStream}
 }
 
-func (repository *EventSourcedPortfolioRepository) Load() portfolio.Portfolio {
+// a stored event that can't be decoded fails the load, the portfolio would be wrong without it
+func (repository *EventSourcedPortfolioRepository) Load() (portfolio.Portfolio, error) {
 	p := portfolio.NewPortfolio()
-	for _, event := range repository.eventStream.Get() {
-
-		if event.Name == portfolio.SharesAddedToPortfolioEventName {
-			ticker := event.Payload["ticker"].(string)
-			shares := event.Payload["shares"].(int)
-			date := event.Payload["date"].(string)
-			domainEvent := portfolio.NewSharesAddedToPortfolioEvent(ticker, shares, 0.0, date)
-			p.Apply(&domainEvent)
-			continue
-		}
-
-		if event.Name == portfolio.SharesRemovedFromPortfolioEventName {
-			ticker := event.Payload["ticker"].(string)
-			shares := event.Payload["shares"].(int)
-			domainEvent := portfolio.NewSharesRemovedFromPortfolioEvent(ticker, shares, 0.0)
-			p.Apply(&domainEvent)
-			continue
-		}
-
-		if event.Name == portfolio.TickerRenamedEventName {
-			oldSymbol := event.Payload["old"].(string)
-			newSymbol := event.Payload["new"].(string)
-			domainEvent := portfolio.NewTickerRenamedEvent(oldSymbol, newSymbol)
-			p.Apply(&domainEvent)
-			continue
+	storedEvents, err := repository.eventStream.Get()
+	if err != nil {
+		return portfolio.Portfolio{}, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return portfolio.Portfolio{}, err
 		}
+		p.Apply(domainEvent)
 	}
-	return p
+	return p, nil
+}
+
+func (repository *EventSourcedPortfolioRepository) Version() (int, error) {
+	return repository.eventStream.Version()
 }
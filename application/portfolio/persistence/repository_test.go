@@ -2,7 +2,9 @@ package persistence_test
 
 import (
 	"reflect"
+	"stock-monitor/application/event"
 	"stock-monitor/application/portfolio/persistence"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 	"testing"
@@ -51,12 +53,16 @@ func TestEventsWillBeAppliedWhenLoadingPortfolio(t *testing.T) {
 	}
 	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
 
-	p := repository.Load()
+	p, err := repository.Load()
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
 
 	expectedPortfolio := portfolio.NewPortfolio()
-	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 20, 10.00)
-	event2 := portfolio.NewSharesAddedToPortfolioEvent("PG", 20, 10.00, "2000-01-01")
-	event3 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 10, 10.00)
+	event1 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(10.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
+	event2 := portfolio.NewSharesAddedToPortfolioEvent("PG", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(10.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	event3 := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
 	event4 := portfolio.NewTickerRenamedEvent("MO", "FOO")
 	expectedPortfolio.Apply(&event1)
 	expectedPortfolio.Apply(&event2)
@@ -67,3 +73,19 @@ func TestEventsWillBeAppliedWhenLoadingPortfolio(t *testing.T) {
 		t.Errorf("Unexpected portfolio state. Expected:%#v Got:%#v", expectedPortfolio, p)
 	}
 }
+
+func TestPortfolioCanNotBeLoadedWhenAnEventCanNotBeDecoded(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}},
+		},
+	}
+	repository := persistence.NewEventSourcedPortfolioRepository(&eventStream)
+
+	_, err := repository.Load()
+
+	_, ok := err.(*event.UnknownEventError)
+	if !ok {
+		t.Errorf("Expected UnknownEventError but got %#v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+)
+
+// DefineSavingsPlanCommand defines a plan buying either Amount or Shares of Ticker, Date is the day the plan was defined
+type DefineSavingsPlanCommand struct {
+	PortfolioId  string
+	Ticker       string
+	Amount       domain.Money
+	Shares       domain.Quantity
+	Interval     string
+	ExecutionDay int
+	Start        string
+	End          string
+	Date         string
+}
+
+func NewDefineSavingsPlanCommand(portfolioId shared.PortfolioId, ticker string, amount domain.Money, shares domain.Quantity, interval string, executionDay int, start string, end string, date shared.CommandDate) DefineSavingsPlanCommand {
+	command := DefineSavingsPlanCommand{portfolioId.Get(), ticker, amount, shares, interval, executionDay, start, end, date.Get()}
+
+	return command
+}
+
+// ExecuteSavingsPlanOrderCommand confirms the pending order of a plan on DueDate at the actual Price, Date is the day of the confirmation
+type ExecuteSavingsPlanOrderCommand struct {
+	PortfolioId string
+	PlanId      int
+	DueDate     string
+	Price       domain.Money
+	Fee         domain.Money
+	Date        string
+}
+
+func NewExecuteSavingsPlanOrderCommand(portfolioId shared.PortfolioId, planId int, dueDate string, price domain.Money, fee domain.Money, date shared.CommandDate) ExecuteSavingsPlanOrderCommand {
+	command := ExecuteSavingsPlanOrderCommand{portfolioId.Get(), planId, dueDate, price, fee, date.Get()}
+
+	return command
+}
+
+// FillSavingsPlanOrdersCommand executes the orders due until Date at the closing prices of their due dates
+type FillSavingsPlanOrdersCommand struct {
+	PortfolioId string
+	Date        string
+}
+
+func NewFillSavingsPlanOrdersCommand(portfolioId shared.PortfolioId, date shared.CommandDate) FillSavingsPlanOrdersCommand {
+	command := FillSavingsPlanOrdersCommand{portfolioId.Get(), date.Get()}
+
+	return command
+}
@@ -0,0 +1,35 @@
+package command_test
+
+import (
+	"reflect"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestDefineSavingsPlanCommand(t *testing.T) {
+	defineSavingsPlanCommand := command.NewDefineSavingsPlanCommand("default", "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.Quantity{}, "monthly", 15, "2001-01-01", "", "2001-01-01")
+	expected := command.DefineSavingsPlanCommand{"default", "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.Quantity{}, "monthly", 15, "2001-01-01", "", "2001-01-01"}
+
+	if reflect.DeepEqual(defineSavingsPlanCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", defineSavingsPlanCommand, expected)
+	}
+}
+
+func TestExecuteSavingsPlanOrderCommand(t *testing.T) {
+	executeCommand := command.NewExecuteSavingsPlanOrderCommand("default", 1, "2001-01-15", domain.NewMoneyFromFloat(25, "EUR"), domain.NewMoneyFromFloat(1, "EUR"), "2001-01-16")
+	expected := command.ExecuteSavingsPlanOrderCommand{"default", 1, "2001-01-15", domain.NewMoneyFromFloat(25, "EUR"), domain.NewMoneyFromFloat(1, "EUR"), "2001-01-16"}
+
+	if reflect.DeepEqual(executeCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", executeCommand, expected)
+	}
+}
+
+func TestFillSavingsPlanOrdersCommand(t *testing.T) {
+	fillCommand := command.NewFillSavingsPlanOrdersCommand("default", "2001-01-16")
+	expected := command.FillSavingsPlanOrdersCommand{"default", "2001-01-16"}
+
+	if reflect.DeepEqual(fillCommand, expected) == false {
+		t.Errorf("Unexpected command. got: %#v, want: %#v", fillCommand, expected)
+	}
+}
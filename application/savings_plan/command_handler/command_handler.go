@@ -0,0 +1,232 @@
+package command_handler
+
+import (
+	"stock-monitor/application/event"
+	portfolioCommand "stock-monitor/application/portfolio/command"
+	portfolioCommandHandler "stock-monitor/application/portfolio/command_handler"
+	portfolioPersistence "stock-monitor/application/portfolio/persistence"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/persistence"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"time"
+)
+
+const maxConcurrencyConflictRetries = 3
+
+// an order is only filled with a close of its due date or of the days before it, enough to cover a weekend
+// and a holiday, an older close would buy at a stale price
+const maxCloseAgeDays = 4
+
+type SavingsPlanCommandHandlerInterface interface {
+	HandleDefineSavingsPlan(command command.DefineSavingsPlanCommand) error
+	HandleExecuteSavingsPlanOrder(command command.ExecuteSavingsPlanOrderCommand) error
+	HandleFillSavingsPlanOrders(command command.FillSavingsPlanOrdersCommand) (FillReport, error)
+}
+
+const (
+	Filled  = "FILLED"
+	Pending = "PENDING"
+	Failed  = "FAILED"
+)
+
+// OrderResult is a FILLED order, an order left PENDING without a recent closing price or an order that FAILED with Error
+type OrderResult struct {
+	PlanId  int    `json:"plan_id"`
+	DueDate string `json:"due_date"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+type FillReport struct {
+	Orders []OrderResult `json:"orders"`
+}
+
+func (report FillReport) Count(status string) int {
+	count := 0
+	for _, order := range report.Orders {
+		if order.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// PriceProvider is the daily prices of the ticker from and including the first until and including the last date
+type PriceProvider interface {
+	Closes(ticker string, from string, to string) ([]query.DailyPrice, error)
+}
+
+type SavingsPlanCommandHandler struct {
+	repository          persistence.SavingsPlanRepository
+	publisher           event.EventPublisher
+	portfolioRepository portfolioPersistence.PortfolioRepository
+	portfolioPublisher  event.EventPublisher
+	priceProvider       PriceProvider
+	buyGuard            portfolioCommandHandler.BuyGuard
+}
+
+func NewSavingsPlanCommandHandler(repository persistence.SavingsPlanRepository, publisher event.EventPublisher, portfolioRepository portfolioPersistence.PortfolioRepository, portfolioPublisher event.EventPublisher, priceProvider PriceProvider) SavingsPlanCommandHandlerInterface {
+	return NewGuardedSavingsPlanCommandHandler(repository, publisher, portfolioRepository, portfolioPublisher, priceProvider, func(command portfolioCommand.AddSharesToPortfolioCommand) ([]event.Publication, error) {
+		return []event.Publication{}, nil
+	})
+}
+
+// NewGuardedSavingsPlanCommandHandler checks the buy of every executed order with the guard of the portfolio buys
+func NewGuardedSavingsPlanCommandHandler(repository persistence.SavingsPlanRepository, publisher event.EventPublisher, portfolioRepository portfolioPersistence.PortfolioRepository, portfolioPublisher event.EventPublisher, priceProvider PriceProvider, buyGuard portfolioCommandHandler.BuyGuard) SavingsPlanCommandHandlerInterface {
+	return &SavingsPlanCommandHandler{repository: repository, publisher: publisher, portfolioRepository: portfolioRepository, portfolioPublisher: portfolioPublisher, priceProvider: priceProvider, buyGuard: buyGuard}
+}
+
+func (commandHandler *SavingsPlanCommandHandler) HandleDefineSavingsPlan(command command.DefineSavingsPlanCommand) error {
+	var err error
+	for attempt := 0; attempt < maxConcurrencyConflictRetries; attempt++ {
+		var version int
+		version, err = commandHandler.repository.Version()
+		if err != nil {
+			return err
+		}
+		var plans savings_plan.SavingsPlans
+		plans, err = commandHandler.repository.Load()
+		if err != nil {
+			return err
+		}
+
+		err = plans.Define(command.Ticker, command.Amount, command.Shares, command.Interval, command.ExecutionDay, command.Start, command.End)
+		if err != nil {
+			return err
+		}
+
+		err = commandHandler.publisher.PublishDomainEventsAtVersion(plans.GetRecordedEvents(), command.Date, version)
+
+		_, conflict := err.(*infrastructure.ConcurrencyConflictError)
+		if !conflict {
+			return err
+		}
+	}
+
+	return err
+}
+
+// the executed order and the buy are published together or not at all, the buy occurs on the due date.
+// The buy passes the guard of the portfolio buys and is only published while the streams pinned by the guard didn't change.
+// Events of the portfolio are kept in date order, so an order due before the last portfolio event can't be executed any more.
+func (commandHandler *SavingsPlanCommandHandler) HandleExecuteSavingsPlanOrder(command command.ExecuteSavingsPlanOrderCommand) error {
+	var err error
+	for attempt := 0; attempt < maxConcurrencyConflictRetries; attempt++ {
+		var version int
+		version, err = commandHandler.repository.Version()
+		if err != nil {
+			return err
+		}
+		var portfolioVersion int
+		portfolioVersion, err = commandHandler.portfolioRepository.Version()
+		if err != nil {
+			return err
+		}
+		var plans savings_plan.SavingsPlans
+		plans, err = commandHandler.repository.Load()
+		if err != nil {
+			return err
+		}
+		var p portfolio.Portfolio
+		p, err = commandHandler.portfolioRepository.Load()
+		if err != nil {
+			return err
+		}
+
+		var order savings_plan.PendingOrder
+		order, err = plans.PendingOrder(command.PlanId, command.DueDate)
+		if err != nil {
+			return err
+		}
+		var shares domain.Quantity
+		shares, err = order.SharesAt(command.Price)
+		if err != nil {
+			return err
+		}
+
+		err = plans.ExecuteOrder(command.PlanId, command.DueDate, shares, command.Price)
+		if err != nil {
+			return err
+		}
+
+		err = p.AddSharesToPortfolio(order.Ticker, shares, command.Price, command.Fee, domain.Money{}, command.DueDate)
+		if err != nil {
+			return err
+		}
+
+		var pins []event.Publication
+		pins, err = commandHandler.buyGuard(portfolioCommand.NewAddSharesToPortfolioCommand(shared.PortfolioId(command.PortfolioId), order.Ticker, shares, command.Price, command.Fee, domain.Money{}, shared.CommandDate(command.DueDate)))
+		if err != nil {
+			return err
+		}
+
+		// the pins go first, a pin of the portfolio stream then conflicts with a changed portfolio version
+		err = event.PublishAtomically(append(pins,
+			event.Publication{commandHandler.publisher, plans.GetRecordedEvents(), version, command.Date},
+			event.Publication{commandHandler.portfolioPublisher, p.GetRecordedEvents(), portfolioVersion, ""},
+		), command.DueDate)
+
+		_, conflict := err.(*infrastructure.ConcurrencyConflictError)
+		if !conflict {
+			return err
+		}
+	}
+
+	return err
+}
+
+// every pending order is filled on its own, orders without a close on their due date or the maxCloseAgeDays before it stay pending
+// and a failing order doesn't keep the others from being filled. Like a confirmed order, an order due before the
+// last portfolio event fails.
+func (commandHandler *SavingsPlanCommandHandler) HandleFillSavingsPlanOrders(fillCommand command.FillSavingsPlanOrdersCommand) (FillReport, error) {
+	plans, err := commandHandler.repository.Load()
+	if err != nil {
+		return FillReport{}, err
+	}
+
+	results := []OrderResult{}
+	for _, order := range plans.PendingOrders(fillCommand.Date) {
+		price, found, err := commandHandler.recentClose(order.Ticker, order.DueDate)
+		if err != nil {
+			results = append(results, OrderResult{order.PlanId, order.DueDate, Failed, err.Error()})
+			continue
+		}
+		if !found {
+			results = append(results, OrderResult{order.PlanId, order.DueDate, Pending, ""})
+			continue
+		}
+
+		err = commandHandler.HandleExecuteSavingsPlanOrder(command.NewExecuteSavingsPlanOrderCommand(shared.PortfolioId(fillCommand.PortfolioId), order.PlanId, order.DueDate, price, domain.Money{}, shared.CommandDate(fillCommand.Date)))
+		if err != nil {
+			results = append(results, OrderResult{order.PlanId, order.DueDate, Failed, err.Error()})
+			continue
+		}
+		results = append(results, OrderResult{order.PlanId, order.DueDate, Filled, ""})
+	}
+
+	return FillReport{results}, nil
+}
+
+// recentClose is the last close of the ticker on the due date or within maxCloseAgeDays before it
+func (commandHandler *SavingsPlanCommandHandler) recentClose(ticker string, dueDate string) (domain.Money, bool, error) {
+	due, err := time.Parse("2006-01-02", dueDate)
+	if err != nil {
+		return domain.Money{}, false, err
+	}
+
+	closes, err := commandHandler.priceProvider.Closes(ticker, due.AddDate(0, 0, -maxCloseAgeDays).Format("2006-01-02"), dueDate)
+	if err != nil {
+		return domain.Money{}, false, err
+	}
+	if len(closes) == 0 {
+		return domain.Money{}, false, nil
+	}
+
+	return closes[len(closes)-1].Close, true, nil
+}
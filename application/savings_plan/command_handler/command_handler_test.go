@@ -0,0 +1,246 @@
+package command_handler_test
+
+import (
+	"reflect"
+	cashCommandHandler "stock-monitor/application/cash/command_handler"
+	cashPersistence "stock-monitor/application/cash/persistence"
+	"stock-monitor/application/event"
+	portfolioCommand "stock-monitor/application/portfolio/command"
+	portfolioPersistence "stock-monitor/application/portfolio/persistence"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/savings_plan/persistence"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"testing"
+)
+
+func makeCommandHandler(savingsPlanEventStream infrastructure.EventStream, portfolioEventStream infrastructure.EventStream, priceProvider command_handler.PriceProvider) command_handler.SavingsPlanCommandHandlerInterface {
+	repository := persistence.NewEventSourcedSavingsPlanRepository(savingsPlanEventStream)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(portfolioEventStream)
+
+	return command_handler.NewSavingsPlanCommandHandler(&repository, event.NewEventPublisher(savingsPlanEventStream), &portfolioRepository, event.NewEventPublisher(portfolioEventStream), priceProvider)
+}
+
+func definedPlan() infrastructure.Event {
+	return infrastructure.Event{
+		savings_plan.SavingsPlanDefinedEventName,
+		map[string]interface{}{"id": 1, "ticker": "MO", "amount": "100", "currency": "EUR", "shares": "0", "interval": "monthly", "execution_day": 15, "start": "2000-01-01", "end": ""},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+	}
+}
+
+func TestItHandlesDefineSavingsPlanCommand(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &infrastructure.InMemoryEventStream{}, query.FakePriceHistoryProvider{})
+
+	err := commandHandler.HandleDefineSavingsPlan(command.NewDefineSavingsPlanCommand("default", "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.Quantity{}, "monthly", 15, "2000-01-01", "", "2000-01-01"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(savingsPlanEventStream.Events, []infrastructure.Event{definedPlan()}) == false {
+		t.Errorf("Unexpected event published. Expected:%#v Got:%#v", definedPlan(), savingsPlanEventStream.Events)
+	}
+}
+
+func TestItHandlesExecuteSavingsPlanOrderCommand(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &portfolioEventStream, query.FakePriceHistoryProvider{})
+
+	err := commandHandler.HandleExecuteSavingsPlanOrder(command.NewExecuteSavingsPlanOrderCommand("default", 1, "2000-01-15", domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(1, "EUR"), "2000-01-20"))
+
+	expectedOrder := infrastructure.Event{
+		savings_plan.OrderExecutedEventName,
+		map[string]interface{}{"plan_id": 1, "due_date": "2000-01-15", "shares": "2.5", "price": "40", "currency": "EUR"},
+		map[string]interface{}{"occurred_at": "2000-01-20", "version": 1},
+	}
+	expectedBuy := infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "shares": "2.5", "price": "40", "fee": "1", "taxes": "0", "currency": "EUR", "date": "2000-01-15"},
+		map[string]interface{}{"occurred_at": "2000-01-15", "version": 4},
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(savingsPlanEventStream.Events[1:], []infrastructure.Event{expectedOrder}) == false {
+		t.Errorf("Unexpected savings plan event published. Expected:%#v Got:%#v", expectedOrder, savingsPlanEventStream.Events[1:])
+	}
+	if reflect.DeepEqual(portfolioEventStream.Events, []infrastructure.Event{expectedBuy}) == false {
+		t.Errorf("Unexpected portfolio event published. Expected:%#v Got:%#v", expectedBuy, portfolioEventStream.Events)
+	}
+}
+
+func TestNoOrderIsExecutedWhenTheBuyFails(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &portfolioEventStream, query.FakePriceHistoryProvider{})
+
+	err := commandHandler.HandleExecuteSavingsPlanOrder(command.NewExecuteSavingsPlanOrderCommand("default", 1, "2000-01-15", domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(1, "USD"), "2000-01-20"))
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+	if len(savingsPlanEventStream.Events) != 1 || len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be published but got %#v and %#v", savingsPlanEventStream.Events[1:], portfolioEventStream.Events)
+	}
+}
+
+func TestBuysOfOrdersOverdrawingCashAreRejected(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	cashEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{
+		{
+			cash.CashDepositedEventName,
+			map[string]interface{}{"amount": "50", "currency": "EUR", "date": "2000-01-01"},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+		},
+	}}
+	cashRepository := cashPersistence.NewEventSourcedCashRepository(&cashEventStream, &portfolioEventStream, &infrastructure.InMemoryEventStream{})
+	repository := persistence.NewEventSourcedSavingsPlanRepository(&savingsPlanEventStream)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&portfolioEventStream)
+	commandHandler := command_handler.NewGuardedSavingsPlanCommandHandler(&repository, event.NewEventPublisher(&savingsPlanEventStream), &portfolioRepository, event.NewEventPublisher(&portfolioEventStream), query.FakePriceHistoryProvider{}, cashCommandHandler.NewCashCoveredBuyGuard(&cashRepository))
+
+	err := commandHandler.HandleExecuteSavingsPlanOrder(command.NewExecuteSavingsPlanOrderCommand("default", 1, "2000-01-15", domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(1, "EUR"), "2000-01-20"))
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+	if len(savingsPlanEventStream.Events) != 1 || len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be published but got %#v and %#v", savingsPlanEventStream.Events[1:], portfolioEventStream.Events)
+	}
+}
+
+func TestOrdersAreNotExecutedWhenAStreamPinnedByTheGuardChanged(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	cashEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{
+		{
+			cash.CashDepositedEventName,
+			map[string]interface{}{"amount": "500", "currency": "EUR", "date": "2000-01-01"},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+		},
+	}}
+	repository := persistence.NewEventSourcedSavingsPlanRepository(&savingsPlanEventStream)
+	portfolioRepository := portfolioPersistence.NewEventSourcedPortfolioRepository(&portfolioEventStream)
+	// the guard checked the cash stream before its deposit
+	staleGuard := func(command portfolioCommand.AddSharesToPortfolioCommand) ([]event.Publication, error) {
+		return []event.Publication{{event.NewEventPublisher(&cashEventStream), []domain.DomainEvent{}, 0, ""}}, nil
+	}
+	commandHandler := command_handler.NewGuardedSavingsPlanCommandHandler(&repository, event.NewEventPublisher(&savingsPlanEventStream), &portfolioRepository, event.NewEventPublisher(&portfolioEventStream), query.FakePriceHistoryProvider{}, staleGuard)
+
+	err := commandHandler.HandleExecuteSavingsPlanOrder(command.NewExecuteSavingsPlanOrderCommand("default", 1, "2000-01-15", domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(1, "EUR"), "2000-01-20"))
+
+	_, ok := err.(*infrastructure.ConcurrencyConflictError)
+	if !ok {
+		t.Errorf("Expected ConcurrencyConflictError but got %#v", err)
+	}
+	if len(savingsPlanEventStream.Events) != 1 || len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be published but got %#v and %#v", savingsPlanEventStream.Events[1:], portfolioEventStream.Events)
+	}
+}
+
+func TestItFillsPendingOrdersWithHistoricalPrices(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	priceProvider := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{
+		"MO": {"2000-01-14": domain.NewMoneyFromFloat(50, "EUR"), "2000-02-14": domain.NewMoneyFromFloat(50, "EUR")},
+	}}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &portfolioEventStream, priceProvider)
+
+	report, err := commandHandler.HandleFillSavingsPlanOrders(command.NewFillSavingsPlanOrdersCommand("default", "2000-02-20"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if report.Count(command_handler.Filled) != 2 {
+		t.Errorf("Expected two filled orders but got %#v", report)
+	}
+	if len(portfolioEventStream.Events) != 2 {
+		t.Fatalf("Expected two buys but got %#v", portfolioEventStream.Events)
+	}
+	for key, dueDate := range []string{"2000-01-15", "2000-02-15"} {
+		if portfolioEventStream.Events[key].Payload["date"] != dueDate || portfolioEventStream.Events[key].Payload["shares"] != "2" {
+			t.Errorf("Unexpected buy. Expected 2 shares on %#v Got:%#v", dueDate, portfolioEventStream.Events[key].Payload)
+		}
+	}
+}
+
+func TestOrdersWithoutHistoricalPriceStayPending(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &portfolioEventStream, query.FakePriceHistoryProvider{})
+
+	report, err := commandHandler.HandleFillSavingsPlanOrders(command.NewFillSavingsPlanOrdersCommand("default", "2000-02-20"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if report.Count(command_handler.Pending) != 2 {
+		t.Errorf("Expected two pending orders but got %#v", report)
+	}
+	if len(savingsPlanEventStream.Events) != 1 || len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be published but got %#v and %#v", savingsPlanEventStream.Events[1:], portfolioEventStream.Events)
+	}
+}
+
+func TestOrdersWithOnlyAnOldClosingPriceStayPending(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	priceProvider := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{
+		"MO": {"2000-01-14": domain.NewMoneyFromFloat(50, "EUR")},
+	}}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &portfolioEventStream, priceProvider)
+
+	report, err := commandHandler.HandleFillSavingsPlanOrders(command.NewFillSavingsPlanOrdersCommand("default", "2000-02-20"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	want := []command_handler.OrderResult{{1, "2000-01-15", command_handler.Filled, ""}, {1, "2000-02-15", command_handler.Pending, ""}}
+	if reflect.DeepEqual(report.Orders, want) == false {
+		t.Errorf("Unexpected orders. Expected:%#v Got:%#v", want, report.Orders)
+	}
+	if len(portfolioEventStream.Events) != 1 {
+		t.Errorf("Expected only the first order to be bought but got %#v", portfolioEventStream.Events)
+	}
+}
+
+func TestFailingOrdersDoNotKeepLaterOrdersFromBeingFilled(t *testing.T) {
+	savingsPlanEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{definedPlan()}}
+	// the first order is due before the last portfolio event and can't be recorded any more
+	portfolioEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "shares": "1", "price": "50", "fee": "0", "taxes": "0", "currency": "EUR", "date": "2000-02-01"},
+			map[string]interface{}{"occurred_at": "2000-02-01", "version": 4},
+		},
+	}}
+	priceProvider := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{
+		"MO": {"2000-01-14": domain.NewMoneyFromFloat(50, "EUR"), "2000-02-14": domain.NewMoneyFromFloat(50, "EUR")},
+	}}
+	commandHandler := makeCommandHandler(&savingsPlanEventStream, &portfolioEventStream, priceProvider)
+
+	report, err := commandHandler.HandleFillSavingsPlanOrders(command.NewFillSavingsPlanOrdersCommand("default", "2000-02-20"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(report.Orders) != 2 || report.Orders[0].Status != command_handler.Failed || report.Orders[0].Error == "" {
+		t.Fatalf("Expected the first order to fail but got %#v", report)
+	}
+	if report.Orders[1] != (command_handler.OrderResult{1, "2000-02-15", command_handler.Filled, ""}) {
+		t.Errorf("Expected the second order to be filled but got %#v", report.Orders[1])
+	}
+	if len(portfolioEventStream.Events) != 2 || portfolioEventStream.Events[1].Payload["date"] != "2000-02-15" {
+		t.Errorf("Expected the second order to be bought but got %#v", portfolioEventStream.Events)
+	}
+}
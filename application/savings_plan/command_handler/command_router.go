@@ -0,0 +1,51 @@
+package command_handler
+
+import (
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/shared"
+)
+
+// SavingsPlanCommandRouter passes each command to the command handler of the portfolio it belongs to
+type SavingsPlanCommandRouter struct {
+	commandHandlers map[string]SavingsPlanCommandHandlerInterface
+}
+
+func NewSavingsPlanCommandRouter(commandHandlers map[string]SavingsPlanCommandHandlerInterface) SavingsPlanCommandHandlerInterface {
+	return &SavingsPlanCommandRouter{commandHandlers: commandHandlers}
+}
+
+func (router *SavingsPlanCommandRouter) HandleDefineSavingsPlan(command command.DefineSavingsPlanCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleDefineSavingsPlan(command)
+}
+
+func (router *SavingsPlanCommandRouter) HandleExecuteSavingsPlanOrder(command command.ExecuteSavingsPlanOrderCommand) error {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	return commandHandler.HandleExecuteSavingsPlanOrder(command)
+}
+
+func (router *SavingsPlanCommandRouter) HandleFillSavingsPlanOrders(command command.FillSavingsPlanOrdersCommand) (FillReport, error) {
+	commandHandler, err := router.commandHandler(command.PortfolioId)
+	if err != nil {
+		return FillReport{}, err
+	}
+
+	return commandHandler.HandleFillSavingsPlanOrders(command)
+}
+
+func (router *SavingsPlanCommandRouter) commandHandler(portfolioId string) (SavingsPlanCommandHandlerInterface, error) {
+	commandHandler, found := router.commandHandlers[portfolioId]
+	if !found {
+		return nil, shared.NewUnknownPortfolioError(portfolioId)
+	}
+
+	return commandHandler, nil
+}
@@ -0,0 +1,34 @@
+package command_handler_test
+
+import (
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestSavingsPlanCommandsForUnknownPortfoliosFail(t *testing.T) {
+	router := command_handler.NewSavingsPlanCommandRouter(map[string]command_handler.SavingsPlanCommandHandlerInterface{})
+
+	err := router.HandleDefineSavingsPlan(command.NewDefineSavingsPlanCommand("foo", "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.Quantity{}, "monthly", 15, "2000-01-01", "", "2000-01-01"))
+
+	_, ok := err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+
+	err = router.HandleExecuteSavingsPlanOrder(command.NewExecuteSavingsPlanOrderCommand("foo", 1, "2000-01-15", domain.NewMoneyFromFloat(40, "EUR"), domain.Money{}, "2000-01-15"))
+
+	_, ok = err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+
+	_, err = router.HandleFillSavingsPlanOrders(command.NewFillSavingsPlanOrdersCommand("foo", "2000-01-15"))
+
+	_, ok = err.(*shared.UnknownPortfolioError)
+	if !ok {
+		t.Errorf("Expected UnknownPortfolioError but got %#v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package persistence
+
+import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+)
+
+type SavingsPlanRepository interface {
+	Load() (savings_plan.SavingsPlans, error)
+	Version() (int, error)
+}
+
+type EventSourcedSavingsPlanRepository struct {
+	eventStream infrastructure.EventStream
+}
+
+func NewEventSourcedSavingsPlanRepository(eventStream infrastructure.EventStream) EventSourcedSavingsPlanRepository {
+	return EventSourcedSavingsPlanRepository{eventStream: eventStream}
+}
+
+func (repository *EventSourcedSavingsPlanRepository) Load() (savings_plan.SavingsPlans, error) {
+	plans := savings_plan.NewSavingsPlans()
+	storedEvents, err := repository.eventStream.Get()
+	if err != nil {
+		return savings_plan.SavingsPlans{}, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return savings_plan.SavingsPlans{}, err
+		}
+		plans.Apply(domainEvent)
+	}
+
+	return plans, nil
+}
+
+func (repository *EventSourcedSavingsPlanRepository) Version() (int, error) {
+	return repository.eventStream.Version()
+}
@@ -0,0 +1,44 @@
+package persistence_test
+
+import (
+	"reflect"
+	"stock-monitor/application/savings_plan/persistence"
+	"stock-monitor/domain"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func TestSavingsPlansAreLoadedFromEventStream(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				savings_plan.SavingsPlanDefinedEventName,
+				map[string]interface{}{"id": 1, "ticker": "MO", "amount": "100", "currency": "EUR", "shares": "0", "interval": "monthly", "execution_day": 15, "start": "2000-01-01", "end": ""},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+			{
+				savings_plan.OrderExecutedEventName,
+				map[string]interface{}{"plan_id": 1, "due_date": "2000-01-15", "shares": "4", "price": "25", "currency": "EUR"},
+				map[string]interface{}{"occurred_at": "2000-01-16", "version": 1},
+			},
+		},
+	}
+	repository := persistence.NewEventSourcedSavingsPlanRepository(&eventStream)
+
+	plans, err := repository.Load()
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	want := []savings_plan.PendingOrder{{1, "MO", "2000-02-15", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0)}}
+	got := plans.PendingOrders("2000-02-28")
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected pending orders. Expected:%#v Got:%#v", want, got)
+	}
+	version, _ := repository.Version()
+	if version != 2 {
+		t.Errorf("Unexpected version. Expected:%#v Got:%#v", 2, version)
+	}
+}
@@ -0,0 +1,13 @@
+package shared
+
+type UnknownPortfolioError struct {
+	portfolioId string
+}
+
+func NewUnknownPortfolioError(portfolioId string) *UnknownPortfolioError {
+	return &UnknownPortfolioError{portfolioId: portfolioId}
+}
+
+func (e *UnknownPortfolioError) Error() string {
+	return "Portfolio not found. Id: " + e.portfolioId
+}
@@ -0,0 +1,17 @@
+package shared_test
+
+import (
+	"stock-monitor/application/shared"
+	"testing"
+)
+
+func TestUnknownPortfolioError(t *testing.T) {
+	err := shared.NewUnknownPortfolioError("foo")
+
+	expected := "Portfolio not found. Id: foo"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
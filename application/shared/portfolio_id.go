@@ -0,0 +1,13 @@
+package shared
+
+const DefaultPortfolioId = "default"
+
+type PortfolioId string
+
+func (id PortfolioId) Get() string {
+	if id != "" {
+		return string(id)
+	}
+
+	return DefaultPortfolioId
+}
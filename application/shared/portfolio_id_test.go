@@ -0,0 +1,22 @@
+package shared_test
+
+import (
+	"stock-monitor/application/shared"
+	"testing"
+)
+
+func TestDefaultPortfolioIdIsUsedWhenNoneIsGiven(t *testing.T) {
+	id := shared.PortfolioId("")
+
+	if id.Get() != shared.DefaultPortfolioId {
+		t.Errorf("Unexpected portfolio id. got: %#v, want: %#v", id.Get(), shared.DefaultPortfolioId)
+	}
+}
+
+func TestProvidesThePortfolioIdAsString(t *testing.T) {
+	id := shared.PortfolioId("retirement")
+
+	if id.Get() != "retirement" {
+		t.Errorf("Unexpected portfolio id. got: %#v, want: %#v", id.Get(), "retirement")
+	}
+}
@@ -0,0 +1,53 @@
+package transaction_import
+
+import "strconv"
+
+type InvalidTransactionCsvError struct {
+	line   int
+	reason string
+}
+
+type UnmappedTransactionTypeError struct {
+	label string
+}
+
+type InvalidTransactionValueError struct {
+	column string
+	value  string
+}
+
+type UnknownPresetError struct {
+	name string
+}
+
+func NewInvalidTransactionCsvError(line int, reason string) *InvalidTransactionCsvError {
+	return &InvalidTransactionCsvError{line: line, reason: reason}
+}
+
+func NewUnmappedTransactionTypeError(label string) *UnmappedTransactionTypeError {
+	return &UnmappedTransactionTypeError{label: label}
+}
+
+func NewInvalidTransactionValueError(column string, value string) *InvalidTransactionValueError {
+	return &InvalidTransactionValueError{column: column, value: value}
+}
+
+func NewUnknownPresetError(name string) *UnknownPresetError {
+	return &UnknownPresetError{name: name}
+}
+
+func (e *InvalidTransactionCsvError) Error() string {
+	return "invalid transaction csv. line: " + strconv.Itoa(e.line) + " " + e.reason
+}
+
+func (e *UnmappedTransactionTypeError) Error() string {
+	return "transaction type is not mapped. type: " + e.label
+}
+
+func (e *InvalidTransactionValueError) Error() string {
+	return "invalid value. column: " + e.column + " value: " + e.value
+}
+
+func (e *UnknownPresetError) Error() string {
+	return "unknown broker preset. preset: " + e.name
+}
@@ -0,0 +1,50 @@
+package transaction_import_test
+
+import (
+	"stock-monitor/application/transaction_import"
+	"testing"
+)
+
+func TestInvalidTransactionCsvError(t *testing.T) {
+	err := transaction_import.NewInvalidTransactionCsvError(1, "header missing")
+
+	expected := "invalid transaction csv. line: 1 header missing"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestUnmappedTransactionTypeError(t *testing.T) {
+	err := transaction_import.NewUnmappedTransactionTypeError("deposit")
+
+	expected := "transaction type is not mapped. type: deposit"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidTransactionValueError(t *testing.T) {
+	err := transaction_import.NewInvalidTransactionValueError("shares", "ten")
+
+	expected := "invalid value. column: shares value: ten"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestUnknownPresetError(t *testing.T) {
+	err := transaction_import.NewUnknownPresetError("foo")
+
+	expected := "unknown broker preset. preset: foo"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
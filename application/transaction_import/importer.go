@@ -0,0 +1,220 @@
+package transaction_import
+
+import (
+	"sort"
+	dividend_command "stock-monitor/application/dividend/command"
+	dividend_command_handler "stock-monitor/application/dividend/command_handler"
+	"stock-monitor/application/event"
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/portfolio/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+)
+
+const (
+	Imported  = "IMPORTED"
+	Ready     = "READY"
+	Duplicate = "DUPLICATE"
+	Ignored   = "IGNORED"
+	Failed    = "FAILED"
+)
+
+// RowResult is READY to be imported in a dry run, otherwise IMPORTED, or it is a DUPLICATE of a recorded transaction,
+// IGNORED for its type or FAILED with Error
+type RowResult struct {
+	Line        int
+	Status      string
+	Transaction Transaction
+	Error       string
+}
+
+type Report struct {
+	Rows []RowResult
+}
+
+func (report Report) Count(status string) int {
+	count := 0
+	for _, row := range report.Rows {
+		if row.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// EventStreams are the streams of a portfolio the recorded transactions are read from and settled against
+type EventStreams struct {
+	Portfolio infrastructure.EventStream
+	Dividend  infrastructure.EventStream
+	Cash      infrastructure.EventStream
+}
+
+// CommandHandlers makes the portfolio and dividend command handlers recording into the given event streams
+type CommandHandlers func(eventStreams EventStreams) (command_handler.PortfolioCommandHandlerInterface, dividend_command_handler.DividendCommandHandlerInterface)
+
+// Importer passes transactions of a portfolio to the portfolio and dividend command handlers
+type Importer struct {
+	eventStreams    EventStreams
+	commandHandlers CommandHandlers
+}
+
+func NewImporter(eventStreams EventStreams, commandHandlers CommandHandlers) Importer {
+	return Importer{eventStreams: eventStreams, commandHandlers: commandHandlers}
+}
+
+// Import records the transactions oldest first and reports the rows in the order of the file.
+// A dry run records them in copies of the event streams, so it fails the same rows as the import would.
+// Transactions equal to a recorded buy, sell or dividend are duplicates, each recorded one matches one row only.
+// All rows fail when the recorded transactions can't be read. Events are stored in date order, so rows dated before
+// the last recorded event of their stream fail and can't be backfilled.
+func (importer *Importer) Import(portfolioId string, rows []Row, dryRun bool) Report {
+	eventStreams := importer.eventStreams
+	imported := Imported
+	if dryRun {
+		copies, err := copyEventStreams(eventStreams)
+		if err != nil {
+			return failAll(rows, err)
+		}
+		eventStreams = copies
+		imported = Ready
+	}
+
+	portfolioCommandHandler, dividendCommandHandler := importer.commandHandlers(eventStreams)
+	recorded, err := recordedTransactions(eventStreams.Portfolio, eventStreams.Dividend)
+	if err != nil {
+		return failAll(rows, err)
+	}
+
+	sortedRows := append([]Row{}, rows...)
+	newestFirst := isNewestFirst(rows)
+	sort.SliceStable(sortedRows, func(i, j int) bool {
+		if sortedRows[i].Transaction.Date != sortedRows[j].Transaction.Date {
+			return sortedRows[i].Transaction.Date < sortedRows[j].Transaction.Date
+		}
+		if newestFirst {
+			return sortedRows[i].Line > sortedRows[j].Line
+		}
+		return sortedRows[i].Line < sortedRows[j].Line
+	})
+
+	results := []RowResult{}
+	for _, row := range sortedRows {
+		if _, unmapped := row.Error.(*UnmappedTransactionTypeError); unmapped {
+			results = append(results, RowResult{row.Line, Ignored, row.Transaction, row.Error.Error()})
+			continue
+		}
+		if row.Error != nil {
+			results = append(results, RowResult{row.Line, Failed, row.Transaction, row.Error.Error()})
+			continue
+		}
+
+		key := transactionKey(row.Transaction)
+		if recorded[key] > 0 {
+			recorded[key]--
+			results = append(results, RowResult{row.Line, Duplicate, row.Transaction, ""})
+			continue
+		}
+
+		err := record(portfolioCommandHandler, dividendCommandHandler, shared.PortfolioId(portfolioId), row.Transaction)
+		if err != nil {
+			results = append(results, RowResult{row.Line, Failed, row.Transaction, err.Error()})
+			continue
+		}
+		results = append(results, RowResult{row.Line, imported, row.Transaction, ""})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+
+	return Report{results}
+}
+
+func failAll(rows []Row, err error) Report {
+	results := []RowResult{}
+	for _, row := range rows {
+		results = append(results, RowResult{row.Line, Failed, row.Transaction, err.Error()})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+
+	return Report{results}
+}
+
+func copyEventStreams(eventStreams EventStreams) (EventStreams, error) {
+	copies := []infrastructure.EventStream{}
+	for _, eventStream := range []infrastructure.EventStream{eventStreams.Portfolio, eventStreams.Dividend, eventStreams.Cash} {
+		storedEvents, err := eventStream.Get()
+		if err != nil {
+			return EventStreams{}, err
+		}
+		copies = append(copies, &infrastructure.InMemoryEventStream{append([]infrastructure.Event{}, storedEvents...)})
+	}
+
+	return EventStreams{copies[0], copies[1], copies[2]}, nil
+}
+
+// isNewestFirst tells whether the file lists transactions newest first, so rows of the same day are recorded bottom up
+func isNewestFirst(rows []Row) bool {
+	dates := []string{}
+	for _, row := range rows {
+		if row.Error == nil && row.Transaction.Date != "" {
+			dates = append(dates, row.Transaction.Date)
+		}
+	}
+
+	return len(dates) > 1 && dates[0] > dates[len(dates)-1]
+}
+
+func record(portfolioCommandHandler command_handler.PortfolioCommandHandlerInterface, dividendCommandHandler dividend_command_handler.DividendCommandHandlerInterface, portfolioId shared.PortfolioId, transaction Transaction) error {
+	date := shared.CommandDate(transaction.Date)
+	switch transaction.Type {
+	case Buy:
+		return portfolioCommandHandler.HandleAddSharesToPortfolio(command.NewAddSharesToPortfolioCommand(portfolioId, transaction.Ticker, transaction.Shares, transaction.Price, transaction.Fee, transaction.Taxes, date))
+	case Sell:
+		return portfolioCommandHandler.HandleRemoveSharesFromPortfolio(command.NewRemoveSharesFromPortfolioCommand(portfolioId, transaction.Ticker, transaction.Shares, transaction.Price, transaction.Fee, transaction.Taxes, date, []portfolio.LotSelection{}))
+	default:
+		return dividendCommandHandler.HandleRecordDividend(dividend_command.NewRecordDividendCommand(portfolioId, transaction.Ticker, transaction.Net, transaction.Gross, date))
+	}
+}
+
+// counts the buys, sells and dividends already recorded by their key
+func recordedTransactions(portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream) (map[string]int, error) {
+	recorded := map[string]int{}
+	for _, eventStream := range []infrastructure.EventStream{portfolioEventStream, dividendEventStream} {
+		storedEvents, err := eventStream.Get()
+		if err != nil {
+			return nil, err
+		}
+		for _, storedEvent := range storedEvents {
+			domainEvent, err := event.Decode(storedEvent)
+			if err != nil {
+				return nil, err
+			}
+			switch domainEvent := domainEvent.(type) {
+			case *portfolio.SharesAddedToPortfolioEvent:
+				recorded[transactionKey(Transaction{Type: Buy, Ticker: domainEvent.Ticker(), Date: domainEvent.Date(), Shares: domainEvent.Shares(), Price: domainEvent.Price()})]++
+			case *portfolio.SharesRemovedFromPortfolioEvent:
+				recorded[transactionKey(Transaction{Type: Sell, Ticker: domainEvent.Ticker(), Date: domainEvent.Date(), Shares: domainEvent.Shares(), Price: domainEvent.Price()})]++
+			case *dividend.DividendRecordedEvent:
+				recorded[transactionKey(Transaction{Type: Dividend, Ticker: domainEvent.Ticker(), Date: domainEvent.Date(), Net: domainEvent.Net()})]++
+			}
+		}
+	}
+
+	return recorded, nil
+}
+
+// trades are identified by ticker, date, shares and price, dividends by ticker, date and net amount
+func transactionKey(transaction Transaction) string {
+	if transaction.Type == Dividend {
+		return transaction.Type + "|" + transaction.Ticker + "|" + transaction.Date + "|" + transaction.Net.String()
+	}
+
+	return transaction.Type + "|" + transaction.Ticker + "|" + transaction.Date + "|" + transaction.Shares.String() + "|" + transaction.Price.String()
+}
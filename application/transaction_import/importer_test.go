@@ -0,0 +1,213 @@
+package transaction_import_test
+
+import (
+	cash_command_handler "stock-monitor/application/cash/command_handler"
+	cash_persistence "stock-monitor/application/cash/persistence"
+	dividend_command_handler "stock-monitor/application/dividend/command_handler"
+	dividend_persistence "stock-monitor/application/dividend/persistence"
+	"stock-monitor/application/event"
+	"stock-monitor/application/portfolio/command_handler"
+	"stock-monitor/application/portfolio/persistence"
+	"stock-monitor/application/transaction_import"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"strings"
+	"testing"
+)
+
+const transactionsCsv = "date,type,ticker,shares,price,fee,amount,gross,currency\n" +
+	"2000-03-01,dividend,MO,,,,7.5,10,EUR\n" +
+	"2000-02-01,sell,MO,5,12.5,1,,,EUR\n" +
+	"2000-01-03,buy,MO,10,9.99,1,,,EUR\n" +
+	"2000-01-03,deposit,,,,,1000,,EUR\n" +
+	"2000-02-02,sell,MO,50,12.5,1,,,EUR\n"
+
+func readRows(t *testing.T, csv string) []transaction_import.Row {
+	rows, err := transaction_import.ReadTransactionsCsv(strings.NewReader(csv), transaction_import.Presets["generic"], "EUR")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	return rows
+}
+
+func makeImporter(portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream) transaction_import.Importer {
+	eventStreams := transaction_import.EventStreams{portfolioEventStream, dividendEventStream, &infrastructure.InMemoryEventStream{}}
+
+	return transaction_import.NewImporter(eventStreams, func(eventStreams transaction_import.EventStreams) (command_handler.PortfolioCommandHandlerInterface, dividend_command_handler.DividendCommandHandlerInterface) {
+		portfolioRepository := persistence.NewEventSourcedPortfolioRepository(eventStreams.Portfolio)
+		dividendRepository := dividend_persistence.NewEventSourcedDividendRepository(eventStreams.Portfolio)
+
+		return command_handler.NewCommandHandler(&portfolioRepository, event.NewEventPublisher(eventStreams.Portfolio)),
+			dividend_command_handler.NewDividendCommandHandler(&dividendRepository, event.NewEventPublisher(eventStreams.Dividend), &portfolioRepository, event.NewEventPublisher(eventStreams.Portfolio))
+	})
+}
+
+func statuses(report transaction_import.Report) []string {
+	got := []string{}
+	for _, row := range report.Rows {
+		got = append(got, row.Status)
+	}
+
+	return got
+}
+
+func TestTransactionsAreImportedOldestFirst(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+
+	report := importer.Import("default", readRows(t, transactionsCsv), false)
+
+	want := []string{transaction_import.Imported, transaction_import.Imported, transaction_import.Imported, transaction_import.Ignored, transaction_import.Failed}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, statuses(report))
+	}
+	if report.Rows[4].Line != 6 || report.Rows[4].Error == "" {
+		t.Errorf("Expected error of line 6 but got %#v", report.Rows[4])
+	}
+	if len(portfolioEventStream.Events) != 2 || portfolioEventStream.Events[0].Name != portfolio.SharesAddedToPortfolioEventName {
+		t.Errorf("Expected buy and sell to be recorded but got %#v", portfolioEventStream.Events)
+	}
+	if len(dividendEventStream.Events) != 1 || dividendEventStream.Events[0].Name != dividend.DividendRecordedEventName {
+		t.Errorf("Expected dividend to be recorded but got %#v", dividendEventStream.Events)
+	}
+}
+
+func TestDryRunRecordsNothing(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+
+	report := importer.Import("default", readRows(t, transactionsCsv), true)
+
+	want := []string{transaction_import.Ready, transaction_import.Ready, transaction_import.Ready, transaction_import.Ignored, transaction_import.Failed}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, statuses(report))
+	}
+	if report.Count(transaction_import.Ready) != 3 {
+		t.Errorf("Unexpected count. Expected:%#v Got:%#v", 3, report.Count(transaction_import.Ready))
+	}
+	if len(portfolioEventStream.Events) != 0 || len(dividendEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be recorded but got %#v and %#v", portfolioEventStream.Events, dividendEventStream.Events)
+	}
+}
+
+func TestRecordedTransactionsAreDuplicates(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+	importer.Import("default", readRows(t, transactionsCsv), false)
+
+	report := importer.Import("default", readRows(t, transactionsCsv+"2000-03-02,buy,MO,10,9.99,1,,,EUR\n"), false)
+
+	want := []string{transaction_import.Duplicate, transaction_import.Duplicate, transaction_import.Duplicate, transaction_import.Ignored, transaction_import.Failed, transaction_import.Imported}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, statuses(report))
+	}
+	if len(portfolioEventStream.Events) != 3 || len(dividendEventStream.Events) != 1 {
+		t.Errorf("Expected only the new buy to be recorded but got %#v and %#v", portfolioEventStream.Events, dividendEventStream.Events)
+	}
+}
+
+func TestEachRecordedTransactionMatchesOneRowOnly(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+	buy := "date,type,ticker,shares,price\n2000-01-03,buy,MO,10,9.99\n"
+	importer.Import("default", readRows(t, buy), false)
+
+	report := importer.Import("default", readRows(t, buy+"2000-01-03,buy,MO,10,9.99\n"), false)
+
+	want := []string{transaction_import.Duplicate, transaction_import.Imported}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, statuses(report))
+	}
+}
+
+func TestTransactionsOlderThanTheLastRecordedEventFail(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+	importer.Import("default", readRows(t, "date,type,ticker,shares,price\n2000-02-01,buy,MO,10,9.99\n"), false)
+
+	report := importer.Import("default", readRows(t, "date,type,ticker,shares,price\n2000-01-03,buy,KO,1,40\n2000-03-01,buy,KO,1,42\n"), false)
+
+	want := []string{transaction_import.Failed, transaction_import.Imported}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, report.Rows)
+	}
+	if !strings.Contains(report.Rows[0].Error, "older than occurredAt of last event") {
+		t.Errorf("Expected the backfilled row to fail for its date but got %#v", report.Rows[0].Error)
+	}
+	if len(portfolioEventStream.Events) != 2 {
+		t.Errorf("Expected only the newer buy to be recorded but got %#v", portfolioEventStream.Events)
+	}
+}
+
+func TestNoRowIsImportedWhenARecordedEventCanNotBeDecoded(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{
+		{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}},
+	}}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+
+	report := importer.Import("default", readRows(t, transactionsCsv), false)
+
+	if report.Count(transaction_import.Failed) != 5 {
+		t.Errorf("Expected all rows to fail but got %#v", statuses(report))
+	}
+	if len(portfolioEventStream.Events) != 1 || len(dividendEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be recorded but got %#v and %#v", portfolioEventStream.Events, dividendEventStream.Events)
+	}
+}
+
+func TestSameDayTransactionsOfNewestFirstExportsAreImportedBottomUp(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	importer := makeImporter(&portfolioEventStream, &dividendEventStream)
+	newestFirst := "date,type,ticker,shares,price\n" +
+		"2000-01-04,sell,MO,5,12.5\n" +
+		"2000-01-04,buy,MO,10,9.99\n" +
+		"2000-01-03,buy,MO,1,9.99\n"
+
+	report := importer.Import("default", readRows(t, newestFirst), false)
+
+	want := []string{transaction_import.Imported, transaction_import.Imported, transaction_import.Imported}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, report.Rows)
+	}
+	if len(portfolioEventStream.Events) != 3 || portfolioEventStream.Events[2].Name != portfolio.SharesRemovedFromPortfolioEventName {
+		t.Errorf("Expected the sell to be recorded last but got %#v", portfolioEventStream.Events)
+	}
+}
+
+func TestImportsGoThroughTheGivenCommandHandlers(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "100", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+		},
+	}
+	eventStreams := transaction_import.EventStreams{&portfolioEventStream, &dividendEventStream, &cashEventStream}
+	importer := transaction_import.NewImporter(eventStreams, func(eventStreams transaction_import.EventStreams) (command_handler.PortfolioCommandHandlerInterface, dividend_command_handler.DividendCommandHandlerInterface) {
+		portfolioRepository := persistence.NewEventSourcedPortfolioRepository(eventStreams.Portfolio)
+		cashRepository := cash_persistence.NewEventSourcedCashRepository(eventStreams.Cash, eventStreams.Portfolio, eventStreams.Dividend)
+
+		return cash_command_handler.NewCashCoveredCommandHandler(&portfolioRepository, event.NewEventPublisher(eventStreams.Portfolio), &cashRepository), nil
+	})
+
+	report := importer.Import("default", readRows(t, "date,type,ticker,shares,price\n2000-01-03,buy,MO,5,10\n2000-01-04,buy,MO,10,10\n"), true)
+
+	want := []string{transaction_import.Ready, transaction_import.Failed}
+	if strings.Join(statuses(report), ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected statuses. Expected:%#v Got:%#v", want, report.Rows)
+	}
+}
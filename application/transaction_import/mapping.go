@@ -0,0 +1,123 @@
+package transaction_import
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const (
+	Buy      = "BUY"
+	Sell     = "SELL"
+	Dividend = "DIVIDEND"
+)
+
+// ColumnMapping names the columns of a broker export by their header, empty columns are not read.
+// Types maps the transaction types of the broker to BUY, SELL or DIVIDEND, rows of other types are ignored.
+// Amount, Gross and Taxes of dividends are in AmountCurrency, prices, fees and taxes of trades in Currency.
+type ColumnMapping struct {
+	Delimiter      string            `json:"delimiter"`
+	DecimalComma   bool              `json:"decimal_comma"`
+	DateFormat     string            `json:"date_format"`
+	Date           string            `json:"date"`
+	Type           string            `json:"type"`
+	Ticker         string            `json:"ticker"`
+	Shares         string            `json:"shares"`
+	Price          string            `json:"price"`
+	Fee            string            `json:"fee"`
+	Taxes          string            `json:"taxes"`
+	Currency       string            `json:"currency"`
+	Amount         string            `json:"amount"`
+	Gross          string            `json:"gross"`
+	AmountCurrency string            `json:"amount_currency"`
+	Types          map[string]string `json:"types"`
+}
+
+// Presets are the column mappings of common brokers, "generic" reads files with the column names of the mapping
+var Presets = map[string]ColumnMapping{
+	"generic": {
+		Delimiter:  ",",
+		DateFormat: "2006-01-02",
+		Date:       "date",
+		Type:       "type",
+		Ticker:     "ticker",
+		Shares:     "shares",
+		Price:      "price",
+		Fee:        "fee",
+		Taxes:      "taxes",
+		Currency:   "currency",
+		Amount:     "amount",
+		Gross:      "gross",
+		Types:      map[string]string{"buy": Buy, "sell": Sell, "dividend": Dividend},
+	},
+	"trading212": {
+		Delimiter:      ",",
+		DateFormat:     "2006-01-02 15:04:05",
+		Date:           "Time",
+		Type:           "Action",
+		Ticker:         "Ticker",
+		Shares:         "No. of shares",
+		Price:          "Price / share",
+		Currency:       "Currency (Price / share)",
+		Amount:         "Total",
+		AmountCurrency: "Currency (Total)",
+		Types: map[string]string{
+			"Market buy":          Buy,
+			"Limit buy":           Buy,
+			"Stop buy":            Buy,
+			"Market sell":         Sell,
+			"Limit sell":          Sell,
+			"Stop sell":           Sell,
+			"Dividend (Ordinary)": Dividend,
+			"Dividend (Dividend)": Dividend,
+		},
+	},
+	"ibkr-flex": {
+		Delimiter:  ",",
+		DateFormat: "20060102",
+		Date:       "TradeDate",
+		Type:       "Buy/Sell",
+		Ticker:     "Symbol",
+		Shares:     "Quantity",
+		Price:      "TradePrice",
+		Fee:        "IBCommission",
+		Currency:   "CurrencyPrimary",
+		Types:      map[string]string{"BUY": Buy, "SELL": Sell},
+	},
+}
+
+func Preset(name string) (ColumnMapping, error) {
+	mapping, found := Presets[name]
+	if !found {
+		return ColumnMapping{}, NewUnknownPresetError(name)
+	}
+
+	return mapping, nil
+}
+
+// ReadColumnMapping reads a mapping from json, keys left out keep the values of the given preset,
+// types replace the types of the preset
+func ReadColumnMapping(reader io.Reader, preset ColumnMapping) (ColumnMapping, error) {
+	mapping := preset
+	mapping.Types = nil
+	err := json.NewDecoder(reader).Decode(&mapping)
+	if err != nil {
+		return ColumnMapping{}, err
+	}
+	if mapping.Types == nil {
+		mapping.Types = preset.Types
+	}
+
+	return mapping, nil
+}
+
+// type labels of the broker are matched ignoring case and surrounding spaces
+func (mapping ColumnMapping) transactionType(label string) (string, bool) {
+	for brokerType, transactionType := range mapping.Types {
+		if strings.EqualFold(strings.TrimSpace(brokerType), strings.TrimSpace(label)) {
+			return transactionType, true
+		}
+	}
+
+	return "", false
+}
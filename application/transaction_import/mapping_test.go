@@ -0,0 +1,36 @@
+package transaction_import_test
+
+import (
+	"reflect"
+	"stock-monitor/application/transaction_import"
+	"strings"
+	"testing"
+)
+
+func TestMappingFileOverridesThePreset(t *testing.T) {
+	preset, _ := transaction_import.Preset("generic")
+
+	got, err := transaction_import.ReadColumnMapping(strings.NewReader("{\"ticker\": \"isin\", \"types\": {\"kauf\": \"BUY\"}}"), preset)
+
+	want := preset
+	want.Ticker = "isin"
+	want.Types = map[string]string{"kauf": transaction_import.Buy}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected mapping. Expected:%#v Got:%#v", want, got)
+	}
+	if len(transaction_import.Presets["generic"].Types) != 3 {
+		t.Errorf("Expected preset to be unchanged but got %#v", transaction_import.Presets["generic"].Types)
+	}
+}
+
+func TestUnknownPresetIsRejected(t *testing.T) {
+	_, err := transaction_import.Preset("foo")
+
+	_, ok := err.(*transaction_import.UnknownPresetError)
+	if !ok {
+		t.Errorf("Expected UnknownPresetError but got %#v", err)
+	}
+}
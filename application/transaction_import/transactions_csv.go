@@ -0,0 +1,177 @@
+package transaction_import
+
+import (
+	"encoding/csv"
+	"io"
+	"stock-monitor/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Transaction is a buy or sell of Shares at Price or a dividend paying Net of Gross
+type Transaction struct {
+	Type   string
+	Ticker string
+	Date   string
+	Shares domain.Quantity
+	Price  domain.Money
+	Fee    domain.Money
+	Taxes  domain.Money
+	Net    domain.Money
+	Gross  domain.Money
+}
+
+// Row is the transaction read from a line of the csv file or the reason it could not be read
+type Row struct {
+	Line        int
+	Transaction Transaction
+	Error       error
+}
+
+// ReadTransactionsCsv reads one transaction per row, rows that can't be read are returned with their error.
+// Signs are dropped since brokers record sells or fees as negative numbers. Amounts without currency column
+// are in the given currency.
+func ReadTransactionsCsv(reader io.Reader, mapping ColumnMapping, currency string) ([]Row, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	if mapping.Delimiter != "" {
+		csvReader.Comma, _ = utf8.DecodeRuneInString(mapping.Delimiter)
+	}
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, NewInvalidTransactionCsvError(1, "header missing")
+	}
+
+	// exports saved by spreadsheets start with a byte order mark
+	columns := map[string]int{}
+	for index, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = index
+	}
+	for _, required := range []string{mapping.Date, mapping.Type, mapping.Ticker} {
+		if _, found := columns[strings.ToLower(required)]; !found {
+			return nil, NewInvalidTransactionCsvError(1, "column missing: "+required)
+		}
+	}
+
+	rows := []Row{}
+	for index, record := range records[1:] {
+		line := index + 2
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		value := func(column string) string {
+			position, found := columns[strings.ToLower(column)]
+			if column == "" || !found || position >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[position])
+		}
+
+		transaction, err := readTransaction(value, mapping, currency)
+		rows = append(rows, Row{line, transaction, err})
+	}
+
+	return rows, nil
+}
+
+func readTransaction(value func(column string) string, mapping ColumnMapping, currency string) (Transaction, error) {
+	transactionType, found := mapping.transactionType(value(mapping.Type))
+	if !found {
+		return Transaction{}, NewUnmappedTransactionTypeError(value(mapping.Type))
+	}
+
+	ticker := value(mapping.Ticker)
+	if ticker == "" {
+		return Transaction{}, NewInvalidTransactionValueError(mapping.Ticker, ticker)
+	}
+	date, err := time.Parse(mapping.DateFormat, value(mapping.Date))
+	if err != nil {
+		return Transaction{}, NewInvalidTransactionValueError(mapping.Date, value(mapping.Date))
+	}
+
+	priceCurrency := currency
+	if value(mapping.Currency) != "" {
+		priceCurrency = value(mapping.Currency)
+	}
+	amountCurrency := priceCurrency
+	if value(mapping.AmountCurrency) != "" {
+		amountCurrency = value(mapping.AmountCurrency)
+	}
+
+	transaction := Transaction{Type: transactionType, Ticker: ticker, Date: date.Format("2006-01-02")}
+	if transactionType == Dividend {
+		return readDividend(transaction, value, mapping, amountCurrency)
+	}
+
+	transaction.Shares, err = domain.NewQuantity(normalizeNumber(value(mapping.Shares), mapping.DecimalComma))
+	if err != nil || !transaction.Shares.IsPositive() {
+		return Transaction{}, NewInvalidTransactionValueError(mapping.Shares, value(mapping.Shares))
+	}
+	transaction.Price, err = moneyValue(value, mapping.Price, mapping.DecimalComma, priceCurrency, true)
+	if err != nil {
+		return Transaction{}, err
+	}
+	transaction.Fee, err = moneyValue(value, mapping.Fee, mapping.DecimalComma, priceCurrency, false)
+	if err != nil {
+		return Transaction{}, err
+	}
+	transaction.Taxes, err = moneyValue(value, mapping.Taxes, mapping.DecimalComma, priceCurrency, false)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// without gross the dividend is taxed with the taxes of the row, if any
+func readDividend(transaction Transaction, value func(column string) string, mapping ColumnMapping, currency string) (Transaction, error) {
+	var err error
+	transaction.Net, err = moneyValue(value, mapping.Amount, mapping.DecimalComma, currency, true)
+	if err != nil {
+		return Transaction{}, err
+	}
+	transaction.Gross, err = moneyValue(value, mapping.Gross, mapping.DecimalComma, currency, false)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if transaction.Gross.IsZero() {
+		taxes, err := moneyValue(value, mapping.Taxes, mapping.DecimalComma, currency, false)
+		if err != nil {
+			return Transaction{}, err
+		}
+		transaction.Gross = transaction.Net
+		if taxes.IsPositive() {
+			transaction.Gross, _ = transaction.Net.Add(taxes)
+		}
+	}
+
+	return transaction, nil
+}
+
+// missing optional amounts are zero without currency, like amounts left out of http requests
+func moneyValue(value func(column string) string, column string, decimalComma bool, currency string, required bool) (domain.Money, error) {
+	if value(column) == "" && !required {
+		return domain.Money{}, nil
+	}
+
+	money, err := domain.NewMoney(normalizeNumber(value(column), decimalComma), currency)
+	if err != nil || (required && !money.IsPositive()) {
+		return domain.Money{}, NewInvalidTransactionValueError(column, value(column))
+	}
+
+	return money, nil
+}
+
+// "-1.234,5" with decimal comma and "-1,234.5" without are both read as "1234.5"
+func normalizeNumber(number string, decimalComma bool) string {
+	number = strings.TrimLeft(strings.TrimSpace(number), "+-")
+	if decimalComma {
+		return strings.Replace(strings.ReplaceAll(number, ".", ""), ",", ".", 1)
+	}
+
+	return strings.ReplaceAll(number, ",", "")
+}
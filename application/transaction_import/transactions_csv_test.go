@@ -0,0 +1,99 @@
+package transaction_import_test
+
+import (
+	"reflect"
+	"stock-monitor/application/transaction_import"
+	"stock-monitor/domain"
+	"strings"
+	"testing"
+)
+
+func TestTransactionsAreReadWithGenericMapping(t *testing.T) {
+	csv := "date,type,ticker,shares,price,fee,taxes,amount,gross,currency\n" +
+		"2000-01-03,buy,MO,10,9.99,1,,,,USD\n" +
+		"2000-02-01,Sell,MO,-5,12.5,1,0.5,,,USD\n" +
+		"2000-03-01,dividend,MO,,,,,7.5,10,USD\n"
+
+	got, err := transaction_import.ReadTransactionsCsv(strings.NewReader(csv), transaction_import.Presets["generic"], "EUR")
+
+	want := []transaction_import.Row{
+		{2, transaction_import.Transaction{transaction_import.Buy, "MO", "2000-01-03", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "USD"), domain.NewMoneyFromFloat(1, "USD"), domain.Money{}, domain.Money{}, domain.Money{}}, nil},
+		{3, transaction_import.Transaction{transaction_import.Sell, "MO", "2000-02-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(12.5, "USD"), domain.NewMoneyFromFloat(1, "USD"), domain.NewMoneyFromFloat(0.5, "USD"), domain.Money{}, domain.Money{}}, nil},
+		{4, transaction_import.Transaction{transaction_import.Dividend, "MO", "2000-03-01", domain.Quantity{}, domain.Money{}, domain.Money{}, domain.Money{}, domain.NewMoneyFromFloat(7.5, "USD"), domain.NewMoneyFromFloat(10, "USD")}, nil},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected rows. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestTransactionsAreReadWithCustomMapping(t *testing.T) {
+	csv := "Datum;Typ;Wertpapier;Stück;Kurs;Betrag;Steuer\n" +
+		"03.01.2000;Kauf;MO;1.000;9,99;;\n" +
+		"01.03.2000;Ausschüttung;MO;;;7,50;2,50\n"
+	mapping := transaction_import.ColumnMapping{
+		Delimiter:    ";",
+		DecimalComma: true,
+		DateFormat:   "02.01.2006",
+		Date:         "Datum",
+		Type:         "Typ",
+		Ticker:       "Wertpapier",
+		Shares:       "Stück",
+		Price:        "Kurs",
+		Taxes:        "Steuer",
+		Amount:       "Betrag",
+		Types:        map[string]string{"kauf": transaction_import.Buy, "ausschüttung": transaction_import.Dividend},
+	}
+
+	got, err := transaction_import.ReadTransactionsCsv(strings.NewReader(csv), mapping, "EUR")
+
+	want := []transaction_import.Row{
+		{2, transaction_import.Transaction{transaction_import.Buy, "MO", "2000-01-03", domain.NewQuantityFromInt(1000), domain.NewMoneyFromFloat(9.99, "EUR"), domain.Money{}, domain.Money{}, domain.Money{}, domain.Money{}}, nil},
+		{3, transaction_import.Transaction{transaction_import.Dividend, "MO", "2000-03-01", domain.Quantity{}, domain.Money{}, domain.Money{}, domain.Money{}, domain.NewMoneyFromFloat(7.5, "EUR"), domain.NewMoneyFromFloat(10, "EUR")}, nil},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected rows. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestRowsThatCanNotBeReadAreReturnedWithTheirError(t *testing.T) {
+	csv := "date,type,ticker,shares,price\n" +
+		"2000-01-03,deposit,,,\n" +
+		"03.01.2000,buy,MO,10,9.99\n" +
+		"2000-01-03,buy,MO,ten,9.99\n" +
+		"2000-01-03,buy,MO,10,0\n"
+
+	got, err := transaction_import.ReadTransactionsCsv(strings.NewReader(csv), transaction_import.Presets["generic"], "EUR")
+
+	want := []error{
+		transaction_import.NewUnmappedTransactionTypeError("deposit"),
+		transaction_import.NewInvalidTransactionValueError("date", "03.01.2000"),
+		transaction_import.NewInvalidTransactionValueError("shares", "ten"),
+		transaction_import.NewInvalidTransactionValueError("price", "0"),
+	}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Unexpected rows. Expected %d Got:%#v", len(want), got)
+	}
+	for key, row := range got {
+		if row.Line != key+2 || reflect.DeepEqual(row.Error, want[key]) == false {
+			t.Errorf("Unexpected row. Expected error %#v in line %d Got:%#v", want[key], key+2, row)
+		}
+	}
+}
+
+func TestCsvWithoutRequiredColumnIsRejected(t *testing.T) {
+	_, err := transaction_import.ReadTransactionsCsv(strings.NewReader("date,ticker,shares\n2000-01-03,MO,10\n"), transaction_import.Presets["generic"], "EUR")
+
+	_, ok := err.(*transaction_import.InvalidTransactionCsvError)
+	if !ok {
+		t.Errorf("Expected InvalidTransactionCsvError but got %#v", err)
+	}
+}
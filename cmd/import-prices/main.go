@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"stock-monitor/domain"
+	"stock-monitor/query"
+
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:      "import-prices",
+		Usage:     "imports daily OHLC prices of a ticker from a csv file into the price store",
+		ArgsUsage: "<ticker> <csv file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "currency",
+				Value: domain.DefaultCurrency,
+				Usage: "currency of the prices",
+			},
+			&cli.StringFlag{
+				Name:    "store",
+				Value:   os.Getenv("EVENT_STREAM_STORAGE_PATH") + os.Getenv("PRICE_HISTORY_FILE"),
+				Usage:   "json file of the price store",
+				EnvVars: []string{"PRICE_STORE"},
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 2 {
+				return cli.Exit("a ticker and a csv file are required", 1)
+			}
+			if c.String("store") == "" {
+				return cli.Exit("the price store is required", 1)
+			}
+
+			return importPrices(c.Args().Get(0), c.Args().Get(1), c.String("currency"), c.String("store"))
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func importPrices(ticker string, csvFile string, currency string, storeFile string) error {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	prices, err := query.ReadDailyPricesCsv(file, currency)
+	if err != nil {
+		return err
+	}
+
+	err = query.NewFilePriceStore(storeFile).Save(ticker, prices)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("imported %d daily prices of %s into %s\n", len(prices), ticker, storeFile)
+
+	return nil
+}
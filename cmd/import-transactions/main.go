@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"stock-monitor/application/shared"
+	"stock-monitor/application/transaction_import"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure/di"
+
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:      "import-transactions",
+		Usage:     "imports buys, sells and dividends of a portfolio from a csv export of a broker",
+		ArgsUsage: "<csv file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "broker",
+				Value: "generic",
+				Usage: "column mapping preset: generic, trading212 or ibkr-flex",
+			},
+			&cli.StringFlag{
+				Name:  "mapping",
+				Usage: "json file with a column mapping, keys left out are taken from the preset",
+			},
+			&cli.StringFlag{
+				Name:  "currency",
+				Value: domain.DefaultCurrency,
+				Usage: "currency of amounts without currency column",
+			},
+			&cli.StringFlag{
+				Name:  "portfolio",
+				Value: shared.DefaultPortfolioId,
+				Usage: "id of the portfolio",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "reports what would be imported without recording anything",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("a csv file is required", 1)
+			}
+			if !knownPortfolio(c.String("portfolio")) {
+				return shared.NewUnknownPortfolioError(c.String("portfolio"))
+			}
+
+			mapping, err := columnMapping(c.String("broker"), c.String("mapping"))
+			if err != nil {
+				return err
+			}
+
+			return importTransactions(c.Args().Get(0), mapping, c.String("currency"), c.String("portfolio"), c.Bool("dry-run"))
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func knownPortfolio(portfolioId string) bool {
+	for _, knownPortfolioId := range di.PortfolioIds() {
+		if knownPortfolioId == portfolioId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func columnMapping(broker string, mappingFile string) (transaction_import.ColumnMapping, error) {
+	mapping, err := transaction_import.Preset(broker)
+	if err != nil || mappingFile == "" {
+		return mapping, err
+	}
+
+	file, err := os.Open(mappingFile)
+	if err != nil {
+		return transaction_import.ColumnMapping{}, err
+	}
+	defer file.Close()
+
+	return transaction_import.ReadColumnMapping(file, mapping)
+}
+
+func importTransactions(csvFile string, mapping transaction_import.ColumnMapping, currency string, portfolioId string, dryRun bool) error {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := transaction_import.ReadTransactionsCsv(file, mapping, currency)
+	if err != nil {
+		return err
+	}
+
+	importer := di.MakeTransactionImporter(portfolioId)
+	report := importer.Import(portfolioId, rows, dryRun)
+
+	for _, row := range report.Rows {
+		fmt.Printf("line %d: %s %s\n", row.Line, row.Status, describe(row))
+	}
+	fmt.Printf("%d imported, %d ready, %d duplicates, %d ignored, %d failed\n",
+		report.Count(transaction_import.Imported),
+		report.Count(transaction_import.Ready),
+		report.Count(transaction_import.Duplicate),
+		report.Count(transaction_import.Ignored),
+		report.Count(transaction_import.Failed),
+	)
+
+	return nil
+}
+
+func describe(row transaction_import.RowResult) string {
+	if row.Error != "" {
+		return row.Error
+	}
+
+	transaction := row.Transaction
+	if transaction.Type == transaction_import.Dividend {
+		return fmt.Sprintf("%s %s %s net %s gross %s", transaction.Date, transaction.Type, transaction.Ticker, transaction.Net, transaction.Gross)
+	}
+
+	return fmt.Sprintf("%s %s %s %s shares at %s", transaction.Date, transaction.Type, transaction.Ticker, transaction.Shares, transaction.Price)
+}
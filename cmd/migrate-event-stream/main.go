@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"stock-monitor/application/event"
+	"stock-monitor/infrastructure"
+	"stock-monitor/infrastructure/di"
+
+	"github.com/urfave/cli/v2"
+)
+
+func main() {
+	app := &cli.App{
+		Name:      "migrate-event-stream",
+		Usage:     "converts gob event stream files into json lines event stream files",
+		ArgsUsage: "[<gob file>...]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return migrateConfiguredStreams()
+			}
+
+			for _, gobFile := range c.Args().Slice() {
+				err := migrate(gobFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// streams that were never written have no gob file and are left out
+func migrateConfiguredStreams() error {
+	for _, gobFile := range di.EventStreamFiles() {
+		if _, err := os.Stat(gobFile); os.IsNotExist(err) {
+			continue
+		}
+
+		err := migrate(gobFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func migrate(gobFile string) error {
+	storagePath := filepath.Dir(gobFile) + string(filepath.Separator)
+	fileName := filepath.Base(gobFile)
+	jsonLinesFile := infrastructure.JsonLinesFileName(fileName)
+
+	if _, err := os.Stat(gobFile); err != nil {
+		return err
+	}
+
+	from := infrastructure.FileSystemEventStream{storagePath, fileName}
+	events, err := from.Get()
+	if err != nil {
+		return err
+	}
+
+	upcasted := infrastructure.InMemoryEventStream{}
+	for _, storedEvent := range events {
+		current, err := event.DefaultRegistry.Upcast(storedEvent)
+		if err != nil {
+			return err
+		}
+		upcasted.Events = append(upcasted.Events, current)
+	}
+
+	to := infrastructure.JsonLinesEventStream{storagePath, jsonLinesFile}
+
+	err = infrastructure.MigrateToJsonLines(&upcasted, &to)
+	if err != nil {
+		return err
+	}
+
+	version, err := to.Version()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("migrated %d events from %s to %s\n", version, gobFile, storagePath+jsonLinesFile)
+
+	return nil
+}
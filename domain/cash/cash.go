@@ -0,0 +1,122 @@
+package cash
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+)
+
+// Cash keeps a balance per currency, buys are debited and sales and net dividends are credited
+type Cash struct {
+	balances map[string]domain.Money
+	events   []domain.DomainEvent
+}
+
+func NewCash() Cash {
+	return Cash{map[string]domain.Money{}, []domain.DomainEvent{}}
+}
+
+func (cash *Cash) Deposit(amount domain.Money, date string) error {
+	if !amount.IsPositive() {
+		return &CashAmountZeroOrNegativeError{}
+	}
+
+	cashDepositedEvent := NewCashDepositedEvent(amount, date)
+	cash.events = append(cash.events, &cashDepositedEvent)
+
+	return nil
+}
+
+func (cash *Cash) Withdraw(amount domain.Money, date string) error {
+	if !amount.IsPositive() {
+		return &CashAmountZeroOrNegativeError{}
+	}
+	err := cash.CanPay(amount)
+	if err != nil {
+		return err
+	}
+
+	cashWithdrawnEvent := NewCashWithdrawnEvent(amount, date)
+	cash.events = append(cash.events, &cashWithdrawnEvent)
+
+	return nil
+}
+
+func (cash *Cash) CanPay(amount domain.Money) error {
+	balance := cash.Balance(amount.Currency())
+	remaining, _ := balance.Sub(amount)
+	if remaining.IsNegative() {
+		return NewInsufficientCashError(amount.String(), balance.String())
+	}
+
+	return nil
+}
+
+func (cash *Cash) Balance(currency string) domain.Money {
+	balance, found := cash.balances[currency]
+	if !found {
+		return domain.NewMoneyFromFloat(0, currency)
+	}
+
+	return balance
+}
+
+func (cash *Cash) GetRecordedEvents() []domain.DomainEvent {
+	return cash.events
+}
+
+func (cash *Cash) Apply(event domain.DomainEvent) {
+	switch event := event.(type) {
+	case *CashDepositedEvent:
+		cash.book(event.Amount())
+	case *CashWithdrawnEvent:
+		cash.book(negate(event.Amount()))
+	case *portfolio.SharesAddedToPortfolioEvent:
+		cash.book(negate(BuyCost(event.Price(), event.Shares(), event.Fee(), event.Taxes())))
+	case *portfolio.SharesRemovedFromPortfolioEvent:
+		cash.book(SaleProceeds(event.Price(), event.Shares(), event.Fee(), event.Taxes()))
+	case *portfolio.SharesConvertedEvent:
+		if event.CashInLieu().IsPositive() {
+			cash.book(event.CashInLieu())
+		}
+	case *dividend.DividendRecordedEvent:
+		cash.book(event.Net())
+	}
+}
+
+func (cash *Cash) book(amount domain.Money) {
+	balance, _ := cash.Balance(amount.Currency()).Add(amount)
+	cash.balances[amount.Currency()] = balance
+}
+
+// BuyCost is the amount paid for a buy including fees and taxes
+func BuyCost(price domain.Money, shares domain.Quantity, fee domain.Money, taxes domain.Money) domain.Money {
+	cost := price.MulQuantity(shares)
+	for _, charge := range []domain.Money{fee, taxes} {
+		sum, err := cost.Add(charge)
+		if err == nil {
+			cost = sum
+		}
+	}
+
+	return cost
+}
+
+// SaleProceeds is the amount received for a sale after fees and taxes
+func SaleProceeds(price domain.Money, shares domain.Quantity, fee domain.Money, taxes domain.Money) domain.Money {
+	proceeds := price.MulQuantity(shares)
+	for _, charge := range []domain.Money{fee, taxes} {
+		difference, err := proceeds.Sub(charge)
+		if err == nil {
+			proceeds = difference
+		}
+	}
+
+	return proceeds
+}
+
+func negate(amount domain.Money) domain.Money {
+	negated, _ := domain.NewMoneyFromFloat(0, amount.Currency()).Sub(amount)
+
+	return negated
+}
@@ -0,0 +1,129 @@
+package cash_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"testing"
+)
+
+func TestCanDepositCash(t *testing.T) {
+	c := cash.NewCash()
+
+	err := c.Deposit(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+
+	expectedEvent := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+	expectedEvents := []domain.DomainEvent{&expectedEvent}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(c.GetRecordedEvents(), expectedEvents) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEvents, c.GetRecordedEvents())
+	}
+}
+
+func TestDepositHasToBeGreaterThanZero(t *testing.T) {
+	c := cash.NewCash()
+
+	err := c.Deposit(domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+
+	_, ok := err.(*cash.CashAmountZeroOrNegativeError)
+	if !ok {
+		t.Errorf("Expected CashAmountZeroOrNegativeError but got %#v", err)
+	}
+}
+
+func TestCanWithdrawCash(t *testing.T) {
+	c := cash.NewCash()
+	depositedEvent := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+	c.Apply(&depositedEvent)
+
+	err := c.Withdraw(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-02")
+
+	expectedEvent := cash.NewCashWithdrawnEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-02")
+	expectedEvents := []domain.DomainEvent{&expectedEvent}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(c.GetRecordedEvents(), expectedEvents) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEvents, c.GetRecordedEvents())
+	}
+}
+
+func TestCanNotWithdrawMoreThanTheBalance(t *testing.T) {
+	c := cash.NewCash()
+	depositedEvent := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+	c.Apply(&depositedEvent)
+
+	err := c.Withdraw(domain.NewMoneyFromFloat(1000.01, "EUR"), "2000-01-02")
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+}
+
+func TestCanNotWithdrawCurrencyThatWasNotDeposited(t *testing.T) {
+	c := cash.NewCash()
+	depositedEvent := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+	c.Apply(&depositedEvent)
+
+	err := c.Withdraw(domain.NewMoneyFromFloat(10, "USD"), "2000-01-02")
+
+	_, ok := err.(*cash.InsufficientCashError)
+	if !ok {
+		t.Errorf("Expected InsufficientCashError but got %#v", err)
+	}
+}
+
+func TestOrdersAndDividendsAreSettledInCash(t *testing.T) {
+	c := cash.NewCash()
+	depositedEvent := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(50, "EUR"), domain.NewMoneyFromFloat(4.95, "EUR"), domain.NewMoneyFromFloat(0.05, "EUR"), "2000-01-02")
+	sharesRemovedEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(60, "EUR"), domain.NewMoneyFromFloat(4.95, "EUR"), domain.NewMoneyFromFloat(5.05, "EUR"), "2000-01-03", []portfolio.SelectedLot{})
+	dividendRecordedEvent := dividend.NewDividendRecordedEvent("MO", domain.NewMoneyFromFloat(7.5, "EUR"), domain.NewMoneyFromFloat(10, "EUR"), "2000-01-04")
+	withdrawnEvent := cash.NewCashWithdrawnEvent(domain.NewMoneyFromFloat(100, "EUR"), "2000-01-05")
+	c.Apply(&depositedEvent)
+	c.Apply(&sharesAddedEvent)
+	c.Apply(&sharesRemovedEvent)
+	c.Apply(&dividendRecordedEvent)
+	c.Apply(&withdrawnEvent)
+
+	expected := domain.NewMoneyFromFloat(692.5, "EUR")
+	got := c.Balance("EUR")
+
+	if reflect.DeepEqual(got.String(), expected.String()) == false {
+		t.Errorf("Unexpected balance. Expected:%#v Got:%#v", expected.String(), got.String())
+	}
+}
+
+func TestCashInLieuOfConvertedSharesIsCredited(t *testing.T) {
+	c := cash.NewCash()
+	fractionalShares, _ := domain.NewQuantity("0.5")
+	sharesConvertedEvent := portfolio.NewSharesConvertedEvent("MO", "PM", 3, 2, fractionalShares, domain.NewMoneyFromFloat(12.5, "USD"), "2000-01-02")
+	c.Apply(&sharesConvertedEvent)
+
+	expected := domain.NewMoneyFromFloat(12.5, "USD")
+	got := c.Balance("USD")
+
+	if reflect.DeepEqual(got.String(), expected.String()) == false {
+		t.Errorf("Unexpected balance. Expected:%#v Got:%#v", expected.String(), got.String())
+	}
+}
+
+func TestBuysCanBeCheckedAgainstTheBalance(t *testing.T) {
+	c := cash.NewCash()
+	depositedEvent := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(100, "EUR"), "2000-01-01")
+	c.Apply(&depositedEvent)
+
+	if err := c.CanPay(domain.NewMoneyFromFloat(100, "EUR")); err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if _, ok := c.CanPay(domain.NewMoneyFromFloat(100.01, "EUR")).(*cash.InsufficientCashError); !ok {
+		t.Errorf("Expected InsufficientCashError")
+	}
+}
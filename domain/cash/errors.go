@@ -0,0 +1,20 @@
+package cash
+
+type CashAmountZeroOrNegativeError struct{}
+
+type InsufficientCashError struct {
+	required  string
+	available string
+}
+
+func NewInsufficientCashError(required string, available string) *InsufficientCashError {
+	return &InsufficientCashError{required: required, available: available}
+}
+
+func (e *CashAmountZeroOrNegativeError) Error() string {
+	return "cash amount must be greater than zero"
+}
+
+func (e *InsufficientCashError) Error() string {
+	return "not enough cash. required: " + e.required + " available: " + e.available
+}
@@ -0,0 +1,28 @@
+package cash_test
+
+import (
+	"stock-monitor/domain/cash"
+	"testing"
+)
+
+func TestCashAmountZeroOrNegativeError(t *testing.T) {
+	err := cash.CashAmountZeroOrNegativeError{}
+
+	expected := "cash amount must be greater than zero"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInsufficientCashError(t *testing.T) {
+	err := cash.NewInsufficientCashError("100 EUR", "50 EUR")
+
+	expected := "not enough cash. required: 100 EUR available: 50 EUR"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
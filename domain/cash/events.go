@@ -0,0 +1,75 @@
+package cash
+
+import "stock-monitor/domain"
+
+const CashDepositedEventName = "Cash.CashDeposited"
+const CashWithdrawnEventName = "Cash.CashWithdrawn"
+
+const CashDepositedEventVersion = 1
+const CashWithdrawnEventVersion = 1
+
+type CashDepositedEvent struct {
+	amount domain.Money
+	date   string
+}
+
+func NewCashDepositedEvent(amount domain.Money, date string) CashDepositedEvent {
+	return CashDepositedEvent{amount: amount, date: date}
+}
+
+func (event *CashDepositedEvent) Name() string {
+	return CashDepositedEventName
+}
+
+func (event *CashDepositedEvent) Version() int {
+	return CashDepositedEventVersion
+}
+
+func (event *CashDepositedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"amount":   event.amount.Amount(),
+		"currency": event.amount.Currency(),
+		"date":     event.date,
+	}
+}
+
+func (event *CashDepositedEvent) Amount() domain.Money {
+	return event.amount
+}
+
+func (event *CashDepositedEvent) Date() string {
+	return event.date
+}
+
+type CashWithdrawnEvent struct {
+	amount domain.Money
+	date   string
+}
+
+func NewCashWithdrawnEvent(amount domain.Money, date string) CashWithdrawnEvent {
+	return CashWithdrawnEvent{amount: amount, date: date}
+}
+
+func (event *CashWithdrawnEvent) Name() string {
+	return CashWithdrawnEventName
+}
+
+func (event *CashWithdrawnEvent) Version() int {
+	return CashWithdrawnEventVersion
+}
+
+func (event *CashWithdrawnEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"amount":   event.amount.Amount(),
+		"currency": event.amount.Currency(),
+		"date":     event.date,
+	}
+}
+
+func (event *CashWithdrawnEvent) Amount() domain.Money {
+	return event.amount
+}
+
+func (event *CashWithdrawnEvent) Date() string {
+	return event.date
+}
@@ -0,0 +1,42 @@
+package cash_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"testing"
+)
+
+func TestCashDepositedEventCanBeCreated(t *testing.T) {
+	event := cash.NewCashDepositedEvent(domain.NewMoneyFromFloat(1000, "EUR"), "2000-01-01")
+
+	if event.Name() != cash.CashDepositedEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", cash.CashDepositedEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"amount":   "1000",
+		"currency": "EUR",
+		"date":     "2000-01-01",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
+
+func TestCashWithdrawnEventCanBeCreated(t *testing.T) {
+	event := cash.NewCashWithdrawnEvent(domain.NewMoneyFromFloat(99.5, "USD"), "2000-01-01")
+
+	if event.Name() != cash.CashWithdrawnEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", cash.CashWithdrawnEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"amount":   "99.5",
+		"currency": "USD",
+		"date":     "2000-01-01",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
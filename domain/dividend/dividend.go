@@ -15,7 +15,7 @@ func NewDividend() Dividend {
 	return Dividend{map[string]string{}, []domain.DomainEvent{}}
 }
 
-func (d *Dividend) RecordDividend(ticker string, net float32, gross float32, date string) error {
+func (d *Dividend) RecordDividend(ticker string, net domain.Money, gross domain.Money, date string) error {
 	stockAddedDate, found := d.Positions[ticker]
 	if !found {
 		return NewTickerUnknownError(ticker)
@@ -23,12 +23,15 @@ func (d *Dividend) RecordDividend(ticker string, net float32, gross float32, dat
 	if !dividendRecordedAfterStockWasAdded(date, stockAddedDate) {
 		return NewDividendDateBeforeSharesWereAddedToPortfolioError(ticker, date)
 	}
-	if net <= 0 {
+	if !net.IsPositive() {
 		return &DividendNetZeroOrNegativeError{}
 	}
-	if gross <= 0 {
+	if !gross.IsPositive() {
 		return &DividendGrossZeroOrNegativeError{}
 	}
+	if net.Currency() != gross.Currency() {
+		return domain.NewCurrencyMismatchError(net.Currency(), gross.Currency())
+	}
 
 	dividendRecordedEvent := NewDividendRecordedEvent(ticker, net, gross, date)
 	d.events = append(d.events, &dividendRecordedEvent)
@@ -42,8 +45,9 @@ func (d *Dividend) GetRecordedEvents() []domain.DomainEvent {
 
 func (d *Dividend) Apply(event domain.DomainEvent) {
 	if event.Name() == portfolio.SharesAddedToPortfolioEventName {
-		ticker := event.Payload()["ticker"].(string)
-		date := event.Payload()["date"].(string)
+		sharesAddedToPortfolioEvent := event.(*portfolio.SharesAddedToPortfolioEvent)
+		ticker := sharesAddedToPortfolioEvent.Ticker()
+		date := sharesAddedToPortfolioEvent.Date()
 		_, found := d.Positions[ticker]
 		if found {
 			return
@@ -52,11 +56,31 @@ func (d *Dividend) Apply(event domain.DomainEvent) {
 	}
 
 	if event.Name() == portfolio.TickerRenamedEventName {
-		oldTicker := event.Payload()["old"].(string)
-		newTicker := event.Payload()["new"].(string)
+		tickerRenamedEvent := event.(*portfolio.TickerRenamedEvent)
+		oldTicker := tickerRenamedEvent.Old()
+		newTicker := tickerRenamedEvent.New()
 		addedDate := d.Positions[oldTicker]
 		d.Positions[newTicker] = addedDate
 	}
+
+	// converted shares keep the eligibility of the earliest shares held
+	if event.Name() == portfolio.SharesConvertedEventName {
+		sharesConvertedEvent := event.(*portfolio.SharesConvertedEvent)
+		addedDate := d.Positions[sharesConvertedEvent.Ticker()]
+		newAddedDate, found := d.Positions[sharesConvertedEvent.NewTicker()]
+		if !found || addedDate < newAddedDate {
+			d.Positions[sharesConvertedEvent.NewTicker()] = addedDate
+		}
+	}
+
+	// spun off shares are held from the spin-off
+	if event.Name() == portfolio.SharesSpunOffEventName {
+		sharesSpunOffEvent := event.(*portfolio.SharesSpunOffEvent)
+		_, found := d.Positions[sharesSpunOffEvent.NewTicker()]
+		if !found {
+			d.Positions[sharesSpunOffEvent.NewTicker()] = sharesSpunOffEvent.Date()
+		}
+	}
 }
 
 func dividendRecordedAfterStockWasAdded(dividendRecorded string, stockAdded string) bool {
@@ -10,14 +10,14 @@ import (
 
 func TestCanRecordADividend(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	d.Apply(&sharesAddedEvent)
 
-	err := d.RecordDividend("MO", 20.00, 30.00, "2000-01-02")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
 
 	events := d.GetRecordedEvents()
 
-	expectedEvent := dividend.NewDividendRecordedEvent("MO", 20.00, 30.00, "2000-01-02")
+	expectedEvent := dividend.NewDividendRecordedEvent("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
 	expectedEvents := []domain.DomainEvent{
 		&expectedEvent,
 	}
@@ -33,7 +33,7 @@ func TestCanRecordADividend(t *testing.T) {
 func TestCanNotRecordADividendWhenTickerWasNotAddedToPortfolio(t *testing.T) {
 	d := dividend.NewDividend()
 
-	err := d.RecordDividend("MO", 20.00, 30.00, "2000-01-01")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-01")
 
 	_, ok := err.(*dividend.TickerUnknownError)
 	if !ok {
@@ -43,10 +43,10 @@ func TestCanNotRecordADividendWhenTickerWasNotAddedToPortfolio(t *testing.T) {
 
 func TestCanNotRecordADividendWhenTickerWasAddedToPortfolioLaterThanDividendDate(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	d.Apply(&sharesAddedEvent)
 
-	err := d.RecordDividend("MO", 20.00, 30.00, "2000-01-01")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-01")
 
 	_, ok := err.(*dividend.DividendDateBeforeSharesWereAddedToPortfolioError)
 	if !ok {
@@ -56,10 +56,10 @@ func TestCanNotRecordADividendWhenTickerWasAddedToPortfolioLaterThanDividendDate
 
 func TestDividendNetHasToBeGreaterThanZero(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	d.Apply(&sharesAddedEvent)
 
-	err := d.RecordDividend("MO", 0, 30.00, "2000-01-02")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
 
 	_, ok := err.(*dividend.DividendNetZeroOrNegativeError)
 	if !ok {
@@ -69,10 +69,10 @@ func TestDividendNetHasToBeGreaterThanZero(t *testing.T) {
 
 func TestDividendGrossHasToBeGreaterThanZero(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	d.Apply(&sharesAddedEvent)
 
-	err := d.RecordDividend("MO", 20.00, 0, "2000-01-02")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02")
 
 	_, ok := err.(*dividend.DividendGrossZeroOrNegativeError)
 	if !ok {
@@ -80,14 +80,27 @@ func TestDividendGrossHasToBeGreaterThanZero(t *testing.T) {
 	}
 }
 
+func TestDividendNetAndGrossHaveToBeInTheSameCurrency(t *testing.T) {
+	d := dividend.NewDividend()
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	d.Apply(&sharesAddedEvent)
+
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "USD"), "2000-01-02")
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
 func TestDateOfLaterAddedSharesIsIgnoredForDividendDateValidation(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
-	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2001-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2001-01-01")
 	d.Apply(&sharesAddedEvent)
 	d.Apply(&sharesAddedEvent2)
 
-	err := d.RecordDividend("MO", 20.00, 30.00, "2000-01-02")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
 
 	if err != nil {
 		t.Errorf("Unexpected error. Got %#v", err.Error())
@@ -96,12 +109,12 @@ func TestDateOfLaterAddedSharesIsIgnoredForDividendDateValidation(t *testing.T)
 
 func TestTickerRenamesAreHandledWhenCheckingDividendDate(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	sharesAddedEvent2 := portfolio.NewTickerRenamedEvent("MO", "FOO")
 	d.Apply(&sharesAddedEvent)
 	d.Apply(&sharesAddedEvent2)
 
-	err := d.RecordDividend("FOO", 20.00, 30.00, "2000-01-02")
+	err := d.RecordDividend("FOO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
 
 	if err != nil {
 		t.Errorf("Unexpected error. Got %#v", err.Error())
@@ -110,13 +123,50 @@ func TestTickerRenamesAreHandledWhenCheckingDividendDate(t *testing.T) {
 
 func TestRenamedTickersCanBeUsed(t *testing.T) {
 	d := dividend.NewDividend()
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	sharesAddedEvent2 := portfolio.NewTickerRenamedEvent("MO", "FOO")
 	d.Apply(&sharesAddedEvent)
 	d.Apply(&sharesAddedEvent2)
 
-	err := d.RecordDividend("MO", 20.00, 30.00, "2000-01-02")
+	err := d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
+
+	if err != nil {
+		t.Errorf("Unexpected error. Got %#v", err.Error())
+	}
+}
+
+func TestConvertedSharesKeepTheirDividendEligibility(t *testing.T) {
+	d := dividend.NewDividend()
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("PM", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05")
+	sharesConvertedEvent := portfolio.NewSharesConvertedEvent("MO", "PM", 1, 2, domain.NewQuantityFromInt(0), domain.Money{}, "2000-01-10")
+	d.Apply(&sharesAddedEvent)
+	d.Apply(&sharesAddedEvent2)
+	d.Apply(&sharesConvertedEvent)
+
+	err := d.RecordDividend("PM", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
+	if err != nil {
+		t.Errorf("Unexpected error. Got %#v", err.Error())
+	}
+	err = d.RecordDividend("MO", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-02")
+	if err != nil {
+		t.Errorf("Unexpected error. Got %#v", err.Error())
+	}
+}
+
+func TestSpunOffSharesAreEligibleForDividendsFromTheSpinOff(t *testing.T) {
+	d := dividend.NewDividend()
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	sharesSpunOffEvent := portfolio.NewSharesSpunOffEvent("MO", "PM", 2, 1, domain.Percentage{}, "2000-01-10")
+	d.Apply(&sharesAddedEvent)
+	d.Apply(&sharesSpunOffEvent)
 
+	err := d.RecordDividend("PM", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-05")
+	_, ok := err.(*dividend.DividendDateBeforeSharesWereAddedToPortfolioError)
+	if !ok {
+		t.Errorf("Expected DividendDateBeforeSharesWereAddedToPortfolioError but got %#v", err)
+	}
+	err = d.RecordDividend("PM", domain.NewMoneyFromFloat(20.00, "EUR"), domain.NewMoneyFromFloat(30.00, "EUR"), "2000-01-11")
 	if err != nil {
 		t.Errorf("Unexpected error. Got %#v", err.Error())
 	}
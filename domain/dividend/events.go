@@ -1,15 +1,19 @@
 package dividend
 
+import "stock-monitor/domain"
+
 const DividendRecordedEventName = "Dividend.DividendRecorded"
 
+const DividendRecordedEventVersion = 2
+
 type DividendRecordedEvent struct {
 	ticker string
-	net    float32
-	gross  float32
+	net    domain.Money
+	gross  domain.Money
 	date   string
 }
 
-func NewDividendRecordedEvent(ticker string, net float32, gross float32, date string) DividendRecordedEvent {
+func NewDividendRecordedEvent(ticker string, net domain.Money, gross domain.Money, date string) DividendRecordedEvent {
 	return DividendRecordedEvent{ticker: ticker, net: net, gross: gross, date: date}
 }
 
@@ -17,11 +21,32 @@ func (event *DividendRecordedEvent) Name() string {
 	return DividendRecordedEventName
 }
 
+func (event *DividendRecordedEvent) Version() int {
+	return DividendRecordedEventVersion
+}
+
 func (event *DividendRecordedEvent) Payload() map[string]interface{} {
 	return map[string]interface{}{
-		"ticker": event.ticker,
-		"net":    event.net,
-		"gross":  event.gross,
-		"date":   event.date,
+		"ticker":   event.ticker,
+		"net":      event.net.Amount(),
+		"gross":    event.gross.Amount(),
+		"currency": event.net.Currency(),
+		"date":     event.date,
 	}
 }
+
+func (event *DividendRecordedEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *DividendRecordedEvent) Net() domain.Money {
+	return event.net
+}
+
+func (event *DividendRecordedEvent) Gross() domain.Money {
+	return event.gross
+}
+
+func (event *DividendRecordedEvent) Date() string {
+	return event.date
+}
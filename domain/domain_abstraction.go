@@ -7,5 +7,6 @@ type Aggregate interface {
 
 type DomainEvent interface {
 	Name() string
+	Version() int
 	Payload() map[string]interface{}
 }
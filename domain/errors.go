@@ -0,0 +1,62 @@
+package domain
+
+type InvalidQuantityError struct {
+	value string
+}
+
+type InvalidMoneyError struct {
+	value string
+}
+
+type CurrencyMismatchError struct {
+	expected string
+	actual   string
+}
+
+type InvalidExchangeRateError struct {
+	value string
+}
+
+type InvalidPercentageError struct {
+	value string
+}
+
+func NewInvalidQuantityError(value string) *InvalidQuantityError {
+	return &InvalidQuantityError{value: value}
+}
+
+func NewInvalidMoneyError(value string) *InvalidMoneyError {
+	return &InvalidMoneyError{value: value}
+}
+
+func NewCurrencyMismatchError(expected string, actual string) *CurrencyMismatchError {
+	return &CurrencyMismatchError{expected: expected, actual: actual}
+}
+
+func NewInvalidExchangeRateError(value string) *InvalidExchangeRateError {
+	return &InvalidExchangeRateError{value: value}
+}
+
+func NewInvalidPercentageError(value string) *InvalidPercentageError {
+	return &InvalidPercentageError{value: value}
+}
+
+func (e *InvalidQuantityError) Error() string {
+	return "invalid quantity. value: " + e.value
+}
+
+func (e *InvalidMoneyError) Error() string {
+	return "invalid amount of money. value: " + e.value
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return "currencies do not match. expected: " + e.expected + " actual: " + e.actual
+}
+
+func (e *InvalidExchangeRateError) Error() string {
+	return "invalid exchange rate. value: " + e.value
+}
+
+func (e *InvalidPercentageError) Error() string {
+	return "invalid percentage. value: " + e.value
+}
@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestInvalidQuantityError(t *testing.T) {
+	err := domain.NewInvalidQuantityError("many")
+
+	expected := "invalid quantity. value: many"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidMoneyError(t *testing.T) {
+	err := domain.NewInvalidMoneyError("much")
+
+	expected := "invalid amount of money. value: much"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestCurrencyMismatchError(t *testing.T) {
+	err := domain.NewCurrencyMismatchError("EUR", "USD")
+
+	expected := "currencies do not match. expected: EUR actual: USD"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidExchangeRateError(t *testing.T) {
+	err := domain.NewInvalidExchangeRateError("-1")
+
+	expected := "invalid exchange rate. value: -1"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidPercentageError(t *testing.T) {
+	err := domain.NewInvalidPercentageError("foo")
+
+	expected := "invalid percentage. value: foo"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+type ExchangeRate struct {
+	from string
+	to   string
+	rate decimal.Decimal
+}
+
+func NewExchangeRate(from string, to string, rate string) (ExchangeRate, error) {
+	parsed, err := decimal.NewFromString(rate)
+	if err != nil || !parsed.IsPositive() {
+		return ExchangeRate{}, NewInvalidExchangeRateError(rate)
+	}
+
+	return ExchangeRate{from, to, canonicalDecimal(parsed)}, nil
+}
+
+// fromRate and toRate are both quoted against the same reference currency, e.g. USD 1.08 and GBP 0.86 per EUR
+func NewCrossExchangeRate(from string, to string, fromRate string, toRate string) (ExchangeRate, error) {
+	parsedFrom, err := decimal.NewFromString(fromRate)
+	if err != nil || !parsedFrom.IsPositive() {
+		return ExchangeRate{}, NewInvalidExchangeRateError(fromRate)
+	}
+	parsedTo, err := decimal.NewFromString(toRate)
+	if err != nil || !parsedTo.IsPositive() {
+		return ExchangeRate{}, NewInvalidExchangeRateError(toRate)
+	}
+
+	return ExchangeRate{from, to, canonicalDecimal(parsedTo.DivRound(parsedFrom, decimalPrecision))}, nil
+}
+
+func (rate ExchangeRate) From() string {
+	return rate.from
+}
+
+func (rate ExchangeRate) To() string {
+	return rate.to
+}
+
+func (rate ExchangeRate) Rate() string {
+	return rate.rate.String()
+}
+
+func (rate ExchangeRate) Convert(money Money) (Money, error) {
+	if money.currency != rate.from {
+		return Money{}, NewCurrencyMismatchError(rate.from, money.currency)
+	}
+
+	return Money{canonicalDecimal(money.amount.Mul(rate.rate).Round(decimalPrecision)), rate.to}, nil
+}
@@ -0,0 +1,52 @@
+package domain_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestExchangeRateConvertsMoney(t *testing.T) {
+	rate, _ := domain.NewExchangeRate("USD", "EUR", "0.9")
+
+	got, err := rate.Convert(domain.NewMoneyFromFloat(19.99, "USD"))
+
+	want := domain.NewMoneyFromFloat(17.991, "EUR")
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected money. Expected:%#v Got:%#v", want.String(), got.String())
+	}
+}
+
+func TestExchangeRateCanNotConvertOtherCurrencies(t *testing.T) {
+	rate, _ := domain.NewExchangeRate("USD", "EUR", "0.9")
+
+	_, err := rate.Convert(domain.NewMoneyFromFloat(19.99, "GBP"))
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
+func TestExchangeRateHasToBePositive(t *testing.T) {
+	_, err := domain.NewExchangeRate("USD", "EUR", "0")
+
+	_, ok := err.(*domain.InvalidExchangeRateError)
+	if !ok {
+		t.Errorf("Expected InvalidExchangeRateError but got %#v", err)
+	}
+}
+
+func TestCrossExchangeRateIsCalculatedFromReferenceRates(t *testing.T) {
+	rate, err := domain.NewCrossExchangeRate("USD", "GBP", "1.25", "0.85")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if rate.Rate() != "0.68" {
+		t.Errorf("Unexpected rate. Expected:%#v Got:%#v", "0.68", rate.Rate())
+	}
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+const DefaultCurrency = "EUR"
+
+type Money struct {
+	amount   decimal.Decimal
+	currency string
+}
+
+type moneyJson struct {
+	Amount   json.Number
+	Currency string
+}
+
+func NewMoney(amount string, currency string) (Money, error) {
+	parsed, err := decimal.NewFromString(amount)
+	if err != nil {
+		return Money{}, NewInvalidMoneyError(amount)
+	}
+
+	return Money{canonicalDecimal(parsed), currency}, nil
+}
+
+func NewMoneyFromFloat(amount float64, currency string) Money {
+	return Money{canonicalDecimal(decimal.NewFromFloat(amount)), currency}
+}
+
+func NewMoneyFromFloat32(amount float32, currency string) Money {
+	return Money{canonicalDecimal(decimal.NewFromFloat32(amount)), currency}
+}
+
+func (money Money) Add(other Money) (Money, error) {
+	if money.currency != other.currency {
+		return Money{}, NewCurrencyMismatchError(money.currency, other.currency)
+	}
+
+	return Money{canonicalDecimal(money.amount.Add(other.amount)), money.currency}, nil
+}
+
+func (money Money) Sub(other Money) (Money, error) {
+	if money.currency != other.currency {
+		return Money{}, NewCurrencyMismatchError(money.currency, other.currency)
+	}
+
+	return Money{canonicalDecimal(money.amount.Sub(other.amount)), money.currency}, nil
+}
+
+func (money Money) MulQuantity(quantity Quantity) Money {
+	return Money{canonicalDecimal(money.amount.Mul(quantity.value)), money.currency}
+}
+
+func (money Money) DivQuantity(quantity Quantity) Money {
+	return Money{canonicalDecimal(money.amount.DivRound(quantity.value, decimalPrecision)), money.currency}
+}
+
+// the quantity of other that fits into money, rounded to 8 decimal places
+func (money Money) DivMoney(other Money) (Quantity, error) {
+	if money.currency != other.currency {
+		return Quantity{}, NewCurrencyMismatchError(money.currency, other.currency)
+	}
+
+	return newQuantity(money.amount.DivRound(other.amount, decimalPrecision)), nil
+}
+
+func (money Money) MulRatio(numerator int, denominator int) Money {
+	multiplied := money.amount.Mul(decimal.NewFromInt(int64(numerator)))
+
+	return Money{canonicalDecimal(multiplied.DivRound(decimal.NewFromInt(int64(denominator)), decimalPrecision)), money.currency}
+}
+
+func (money Money) IsZero() bool {
+	return money.amount.IsZero()
+}
+
+func (money Money) IsPositive() bool {
+	return money.amount.IsPositive()
+}
+
+func (money Money) IsNegative() bool {
+	return money.amount.IsNegative()
+}
+
+// Float64 is only meant for calculations that can not be exact anyway, like rates of return
+func (money Money) Float64() float64 {
+	amount, _ := money.amount.Float64()
+
+	return amount
+}
+
+func (money Money) Amount() string {
+	return money.amount.String()
+}
+
+func (money Money) Currency() string {
+	return money.currency
+}
+
+func (money Money) String() string {
+	return money.amount.String() + " " + money.currency
+}
+
+func (money Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJson{json.Number(money.amount.String()), money.currency})
+}
+
+// a plain number is read as an amount in the default currency
+func (money *Money) UnmarshalJSON(data []byte) error {
+	var amount json.Number
+	if json.Unmarshal(data, &amount) == nil {
+		parsed, err := NewMoney(amount.String(), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*money = parsed
+
+		return nil
+	}
+
+	decoded := moneyJson{}
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return NewInvalidMoneyError(string(data))
+	}
+
+	parsed, err := NewMoney(decoded.Amount.String(), decoded.Currency)
+	if err != nil {
+		return err
+	}
+	*money = parsed
+
+	return nil
+}
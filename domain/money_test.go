@@ -0,0 +1,112 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestMoneyCanBeParsed(t *testing.T) {
+	got, err := domain.NewMoney("19.99", "USD")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if got.String() != "19.99 USD" {
+		t.Errorf("Unexpected money. Expected:%#v Got:%#v", "19.99 USD", got.String())
+	}
+}
+
+func TestInvalidMoneyCanNotBeParsed(t *testing.T) {
+	_, err := domain.NewMoney("much", "EUR")
+
+	_, ok := err.(*domain.InvalidMoneyError)
+	if !ok {
+		t.Errorf("Expected InvalidMoneyError but got %#v", err)
+	}
+}
+
+func TestMoneyCalculationsAreExact(t *testing.T) {
+	sum := domain.NewMoneyFromFloat(0, "EUR")
+	for i := 0; i < 10; i++ {
+		sum, _ = sum.Add(domain.NewMoneyFromFloat(0.1, "EUR"))
+	}
+
+	if reflect.DeepEqual(sum, domain.NewMoneyFromFloat(1, "EUR")) == false {
+		t.Errorf("Unexpected money. Expected:%#v Got:%#v", "1 EUR", sum.String())
+	}
+
+	total := domain.NewMoneyFromFloat(9.99, "EUR").MulQuantity(domain.NewQuantityFromFloat(0.3))
+	if total.Amount() != "2.997" {
+		t.Errorf("Unexpected amount. Expected:%#v Got:%#v", "2.997", total.Amount())
+	}
+}
+
+func TestMoneyCanBeDividedByQuantity(t *testing.T) {
+	got := domain.NewMoneyFromFloat(100, "EUR").DivQuantity(domain.NewQuantityFromInt(8))
+
+	if got.Amount() != "12.5" {
+		t.Errorf("Unexpected amount. Expected:%#v Got:%#v", "12.5", got.Amount())
+	}
+}
+
+func TestMoneyCanBeDividedByMoney(t *testing.T) {
+	got, err := domain.NewMoneyFromFloat(20, "EUR").DivMoney(domain.NewMoneyFromFloat(3, "EUR"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if got.String() != "6.66666667" {
+		t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", "6.66666667", got.String())
+	}
+}
+
+func TestMoneyInDifferentCurrenciesCanNotBeDivided(t *testing.T) {
+	_, err := domain.NewMoneyFromFloat(20, "EUR").DivMoney(domain.NewMoneyFromFloat(3, "USD"))
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
+func TestMoneyInDifferentCurrenciesCanNotBeAdded(t *testing.T) {
+	_, err := domain.NewMoneyFromFloat(1, "EUR").Add(domain.NewMoneyFromFloat(1, "USD"))
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
+func TestMoneyCanBeMarshalledToJson(t *testing.T) {
+	got, _ := json.Marshal(domain.NewMoneyFromFloat(9.99, "USD"))
+
+	expected := `{"Amount":9.99,"Currency":"USD"}`
+	if string(got) != expected {
+		t.Errorf("Unexpected json. Expected:%#v Got:%#v", expected, string(got))
+	}
+}
+
+func TestMoneyCanBeUnmarshalledFromJson(t *testing.T) {
+	fromNumber := domain.Money{}
+	fromObject := domain.Money{}
+	json.Unmarshal([]byte(`9.99`), &fromNumber)
+	json.Unmarshal([]byte(`{"Amount":9.99,"Currency":"USD"}`), &fromObject)
+
+	if reflect.DeepEqual(fromNumber, domain.NewMoneyFromFloat(9.99, domain.DefaultCurrency)) == false {
+		t.Errorf("Unexpected money. Expected:%#v Got:%#v", "9.99 EUR", fromNumber.String())
+	}
+	if reflect.DeepEqual(fromObject, domain.NewMoneyFromFloat(9.99, "USD")) == false {
+		t.Errorf("Unexpected money. Expected:%#v Got:%#v", "9.99 USD", fromObject.String())
+	}
+}
+
+func TestMoneyCanBeConvertedToFloat(t *testing.T) {
+	got := domain.NewMoneyFromFloat(9.99, "EUR").Float64()
+
+	if got != 9.99 {
+		t.Errorf("Unexpected amount. Expected:%#v Got:%#v", 9.99, got)
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+const percentagePrecision = 2
+
+type Percentage struct {
+	value decimal.Decimal
+}
+
+func NewPercentage(value string) (Percentage, error) {
+	parsed, err := decimal.NewFromString(value)
+	if err != nil {
+		return Percentage{}, NewInvalidPercentageError(value)
+	}
+
+	return Percentage{canonicalDecimal(parsed)}, nil
+}
+
+func (percentage Percentage) IsBetweenZeroAndHundred() bool {
+	return !percentage.value.IsNegative() && percentage.value.LessThanOrEqual(decimal.NewFromInt(100))
+}
+
+func (percentage Percentage) String() string {
+	return percentage.value.String()
+}
+
+func (percentage Percentage) MarshalJSON() ([]byte, error) {
+	return []byte(percentage.value.String()), nil
+}
+
+func (percentage *Percentage) UnmarshalJSON(data []byte) error {
+	var value json.Number
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return NewInvalidPercentageError(string(data))
+	}
+
+	parsed, err := NewPercentage(value.String())
+	if err != nil {
+		return err
+	}
+	*percentage = parsed
+
+	return nil
+}
+
+// a ratio of 0.0525 is 5.25 percent
+func NewPercentageFromRatio(ratio float64) Percentage {
+	return Percentage{canonicalDecimal(decimal.NewFromFloat(ratio).Mul(decimal.NewFromInt(100)).Round(percentagePrecision))}
+}
+
+// a zero base results in zero percent
+func (money Money) PercentageOf(base Money) (Percentage, error) {
+	if money.currency != base.currency {
+		return Percentage{}, NewCurrencyMismatchError(base.currency, money.currency)
+	}
+	if base.IsZero() {
+		return Percentage{canonicalDecimal(decimal.Zero)}, nil
+	}
+
+	ratio := money.amount.DivRound(base.amount, decimalPrecision)
+
+	return Percentage{canonicalDecimal(ratio.Mul(decimal.NewFromInt(100)).Round(percentagePrecision))}, nil
+}
+
+func (money Money) MulPercentage(percentage Percentage) Money {
+	multiplied := money.amount.Mul(percentage.value)
+
+	return Money{canonicalDecimal(multiplied.DivRound(decimal.NewFromInt(100), decimalPrecision)), money.currency}
+}
@@ -0,0 +1,83 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestPercentageOfMoneyIsRounded(t *testing.T) {
+	got, err := domain.NewMoneyFromFloat(1, "EUR").PercentageOf(domain.NewMoneyFromFloat(3, "EUR"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if got.String() != "33.33" {
+		t.Errorf("Unexpected percentage. Expected:%#v Got:%#v", "33.33", got.String())
+	}
+}
+
+func TestPercentageOfZeroIsZero(t *testing.T) {
+	got, _ := domain.NewMoneyFromFloat(10, "EUR").PercentageOf(domain.NewMoneyFromFloat(0, "EUR"))
+
+	encoded, _ := json.Marshal(got)
+	if string(encoded) != "0" {
+		t.Errorf("Unexpected percentage. Expected:%#v Got:%#v", "0", string(encoded))
+	}
+}
+
+func TestPercentageNeedsSameCurrency(t *testing.T) {
+	_, err := domain.NewMoneyFromFloat(10, "EUR").PercentageOf(domain.NewMoneyFromFloat(10, "USD"))
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
+func TestPercentageCanBeCreatedFromRatio(t *testing.T) {
+	got := domain.NewPercentageFromRatio(0.052549)
+
+	if got.String() != "5.25" {
+		t.Errorf("Unexpected percentage. Expected:%#v Got:%#v", "5.25", got.String())
+	}
+}
+
+func TestPercentageCanBeUnmarshalledFromJsonNumbersAndStrings(t *testing.T) {
+	for _, data := range []string{`8.5`, `"8.5"`} {
+		var got domain.Percentage
+		err := json.Unmarshal([]byte(data), &got)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+		if got.String() != "8.5" {
+			t.Errorf("Unexpected percentage. Expected:%#v Got:%#v", "8.5", got.String())
+		}
+	}
+
+	var invalid domain.Percentage
+	if err := json.Unmarshal([]byte(`"foo"`), &invalid); err == nil {
+		t.Errorf("Expected error for invalid percentage")
+	}
+}
+
+func TestPercentageCanBeCheckedToBeBetweenZeroAndHundred(t *testing.T) {
+	for value, want := range map[string]bool{"-0.01": false, "0": true, "100": true, "100.01": false} {
+		percentage, _ := domain.NewPercentage(value)
+
+		if got := percentage.IsBetweenZeroAndHundred(); got != want {
+			t.Errorf("Unexpected range check of %s. Expected:%#v Got:%#v", value, want, got)
+		}
+	}
+}
+
+func TestMoneyCanBeMultipliedByPercentage(t *testing.T) {
+	percentage, _ := domain.NewPercentage("8.5")
+
+	got := domain.NewMoneyFromFloat(200, "EUR").MulPercentage(percentage)
+
+	if got.String() != "17 EUR" {
+		t.Errorf("Unexpected amount. Expected:%#v Got:%#v", "17 EUR", got.String())
+	}
+}
@@ -12,6 +12,45 @@ type TickerAlreadyUsedError struct {
 	ticker string
 }
 
+type InvalidSplitRatioError struct{}
+
+type SplitTickerNotInPortfolioError struct {
+	ticker string
+}
+
+type LotNotFoundError struct {
+	ticker string
+	lot    string
+}
+
+type NotEnoughSharesInLotError struct {
+	lot string
+}
+
+type SelectedLotsDoNotMatchSharesError struct{}
+
+type InvalidChargeError struct{}
+
+type InvalidConversionRatioError struct{}
+
+type ConversionTickerNotInPortfolioError struct {
+	ticker string
+}
+
+type InvalidCashInLieuError struct{}
+
+type CashInLieuWithoutFractionalSharesError struct{}
+
+type InvalidSpinOffRatioError struct{}
+
+type SpinOffTickerNotInPortfolioError struct {
+	ticker string
+}
+
+type InvalidCostBasisPercentageError struct{}
+
+type InvalidReinvestmentPriceError struct{}
+
 func NewTickerNotInPortfolioError(ticker string) *TickerNotInPortfolioError {
 	return &TickerNotInPortfolioError{ticker: ticker}
 }
@@ -20,6 +59,26 @@ func NewTickerAlreadyUsedError(ticker string) *TickerAlreadyUsedError {
 	return &TickerAlreadyUsedError{ticker: ticker}
 }
 
+func NewSplitTickerNotInPortfolioError(ticker string) *SplitTickerNotInPortfolioError {
+	return &SplitTickerNotInPortfolioError{ticker: ticker}
+}
+
+func NewConversionTickerNotInPortfolioError(ticker string) *ConversionTickerNotInPortfolioError {
+	return &ConversionTickerNotInPortfolioError{ticker: ticker}
+}
+
+func NewSpinOffTickerNotInPortfolioError(ticker string) *SpinOffTickerNotInPortfolioError {
+	return &SpinOffTickerNotInPortfolioError{ticker: ticker}
+}
+
+func NewLotNotFoundError(ticker string, lot string) *LotNotFoundError {
+	return &LotNotFoundError{ticker: ticker, lot: lot}
+}
+
+func NewNotEnoughSharesInLotError(lot string) *NotEnoughSharesInLotError {
+	return &NotEnoughSharesInLotError{lot: lot}
+}
+
 func (e *InvalidNumbersOfSharesError) Error() string {
 	return "number of shares must be greater than 0"
 }
@@ -35,3 +94,59 @@ func (e *TickerNotInPortfolioError) Error() string {
 func (e *TickerAlreadyUsedError) Error() string {
 	return "New ticker symbol already in use. Ticker: " + e.ticker
 }
+
+func (e *InvalidSplitRatioError) Error() string {
+	return "split ratio must be greater than 0"
+}
+
+func (e *SplitTickerNotInPortfolioError) Error() string {
+	return "Ticker to be split not found. Ticker: " + e.ticker
+}
+
+func (e *LotNotFoundError) Error() string {
+	return "Lot to be sold not found. Ticker: " + e.ticker + " Lot: " + e.lot
+}
+
+func (e *NotEnoughSharesInLotError) Error() string {
+	return "not enough shares left in lot. Lot: " + e.lot
+}
+
+func (e *SelectedLotsDoNotMatchSharesError) Error() string {
+	return "shares of the selected lots must add up to the number of shares sold"
+}
+
+func (e *InvalidChargeError) Error() string {
+	return "fees and taxes must not be negative"
+}
+
+func (e *InvalidConversionRatioError) Error() string {
+	return "conversion ratio must be greater than 0"
+}
+
+func (e *ConversionTickerNotInPortfolioError) Error() string {
+	return "Ticker to be converted not found. Ticker: " + e.ticker
+}
+
+func (e *InvalidCashInLieuError) Error() string {
+	return "cash in lieu must not be negative"
+}
+
+func (e *CashInLieuWithoutFractionalSharesError) Error() string {
+	return "cash in lieu is only paid for fractional shares"
+}
+
+func (e *InvalidSpinOffRatioError) Error() string {
+	return "spin-off ratio must be greater than 0"
+}
+
+func (e *SpinOffTickerNotInPortfolioError) Error() string {
+	return "Ticker to be spun off from not found. Ticker: " + e.ticker
+}
+
+func (e *InvalidCostBasisPercentageError) Error() string {
+	return "cost basis percentage must be between 0 and 100"
+}
+
+func (e *InvalidReinvestmentPriceError) Error() string {
+	return "reinvestment price must be greater than 0"
+}
@@ -48,3 +48,157 @@ func TestTickerAlreadyUsedError(t *testing.T) {
 		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
 	}
 }
+
+func TestInvalidSplitRatioError(t *testing.T) {
+	err := portfolio.InvalidSplitRatioError{}
+
+	expected := "split ratio must be greater than 0"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestSplitTickerNotInPortfolioError(t *testing.T) {
+	err := portfolio.NewSplitTickerNotInPortfolioError("FOO")
+
+	expected := "Ticker to be split not found. Ticker: FOO"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestLotNotFoundError(t *testing.T) {
+	err := portfolio.NewLotNotFoundError("FOO", "3")
+
+	expected := "Lot to be sold not found. Ticker: FOO Lot: 3"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestNotEnoughSharesInLotError(t *testing.T) {
+	err := portfolio.NewNotEnoughSharesInLotError("2000-01-01")
+
+	expected := "not enough shares left in lot. Lot: 2000-01-01"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestSelectedLotsDoNotMatchSharesError(t *testing.T) {
+	err := portfolio.SelectedLotsDoNotMatchSharesError{}
+
+	expected := "shares of the selected lots must add up to the number of shares sold"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidChargeError(t *testing.T) {
+	err := portfolio.InvalidChargeError{}
+
+	expected := "fees and taxes must not be negative"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidConversionRatioError(t *testing.T) {
+	err := portfolio.InvalidConversionRatioError{}
+
+	expected := "conversion ratio must be greater than 0"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestConversionTickerNotInPortfolioError(t *testing.T) {
+	err := portfolio.NewConversionTickerNotInPortfolioError("MO")
+
+	expected := "Ticker to be converted not found. Ticker: MO"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidCashInLieuError(t *testing.T) {
+	err := portfolio.InvalidCashInLieuError{}
+
+	expected := "cash in lieu must not be negative"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestCashInLieuWithoutFractionalSharesError(t *testing.T) {
+	err := portfolio.CashInLieuWithoutFractionalSharesError{}
+
+	expected := "cash in lieu is only paid for fractional shares"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidSpinOffRatioError(t *testing.T) {
+	err := portfolio.InvalidSpinOffRatioError{}
+
+	expected := "spin-off ratio must be greater than 0"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestSpinOffTickerNotInPortfolioError(t *testing.T) {
+	err := portfolio.NewSpinOffTickerNotInPortfolioError("MO")
+
+	expected := "Ticker to be spun off from not found. Ticker: MO"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidCostBasisPercentageError(t *testing.T) {
+	err := portfolio.InvalidCostBasisPercentageError{}
+
+	expected := "cost basis percentage must be between 0 and 100"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidReinvestmentPriceError(t *testing.T) {
+	err := portfolio.InvalidReinvestmentPriceError{}
+
+	expected := "reinvestment price must be greater than 0"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
@@ -1,55 +1,141 @@
 package portfolio
 
+import "stock-monitor/domain"
+
 const SharesAddedToPortfolioEventName = "Portfolio.SharesAddedToPortfolio"
 const SharesRemovedFromPortfolioEventName = "Portfolio.SharesRemovedFromPortfolio"
 const TickerRenamedEventName = "Portfolio.TickerRenamed"
+const StockSplitEventName = "Portfolio.StockSplit"
+const SharesConvertedEventName = "Portfolio.SharesConverted"
+const SharesSpunOffEventName = "Portfolio.SharesSpunOff"
+
+const SharesAddedToPortfolioEventVersion = 4
+const SharesRemovedFromPortfolioEventVersion = 6
+const TickerRenamedEventVersion = 1
+const StockSplitEventVersion = 1
+const SharesConvertedEventVersion = 1
+const SharesSpunOffEventVersion = 1
 
 type SharesAddedToPortfolioEvent struct {
 	ticker string
-	shares int
-	price  float32
+	shares domain.Quantity
+	price  domain.Money
+	fee    domain.Money
+	taxes  domain.Money
 	date   string
 }
 
-func NewSharesAddedToPortfolioEvent(ticker string, shares int, price float32, date string) SharesAddedToPortfolioEvent {
-	return SharesAddedToPortfolioEvent{ticker: ticker, shares: shares, price: price, date: date}
+func NewSharesAddedToPortfolioEvent(ticker string, shares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date string) SharesAddedToPortfolioEvent {
+	return SharesAddedToPortfolioEvent{ticker: ticker, shares: shares, price: price, fee: fee, taxes: taxes, date: date}
 }
 
 func (event *SharesAddedToPortfolioEvent) Name() string {
 	return SharesAddedToPortfolioEventName
 }
 
+func (event *SharesAddedToPortfolioEvent) Version() int {
+	return SharesAddedToPortfolioEventVersion
+}
+
 func (event *SharesAddedToPortfolioEvent) Payload() map[string]interface{} {
 	return map[string]interface{}{
-		"ticker": event.ticker,
-		"shares": event.shares,
-		"price":  event.price,
-		"date":   event.date,
+		"ticker":   event.ticker,
+		"shares":   event.shares.String(),
+		"price":    event.price.Amount(),
+		"fee":      event.fee.Amount(),
+		"taxes":    event.taxes.Amount(),
+		"currency": event.price.Currency(),
+		"date":     event.date,
 	}
 }
 
+func (event *SharesAddedToPortfolioEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *SharesAddedToPortfolioEvent) Shares() domain.Quantity {
+	return event.shares
+}
+
+func (event *SharesAddedToPortfolioEvent) Price() domain.Money {
+	return event.price
+}
+
+func (event *SharesAddedToPortfolioEvent) Fee() domain.Money {
+	return event.fee
+}
+
+func (event *SharesAddedToPortfolioEvent) Taxes() domain.Money {
+	return event.taxes
+}
+
+func (event *SharesAddedToPortfolioEvent) Date() string {
+	return event.date
+}
+
 type SharesRemovedFromPortfolioEvent struct {
 	ticker string
-	shares int
-	price  float32
+	shares domain.Quantity
+	price  domain.Money
+	fee    domain.Money
+	taxes  domain.Money
+	date   string
+	lots   []SelectedLot
 }
 
-func NewSharesRemovedFromPortfolioEvent(ticker string, shares int, price float32) SharesRemovedFromPortfolioEvent {
-	return SharesRemovedFromPortfolioEvent{ticker: ticker, shares: shares, price: price}
+func NewSharesRemovedFromPortfolioEvent(ticker string, shares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date string, lots []SelectedLot) SharesRemovedFromPortfolioEvent {
+	return SharesRemovedFromPortfolioEvent{ticker: ticker, shares: shares, price: price, fee: fee, taxes: taxes, date: date, lots: lots}
 }
 
 func (event *SharesRemovedFromPortfolioEvent) Name() string {
 	return SharesRemovedFromPortfolioEventName
 }
 
+func (event *SharesRemovedFromPortfolioEvent) Version() int {
+	return SharesRemovedFromPortfolioEventVersion
+}
+
 func (event *SharesRemovedFromPortfolioEvent) Payload() map[string]interface{} {
 	return map[string]interface{}{
-		"ticker": event.ticker,
-		"shares": event.shares,
-		"price":  event.price,
+		"ticker":   event.ticker,
+		"shares":   event.shares.String(),
+		"price":    event.price.Amount(),
+		"fee":      event.fee.Amount(),
+		"taxes":    event.taxes.Amount(),
+		"currency": event.price.Currency(),
+		"date":     event.date,
+		"lots":     EncodeSelectedLots(event.lots),
 	}
 }
 
+func (event *SharesRemovedFromPortfolioEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *SharesRemovedFromPortfolioEvent) Shares() domain.Quantity {
+	return event.shares
+}
+
+func (event *SharesRemovedFromPortfolioEvent) Price() domain.Money {
+	return event.price
+}
+
+func (event *SharesRemovedFromPortfolioEvent) Fee() domain.Money {
+	return event.fee
+}
+
+func (event *SharesRemovedFromPortfolioEvent) Taxes() domain.Money {
+	return event.taxes
+}
+
+func (event *SharesRemovedFromPortfolioEvent) Date() string {
+	return event.date
+}
+
+func (event *SharesRemovedFromPortfolioEvent) Lots() []SelectedLot {
+	return event.lots
+}
+
 type TickerRenamedEvent struct {
 	old string
 	new string
@@ -63,9 +149,187 @@ func (event *TickerRenamedEvent) Name() string {
 	return TickerRenamedEventName
 }
 
+func (event *TickerRenamedEvent) Version() int {
+	return TickerRenamedEventVersion
+}
+
 func (event *TickerRenamedEvent) Payload() map[string]interface{} {
 	return map[string]interface{}{
 		"old": event.old,
 		"new": event.new,
 	}
 }
+
+func (event *TickerRenamedEvent) Old() string {
+	return event.old
+}
+
+func (event *TickerRenamedEvent) New() string {
+	return event.new
+}
+
+type StockSplitEvent struct {
+	ticker    string
+	ratioFrom int
+	ratioTo   int
+	date      string
+}
+
+func NewStockSplitEvent(ticker string, ratioFrom int, ratioTo int, date string) StockSplitEvent {
+	return StockSplitEvent{ticker: ticker, ratioFrom: ratioFrom, ratioTo: ratioTo, date: date}
+}
+
+func (event *StockSplitEvent) Name() string {
+	return StockSplitEventName
+}
+
+func (event *StockSplitEvent) Version() int {
+	return StockSplitEventVersion
+}
+
+func (event *StockSplitEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"ticker":     event.ticker,
+		"ratio_from": event.ratioFrom,
+		"ratio_to":   event.ratioTo,
+		"date":       event.date,
+	}
+}
+
+func (event *StockSplitEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *StockSplitEvent) RatioFrom() int {
+	return event.ratioFrom
+}
+
+func (event *StockSplitEvent) RatioTo() int {
+	return event.ratioTo
+}
+
+func (event *StockSplitEvent) Date() string {
+	return event.date
+}
+
+// SharesConvertedEvent exchanges all shares of ticker for shares of newTicker, fractionalShares of newTicker are paid out as cashInLieu
+type SharesConvertedEvent struct {
+	ticker           string
+	newTicker        string
+	ratioFrom        int
+	ratioTo          int
+	fractionalShares domain.Quantity
+	cashInLieu       domain.Money
+	date             string
+}
+
+func NewSharesConvertedEvent(ticker string, newTicker string, ratioFrom int, ratioTo int, fractionalShares domain.Quantity, cashInLieu domain.Money, date string) SharesConvertedEvent {
+	return SharesConvertedEvent{ticker: ticker, newTicker: newTicker, ratioFrom: ratioFrom, ratioTo: ratioTo, fractionalShares: fractionalShares, cashInLieu: cashInLieu, date: date}
+}
+
+func (event *SharesConvertedEvent) Name() string {
+	return SharesConvertedEventName
+}
+
+func (event *SharesConvertedEvent) Version() int {
+	return SharesConvertedEventVersion
+}
+
+func (event *SharesConvertedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"ticker":            event.ticker,
+		"new_ticker":        event.newTicker,
+		"ratio_from":        event.ratioFrom,
+		"ratio_to":          event.ratioTo,
+		"fractional_shares": event.fractionalShares.String(),
+		"cash_in_lieu":      event.cashInLieu.Amount(),
+		"currency":          event.cashInLieu.Currency(),
+		"date":              event.date,
+	}
+}
+
+func (event *SharesConvertedEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *SharesConvertedEvent) NewTicker() string {
+	return event.newTicker
+}
+
+func (event *SharesConvertedEvent) RatioFrom() int {
+	return event.ratioFrom
+}
+
+func (event *SharesConvertedEvent) RatioTo() int {
+	return event.ratioTo
+}
+
+func (event *SharesConvertedEvent) FractionalShares() domain.Quantity {
+	return event.fractionalShares
+}
+
+func (event *SharesConvertedEvent) CashInLieu() domain.Money {
+	return event.cashInLieu
+}
+
+func (event *SharesConvertedEvent) Date() string {
+	return event.date
+}
+
+// SharesSpunOffEvent adds ratioTo shares of newTicker for every ratioFrom shares of ticker held,
+// costBasisPercentage of the cost basis of ticker is allocated to newTicker
+type SharesSpunOffEvent struct {
+	ticker              string
+	newTicker           string
+	ratioFrom           int
+	ratioTo             int
+	costBasisPercentage domain.Percentage
+	date                string
+}
+
+func NewSharesSpunOffEvent(ticker string, newTicker string, ratioFrom int, ratioTo int, costBasisPercentage domain.Percentage, date string) SharesSpunOffEvent {
+	return SharesSpunOffEvent{ticker: ticker, newTicker: newTicker, ratioFrom: ratioFrom, ratioTo: ratioTo, costBasisPercentage: costBasisPercentage, date: date}
+}
+
+func (event *SharesSpunOffEvent) Name() string {
+	return SharesSpunOffEventName
+}
+
+func (event *SharesSpunOffEvent) Version() int {
+	return SharesSpunOffEventVersion
+}
+
+func (event *SharesSpunOffEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"ticker":                event.ticker,
+		"new_ticker":            event.newTicker,
+		"ratio_from":            event.ratioFrom,
+		"ratio_to":              event.ratioTo,
+		"cost_basis_percentage": event.costBasisPercentage.String(),
+		"date":                  event.date,
+	}
+}
+
+func (event *SharesSpunOffEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *SharesSpunOffEvent) NewTicker() string {
+	return event.newTicker
+}
+
+func (event *SharesSpunOffEvent) RatioFrom() int {
+	return event.ratioFrom
+}
+
+func (event *SharesSpunOffEvent) RatioTo() int {
+	return event.ratioTo
+}
+
+func (event *SharesSpunOffEvent) CostBasisPercentage() domain.Percentage {
+	return event.costBasisPercentage
+}
+
+func (event *SharesSpunOffEvent) Date() string {
+	return event.date
+}
@@ -2,22 +2,26 @@ package portfolio_test
 
 import (
 	"reflect"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"testing"
 )
 
 func TestSharesAddedToPortfolioEventCanBeCreated(t *testing.T) {
-	event := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	event := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(4.95, "EUR"), domain.NewMoneyFromFloat(0.12, "EUR"), "2000-01-01")
 
 	if event.Name() != portfolio.SharesAddedToPortfolioEventName {
 		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", portfolio.SharesAddedToPortfolioEventName, event.Name())
 	}
 
 	expectedPayload := map[string]interface{}{
-		"ticker": "MO",
-		"shares": 10,
-		"price":  float32(9.99),
-		"date":   "2000-01-01",
+		"ticker":   "MO",
+		"shares":   "10",
+		"price":    "9.99",
+		"fee":      "4.95",
+		"taxes":    "0.12",
+		"currency": "EUR",
+		"date":     "2000-01-01",
 	}
 	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
 		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
@@ -25,16 +29,21 @@ func TestSharesAddedToPortfolioEventCanBeCreated(t *testing.T) {
 }
 
 func TestSharesRemovedFromPortfolioEventCanBeCreated(t *testing.T) {
-	event := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 10, 9.99)
+	event := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{portfolio.NewSelectedLot(1, domain.NewQuantityFromInt(4)), portfolio.NewSelectedLot(3, domain.NewQuantityFromFloat(6))})
 
 	if event.Name() != portfolio.SharesRemovedFromPortfolioEventName {
 		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", portfolio.SharesRemovedFromPortfolioEventName, event.Name())
 	}
 
 	expectedPayload := map[string]interface{}{
-		"ticker": "MO",
-		"shares": 10,
-		"price":  float32(9.99),
+		"ticker":   "MO",
+		"shares":   "10",
+		"price":    "9.99",
+		"fee":      "0",
+		"taxes":    "0",
+		"currency": "EUR",
+		"date":     "2000-01-01",
+		"lots":     "1:4,3:6",
 	}
 	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
 		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
@@ -56,3 +65,65 @@ func TestTickerRenamedEventEventCanBeCreated(t *testing.T) {
 		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
 	}
 }
+
+func TestStockSplitEventCanBeCreated(t *testing.T) {
+	event := portfolio.NewStockSplitEvent("MO", 1, 4, "2000-01-01")
+
+	if event.Name() != portfolio.StockSplitEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", portfolio.StockSplitEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"ticker":     "MO",
+		"ratio_from": 1,
+		"ratio_to":   4,
+		"date":       "2000-01-01",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
+
+func TestSharesConvertedEventCanBeCreated(t *testing.T) {
+	fractionalShares, _ := domain.NewQuantity("0.5")
+	event := portfolio.NewSharesConvertedEvent("MO", "PM", 3, 2, fractionalShares, domain.NewMoneyFromFloat(12.5, "USD"), "2000-01-01")
+
+	if event.Name() != portfolio.SharesConvertedEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", portfolio.SharesConvertedEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"ticker":            "MO",
+		"new_ticker":        "PM",
+		"ratio_from":        3,
+		"ratio_to":          2,
+		"fractional_shares": "0.5",
+		"cash_in_lieu":      "12.5",
+		"currency":          "USD",
+		"date":              "2000-01-01",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
+
+func TestSharesSpunOffEventCanBeCreated(t *testing.T) {
+	costBasisPercentage, _ := domain.NewPercentage("12.5")
+	event := portfolio.NewSharesSpunOffEvent("MO", "PM", 2, 1, costBasisPercentage, "2000-01-01")
+
+	if event.Name() != portfolio.SharesSpunOffEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", portfolio.SharesSpunOffEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"ticker":                "MO",
+		"new_ticker":            "PM",
+		"ratio_from":            2,
+		"ratio_to":              1,
+		"cost_basis_percentage": "12.5",
+		"date":                  "2000-01-01",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
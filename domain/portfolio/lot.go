@@ -0,0 +1,135 @@
+package portfolio
+
+import (
+	"stock-monitor/domain"
+	"strconv"
+	"strings"
+)
+
+// lots are numbered in the order shares were added to the portfolio, starting at 1
+type Lot struct {
+	Id      int
+	BuyDate string
+	Shares  domain.Quantity
+}
+
+// LotSelection names a lot either by its id or by its buy date
+type LotSelection struct {
+	LotId   int
+	BuyDate string
+	Shares  domain.Quantity
+}
+
+type SelectedLot struct {
+	lotId  int
+	shares domain.Quantity
+}
+
+func NewSelectedLot(lotId int, shares domain.Quantity) SelectedLot {
+	return SelectedLot{lotId: lotId, shares: shares}
+}
+
+func (selectedLot SelectedLot) LotId() int {
+	return selectedLot.lotId
+}
+
+func (selectedLot SelectedLot) Shares() domain.Quantity {
+	return selectedLot.shares
+}
+
+// selected lots are stored as "id:shares,id:shares" so that every event stream keeps them as a plain string
+func EncodeSelectedLots(selectedLots []SelectedLot) string {
+	encoded := []string{}
+	for _, selectedLot := range selectedLots {
+		encoded = append(encoded, strconv.Itoa(selectedLot.lotId)+":"+selectedLot.shares.String())
+	}
+
+	return strings.Join(encoded, ",")
+}
+
+func DecodeSelectedLots(encoded string) ([]SelectedLot, error) {
+	selectedLots := []SelectedLot{}
+	if encoded == "" {
+		return selectedLots, nil
+	}
+
+	for _, part := range strings.Split(encoded, ",") {
+		values := strings.SplitN(part, ":", 2)
+		if len(values) != 2 {
+			return nil, domain.NewInvalidQuantityError(part)
+		}
+		lotId, err := strconv.Atoi(values[0])
+		if err != nil {
+			return nil, domain.NewInvalidQuantityError(part)
+		}
+		shares, err := domain.NewQuantity(values[1])
+		if err != nil {
+			return nil, err
+		}
+		selectedLots = append(selectedLots, NewSelectedLot(lotId, shares))
+	}
+
+	return selectedLots, nil
+}
+
+// resolves the selections against the open lots of the ticker, a buy date takes the lots of that day in order
+func selectLots(openLots []Lot, ticker string, shares domain.Quantity, selections []LotSelection) ([]SelectedLot, error) {
+	remaining := map[int]domain.Quantity{}
+	for _, lot := range openLots {
+		remaining[lot.Id] = lot.Shares
+	}
+
+	selectedLots := []SelectedLot{}
+	selected := domain.NewQuantityFromInt(0)
+	for _, selection := range selections {
+		if !selection.Shares.IsPositive() {
+			return nil, &InvalidNumbersOfSharesError{}
+		}
+
+		if selection.LotId != 0 {
+			lotShares, found := remaining[selection.LotId]
+			if !found {
+				return nil, NewLotNotFoundError(ticker, strconv.Itoa(selection.LotId))
+			}
+			if lotShares.LessThan(selection.Shares) {
+				return nil, NewNotEnoughSharesInLotError(strconv.Itoa(selection.LotId))
+			}
+			remaining[selection.LotId] = lotShares.Sub(selection.Shares)
+			selectedLots = append(selectedLots, NewSelectedLot(selection.LotId, selection.Shares))
+			selected = selected.Add(selection.Shares)
+			continue
+		}
+
+		needed := selection.Shares
+		found := false
+		for _, lot := range openLots {
+			if lot.BuyDate != selection.BuyDate {
+				continue
+			}
+			found = true
+			taken := remaining[lot.Id]
+			if needed.LessThan(taken) {
+				taken = needed
+			}
+			if !taken.IsPositive() {
+				continue
+			}
+			remaining[lot.Id] = remaining[lot.Id].Sub(taken)
+			selectedLots = append(selectedLots, NewSelectedLot(lot.Id, taken))
+			needed = needed.Sub(taken)
+		}
+		if !found {
+			return nil, NewLotNotFoundError(ticker, selection.BuyDate)
+		}
+		if needed.IsPositive() {
+			return nil, NewNotEnoughSharesInLotError(selection.BuyDate)
+		}
+		selected = selected.Add(selection.Shares)
+	}
+
+	if !selected.Equal(shares) {
+		return nil, &SelectedLotsDoNotMatchSharesError{}
+	}
+
+	return selectedLots, nil
+}
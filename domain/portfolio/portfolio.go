@@ -14,23 +14,58 @@ func NewPortfolio() Portfolio {
 	return Portfolio{state, []domain.DomainEvent{}}
 }
 
-func (portfolio *Portfolio) AddSharesToPortfolio(ticker string, shares int, price float32, date string) error {
-	if shares <= 0 {
+func (portfolio *Portfolio) AddSharesToPortfolio(ticker string, shares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date string) error {
+	if !shares.IsPositive() {
 		return &InvalidNumbersOfSharesError{}
 	}
 
-	sharesAddedToPortfolioEvent := NewSharesAddedToPortfolioEvent(ticker, shares, price, date)
+	fee, taxes, err := orderCharges(price, fee, taxes)
+	if err != nil {
+		return err
+	}
+
+	sharesAddedToPortfolioEvent := NewSharesAddedToPortfolioEvent(ticker, shares, price, fee, taxes, date)
 	portfolio.events = append(portfolio.events, &sharesAddedToPortfolioEvent)
 
 	return nil
 }
 
-func (portfolio *Portfolio) RemoveSharesFromPortfolio(ticker string, shares int, price float32) error {
-	if portfolio.state.GetNumberOfSharesForTicker(ticker) < shares {
+// ReinvestDividend buys as many, possibly fractional, shares of ticker at price as the net dividend pays for
+func (portfolio *Portfolio) ReinvestDividend(ticker string, net domain.Money, price domain.Money, date string) error {
+	if !price.IsPositive() {
+		return &InvalidReinvestmentPriceError{}
+	}
+
+	shares, err := net.DivMoney(price)
+	if err != nil {
+		return err
+	}
+
+	zero := domain.NewMoneyFromFloat(0, price.Currency())
+
+	return portfolio.AddSharesToPortfolio(ticker, shares, price, zero, zero, date)
+}
+
+// without selections the shares are not attributed to specific lots
+func (portfolio *Portfolio) RemoveSharesFromPortfolio(ticker string, shares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date string, selections []LotSelection) error {
+	if portfolio.state.GetNumberOfSharesForTicker(ticker).LessThan(shares) {
 		return &CantSellMoreSharesThanExistingError{}
 	}
 
-	sharesRemovedFromPortfolioEvent := NewSharesRemovedFromPortfolioEvent(ticker, shares, price)
+	fee, taxes, err := orderCharges(price, fee, taxes)
+	if err != nil {
+		return err
+	}
+
+	selectedLots := []SelectedLot{}
+	if len(selections) > 0 {
+		selectedLots, err = selectLots(portfolio.state.GetOpenLots(ticker), ticker, shares, selections)
+		if err != nil {
+			return err
+		}
+	}
+
+	sharesRemovedFromPortfolioEvent := NewSharesRemovedFromPortfolioEvent(ticker, shares, price, fee, taxes, date, selectedLots)
 	portfolio.events = append(portfolio.events, &sharesRemovedFromPortfolioEvent)
 
 	return nil
@@ -52,12 +87,103 @@ func (portfolio *Portfolio) RenameTicker(old string, new string) error {
 	return nil
 }
 
+func (portfolio *Portfolio) SplitStock(ticker string, ratioFrom int, ratioTo int, date string) error {
+	if ratioFrom <= 0 || ratioTo <= 0 {
+		return &InvalidSplitRatioError{}
+	}
+
+	_, found := portfolio.state.positions[ticker]
+	if !found {
+		return NewSplitTickerNotInPortfolioError(ticker)
+	}
+
+	stockSplitEvent := NewStockSplitEvent(ticker, ratioFrom, ratioTo, date)
+	portfolio.events = append(portfolio.events, &stockSplitEvent)
+
+	return nil
+}
+
+// ConvertShares exchanges all shares of ticker for shares of newTicker, ratioFrom shares become ratioTo shares,
+// e.g. in a merger. With cash in lieu the fraction of a new share is paid out and only whole shares are kept.
+func (portfolio *Portfolio) ConvertShares(ticker string, newTicker string, ratioFrom int, ratioTo int, cashInLieu domain.Money, date string) error {
+	if ratioFrom <= 0 || ratioTo <= 0 {
+		return &InvalidConversionRatioError{}
+	}
+
+	shares := portfolio.state.GetNumberOfSharesForTicker(ticker)
+	if !shares.IsPositive() {
+		return NewConversionTickerNotInPortfolioError(ticker)
+	}
+	if newTicker == ticker {
+		return NewTickerAlreadyUsedError(newTicker)
+	}
+	if cashInLieu.IsNegative() {
+		return &InvalidCashInLieuError{}
+	}
+
+	fractionalShares := domain.NewQuantityFromInt(0)
+	if cashInLieu.IsPositive() {
+		converted := shares.MulRatio(ratioTo, ratioFrom)
+		fractionalShares = converted.Sub(converted.Floor())
+		if !fractionalShares.IsPositive() {
+			return &CashInLieuWithoutFractionalSharesError{}
+		}
+	}
+
+	sharesConvertedEvent := NewSharesConvertedEvent(ticker, newTicker, ratioFrom, ratioTo, fractionalShares, cashInLieu, date)
+	portfolio.events = append(portfolio.events, &sharesConvertedEvent)
+
+	return nil
+}
+
+// SpinOff adds shares of newTicker to the holders of ticker, ratioFrom shares of ticker receive ratioTo new shares.
+// costBasisPercentage of the cost basis of ticker moves to the new shares.
+func (portfolio *Portfolio) SpinOff(ticker string, newTicker string, ratioFrom int, ratioTo int, costBasisPercentage domain.Percentage, date string) error {
+	if ratioFrom <= 0 || ratioTo <= 0 {
+		return &InvalidSpinOffRatioError{}
+	}
+	if !portfolio.state.GetNumberOfSharesForTicker(ticker).IsPositive() {
+		return NewSpinOffTickerNotInPortfolioError(ticker)
+	}
+	if newTicker == ticker {
+		return NewTickerAlreadyUsedError(newTicker)
+	}
+	if !costBasisPercentage.IsBetweenZeroAndHundred() {
+		return &InvalidCostBasisPercentageError{}
+	}
+
+	sharesSpunOffEvent := NewSharesSpunOffEvent(ticker, newTicker, ratioFrom, ratioTo, costBasisPercentage, date)
+	portfolio.events = append(portfolio.events, &sharesSpunOffEvent)
+
+	return nil
+}
+
+// fees and taxes are paid in the currency of the price, missing ones are zero
+func orderCharges(price domain.Money, fee domain.Money, taxes domain.Money) (domain.Money, domain.Money, error) {
+	charges := []domain.Money{fee, taxes}
+	for key, charge := range charges {
+		if charge.Currency() == "" && charge.IsZero() {
+			charges[key] = domain.NewMoneyFromFloat(0, price.Currency())
+			continue
+		}
+		if charge.Currency() != price.Currency() {
+			return domain.Money{}, domain.Money{}, domain.NewCurrencyMismatchError(price.Currency(), charge.Currency())
+		}
+		if charge.IsNegative() {
+			return domain.Money{}, domain.Money{}, &InvalidChargeError{}
+		}
+	}
+
+	return charges[0], charges[1], nil
+}
+
 func (portfolio *Portfolio) Apply(event domain.DomainEvent) {
 	if event.Name() == SharesAddedToPortfolioEventName {
 		sharesAddedToPortfolioEvent := event.(*SharesAddedToPortfolioEvent)
 		portfolio.state.AddShares(
 			sharesAddedToPortfolioEvent.ticker,
 			sharesAddedToPortfolioEvent.shares,
+			sharesAddedToPortfolioEvent.date,
 		)
 		return
 	}
@@ -66,13 +192,46 @@ func (portfolio *Portfolio) Apply(event domain.DomainEvent) {
 		portfolio.state.RemoveShares(
 			sharesRemovedFromPortfolioEvent.ticker,
 			sharesRemovedFromPortfolioEvent.shares,
+			sharesRemovedFromPortfolioEvent.lots,
 		)
 		return
 	}
 	if event.Name() == TickerRenamedEventName {
 		tickerRenamedEvent := event.(*TickerRenamedEvent)
-		portfolio.state.positions[tickerRenamedEvent.new] = portfolio.state.positions[tickerRenamedEvent.old]
-		delete(portfolio.state.positions, tickerRenamedEvent.old)
+		portfolio.state.RenameTicker(tickerRenamedEvent.old, tickerRenamedEvent.new)
+		return
+	}
+
+	if event.Name() == StockSplitEventName {
+		stockSplitEvent := event.(*StockSplitEvent)
+		portfolio.state.SplitShares(
+			stockSplitEvent.ticker,
+			stockSplitEvent.ratioFrom,
+			stockSplitEvent.ratioTo,
+		)
+		return
+	}
+
+	if event.Name() == SharesConvertedEventName {
+		sharesConvertedEvent := event.(*SharesConvertedEvent)
+		portfolio.state.ConvertShares(
+			sharesConvertedEvent.ticker,
+			sharesConvertedEvent.newTicker,
+			sharesConvertedEvent.ratioFrom,
+			sharesConvertedEvent.ratioTo,
+			sharesConvertedEvent.fractionalShares,
+		)
+		return
+	}
+
+	if event.Name() == SharesSpunOffEventName {
+		sharesSpunOffEvent := event.(*SharesSpunOffEvent)
+		portfolio.state.SpinOff(
+			sharesSpunOffEvent.ticker,
+			sharesSpunOffEvent.newTicker,
+			sharesSpunOffEvent.ratioFrom,
+			sharesSpunOffEvent.ratioTo,
+		)
 	}
 }
 
@@ -10,12 +10,12 @@ import (
 func TestCanAddShares(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	err := p.AddSharesToPortfolio("MO", 10, 9.99, "2000-01-01")
+	err := p.AddSharesToPortfolio("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	if err != nil {
 		t.Errorf("Unexpected Error. %#v", err)
 	}
 
-	expectedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 10, 9.99, "2000-01-01")
+	expectedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	expectedEventArray := []domain.DomainEvent{
 		&expectedEvent,
 	}
@@ -29,14 +29,14 @@ func TestCanAddShares(t *testing.T) {
 func TestCanRemoveShares(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 11, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	p.Apply(&sharesAddedEvent)
-	err := p.RemoveSharesFromPortfolio("MO", 10, 9.99)
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", nil)
 	if err != nil {
 		t.Errorf("Unexpected Error. %#v", err)
 	}
 
-	expectedEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 10, 9.99)
+	expectedEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
 	expectedEventArray := []domain.DomainEvent{
 		&expectedEvent,
 	}
@@ -50,12 +50,12 @@ func TestCanRemoveShares(t *testing.T) {
 func TestSharesAddedToPortfolioEventCanBeApplied(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 11, 9.99, "2000-01-01")
-	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("MO", 9, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(9), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	p.Apply(&sharesAddedEvent)
 	p.Apply(&sharesAddedEvent2)
 
-	err := p.RemoveSharesFromPortfolio("MO", 20, 9.99)
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", nil)
 
 	if err != nil {
 		t.Errorf("Unexpected Error. %#v", err)
@@ -65,12 +65,12 @@ func TestSharesAddedToPortfolioEventCanBeApplied(t *testing.T) {
 func TestSharesRemovedFromPortfolioEventCanBeApplied(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 11, 9.99, "2000-01-01")
-	sharesRemovedEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 11, 9.99)
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	sharesRemovedEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
 	p.Apply(&sharesAddedEvent)
 	p.Apply(&sharesRemovedEvent)
 
-	err := p.RemoveSharesFromPortfolio("MO", 1, 9.99)
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", nil)
 
 	_, ok := err.(*portfolio.CantSellMoreSharesThanExistingError)
 	if !ok {
@@ -81,7 +81,7 @@ func TestSharesRemovedFromPortfolioEventCanBeApplied(t *testing.T) {
 func TestCanNotBuyZeroShares(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	err := p.AddSharesToPortfolio("MO", 0, 9.99, "2000-01-01")
+	err := p.AddSharesToPortfolio("MO", domain.NewQuantityFromInt(0), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 
 	_, ok := err.(*portfolio.InvalidNumbersOfSharesError)
 	if !ok {
@@ -92,7 +92,7 @@ func TestCanNotBuyZeroShares(t *testing.T) {
 func TestCanNotBuyNegativeNumberOfShares(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	err := p.AddSharesToPortfolio("MO", -10, 9.99, "2000-01-01")
+	err := p.AddSharesToPortfolio("MO", domain.NewQuantityFromInt(-10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 
 	_, ok := err.(*portfolio.InvalidNumbersOfSharesError)
 	if !ok {
@@ -103,7 +103,7 @@ func TestCanNotBuyNegativeNumberOfShares(t *testing.T) {
 func TestCanNotSellMoreSharesThenCurrentlyInPortfolio(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	err := p.RemoveSharesFromPortfolio("MO", 21, 9.99)
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(21), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", nil)
 
 	_, ok := err.(*portfolio.CantSellMoreSharesThanExistingError)
 	if !ok {
@@ -114,7 +114,7 @@ func TestCanNotSellMoreSharesThenCurrentlyInPortfolio(t *testing.T) {
 func TestTickerCanBeRenamed(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 11, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	p.Apply(&sharesAddedEvent)
 
 	p.RenameTicker("MO", "FOO")
@@ -133,7 +133,7 @@ func TestTickerCanBeRenamed(t *testing.T) {
 func TestTickerHasToBePresentInPortfolioToBeRenamed(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 11, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	p.Apply(&sharesAddedEvent)
 
 	err := p.RenameTicker("PG", "FOO")
@@ -147,8 +147,8 @@ func TestTickerHasToBePresentInPortfolioToBeRenamed(t *testing.T) {
 func TestTickerCanBeRenamedEvenIfThereAreNoSharesHeld(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 1, 9.99, "2000-01-01")
-	removeSharesEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", 1, 9.99)
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	removeSharesEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", []portfolio.SelectedLot{})
 	p.Apply(&sharesAddedEvent)
 	p.Apply(&removeSharesEvent)
 
@@ -162,8 +162,8 @@ func TestTickerCanBeRenamedEvenIfThereAreNoSharesHeld(t *testing.T) {
 func TestNewTickerMustNotBeAlreadyInPortfolio(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 11, 9.99, "2000-01-01")
-	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("PG", 11, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	sharesAddedEvent2 := portfolio.NewSharesAddedToPortfolioEvent("PG", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	p.Apply(&sharesAddedEvent)
 	p.Apply(&sharesAddedEvent2)
 
@@ -178,14 +178,487 @@ func TestNewTickerMustNotBeAlreadyInPortfolio(t *testing.T) {
 func TestNewTickerWillBeUsedForAnyNewPortfolioCommands(t *testing.T) {
 	p := portfolio.NewPortfolio()
 
-	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", 1, 9.99, "2000-01-01")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
 	renameEvent := portfolio.NewTickerRenamedEvent("MO", "FOO")
 	p.Apply(&sharesAddedEvent)
 	p.Apply(&renameEvent)
 
-	err := p.RemoveSharesFromPortfolio("FOO", 1, 9.99)
+	err := p.RemoveSharesFromPortfolio("FOO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01", nil)
 
 	if err != nil {
 		t.Errorf("Got unexpected error: %#v", err)
 	}
 }
+
+func TestCanSplitStock(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	p.Apply(&sharesAddedEvent)
+
+	err := p.SplitStock("MO", 1, 4, "2000-01-02")
+	if err != nil {
+		t.Errorf("Unexpected Error. %#v", err)
+	}
+
+	expectedEvent := portfolio.NewStockSplitEvent("MO", 1, 4, "2000-01-02")
+	expectedEventArray := []domain.DomainEvent{
+		&expectedEvent,
+	}
+	got := p.GetRecordedEvents()
+
+	if reflect.DeepEqual(got, expectedEventArray) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEventArray, got)
+	}
+}
+
+func TestSplitSharesCanBeSold(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	splitEvent := portfolio.NewStockSplitEvent("MO", 1, 4, "2000-01-02")
+	p.Apply(&sharesAddedEvent)
+	p.Apply(&splitEvent)
+
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(40), domain.NewMoneyFromFloat(2.50, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-03", nil)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %#v", err)
+	}
+}
+
+func TestReverseSplitReducesShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	splitEvent := portfolio.NewStockSplitEvent("MO", 5, 1, "2000-01-02")
+	p.Apply(&sharesAddedEvent)
+	p.Apply(&splitEvent)
+
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(3), domain.NewMoneyFromFloat(49.95, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-03", nil)
+
+	_, ok := err.(*portfolio.CantSellMoreSharesThanExistingError)
+	if !ok {
+		t.Errorf("Expected CantSellMoreSharesThanExistingError but got %#v", err)
+	}
+}
+
+func TestSplitRatioMustBeGreaterThanZero(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	p.Apply(&sharesAddedEvent)
+
+	err := p.SplitStock("MO", 0, 4, "2000-01-02")
+
+	_, ok := err.(*portfolio.InvalidSplitRatioError)
+	if !ok {
+		t.Errorf("Expected InvalidSplitRatioError but got %#v", err)
+	}
+}
+
+func TestOnlyTickersInPortfolioCanBeSplit(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	err := p.SplitStock("MO", 1, 4, "2000-01-02")
+
+	_, ok := err.(*portfolio.SplitTickerNotInPortfolioError)
+	if !ok {
+		t.Errorf("Expected SplitTickerNotInPortfolioError but got %#v", err)
+	}
+}
+
+func TestSplitCanResultInFractionalShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	splitEvent := portfolio.NewStockSplitEvent("MO", 4, 1, "2000-01-02")
+	p.Apply(&sharesAddedEvent)
+	p.Apply(&splitEvent)
+
+	shares, _ := domain.NewQuantity("2.5")
+	err := p.RemoveSharesFromPortfolio("MO", shares, domain.NewMoneyFromFloat(39.96, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-03", nil)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %#v", err)
+	}
+}
+
+func TestFractionalSharesCanBeAddedAndRemoved(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	added, _ := domain.NewQuantity("0.4213")
+	sharesAddedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", added, domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	p.Apply(&sharesAddedEvent)
+
+	removed, _ := domain.NewQuantity("0.4214")
+	err := p.RemoveSharesFromPortfolio("MO", removed, domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", nil)
+
+	_, ok := err.(*portfolio.CantSellMoreSharesThanExistingError)
+	if !ok {
+		t.Errorf("Expected CantSellMoreSharesThanExistingError but got %#v", err)
+	}
+
+	err = p.RemoveSharesFromPortfolio("MO", added, domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02", nil)
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %#v", err)
+	}
+}
+
+func applyBuys(p *portfolio.Portfolio) {
+	buy1 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	buy2 := portfolio.NewSharesAddedToPortfolioEvent("PG", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(19.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-02")
+	buy3 := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(11.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-03")
+	p.Apply(&buy1)
+	p.Apply(&buy2)
+	p.Apply(&buy3)
+}
+
+func TestSharesCanBeSoldFromSelectedLots(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	selections := []portfolio.LotSelection{
+		{3, "", domain.NewQuantityFromInt(8)},
+		{0, "2000-01-01", domain.NewQuantityFromInt(4)},
+	}
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(12), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04", selections)
+	if err != nil {
+		t.Errorf("Unexpected Error. %#v", err)
+	}
+
+	expectedEvent := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(12), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04", []portfolio.SelectedLot{
+		portfolio.NewSelectedLot(3, domain.NewQuantityFromInt(8)),
+		portfolio.NewSelectedLot(1, domain.NewQuantityFromInt(4)),
+	})
+	expectedEventArray := []domain.DomainEvent{
+		&expectedEvent,
+	}
+	got := p.GetRecordedEvents()
+
+	if reflect.DeepEqual(got, expectedEventArray) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEventArray, got)
+	}
+}
+
+func TestSelectedLotsMustExist(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	for _, selection := range []portfolio.LotSelection{
+		{2, "", domain.NewQuantityFromInt(1)},
+		{7, "", domain.NewQuantityFromInt(1)},
+		{0, "2000-01-02", domain.NewQuantityFromInt(1)},
+	} {
+		err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04", []portfolio.LotSelection{selection})
+
+		_, ok := err.(*portfolio.LotNotFoundError)
+		if !ok {
+			t.Errorf("Expected LotNotFoundError for %#v but got %#v", selection, err)
+		}
+	}
+}
+
+func TestSelectedLotsMustHaveEnoughRemainingShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	sell := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(6), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04", []portfolio.SelectedLot{portfolio.NewSelectedLot(3, domain.NewQuantityFromInt(6))})
+	p.Apply(&sell)
+
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{3, "", domain.NewQuantityFromInt(5)}})
+
+	_, ok := err.(*portfolio.NotEnoughSharesInLotError)
+	if !ok {
+		t.Errorf("Expected NotEnoughSharesInLotError but got %#v", err)
+	}
+}
+
+func TestUnselectedSalesTakeSharesFromOldestLots(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	sell := portfolio.NewSharesRemovedFromPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04", []portfolio.SelectedLot{})
+	p.Apply(&sell)
+
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{0, "2000-01-01", domain.NewQuantityFromInt(1)}})
+
+	_, ok := err.(*portfolio.LotNotFoundError)
+	if !ok {
+		t.Errorf("Expected LotNotFoundError but got %#v", err)
+	}
+}
+
+func TestSelectedLotsMustAddUpToSoldShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(12), domain.NewMoneyFromFloat(12.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04", []portfolio.LotSelection{{1, "", domain.NewQuantityFromInt(10)}})
+
+	_, ok := err.(*portfolio.SelectedLotsDoNotMatchSharesError)
+	if !ok {
+		t.Errorf("Expected SelectedLotsDoNotMatchSharesError but got %#v", err)
+	}
+}
+
+func TestLotsFollowRenamesAndSplits(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	rename := portfolio.NewTickerRenamedEvent("MO", "FOO")
+	split := portfolio.NewStockSplitEvent("FOO", 1, 2, "2000-01-04")
+	p.Apply(&rename)
+	p.Apply(&split)
+
+	err := p.RemoveSharesFromPortfolio("FOO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(6.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{1, "", domain.NewQuantityFromInt(20)}})
+
+	if err != nil {
+		t.Errorf("Got unexpected error: %#v", err)
+	}
+}
+
+func TestMissingFeesAndTaxesAreZeroInTheCurrencyOfThePrice(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	err := p.AddSharesToPortfolio("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "USD"), domain.Money{}, domain.NewMoneyFromFloat(0.5, "USD"), "2000-01-01")
+	if err != nil {
+		t.Errorf("Unexpected Error. %#v", err)
+	}
+
+	expectedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "USD"), domain.NewMoneyFromFloat(0, "USD"), domain.NewMoneyFromFloat(0.5, "USD"), "2000-01-01")
+	expectedEventArray := []domain.DomainEvent{
+		&expectedEvent,
+	}
+	got := p.GetRecordedEvents()
+
+	if reflect.DeepEqual(got, expectedEventArray) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEventArray, got)
+	}
+}
+
+func TestFeesMustNotBeNegative(t *testing.T) {
+	p := portfolio.NewPortfolio()
+
+	err := p.AddSharesToPortfolio("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(-1, "EUR"), domain.Money{}, "2000-01-01")
+
+	_, ok := err.(*portfolio.InvalidChargeError)
+	if !ok {
+		t.Errorf("Expected InvalidChargeError but got %#v", err)
+	}
+}
+
+func TestTaxesMustBePaidInTheCurrencyOfThePrice(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.RemoveSharesFromPortfolio("MO", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(12.99, "EUR"), domain.Money{}, domain.NewMoneyFromFloat(1, "USD"), "2000-01-04", nil)
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
+func TestCanConvertShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.ConvertShares("MO", "PM", 3, 2, domain.NewMoneyFromFloat(12.5, "EUR"), "2000-01-04")
+	if err != nil {
+		t.Errorf("Unexpected Error. %#v", err)
+	}
+
+	fractionalShares, _ := domain.NewQuantity("0.33333333")
+	expectedEvent := portfolio.NewSharesConvertedEvent("MO", "PM", 3, 2, fractionalShares, domain.NewMoneyFromFloat(12.5, "EUR"), "2000-01-04")
+	expectedEventArray := []domain.DomainEvent{
+		&expectedEvent,
+	}
+	got := p.GetRecordedEvents()
+
+	if reflect.DeepEqual(got, expectedEventArray) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEventArray, got)
+	}
+}
+
+func TestConvertedSharesJoinTheHeldPositionWithTheirLots(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	conversion := portfolio.NewSharesConvertedEvent("MO", "PG", 1, 2, domain.NewQuantityFromInt(0), domain.Money{}, "2000-01-04")
+	p.Apply(&conversion)
+
+	err := p.RemoveSharesFromPortfolio("PG", domain.NewQuantityFromInt(45), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{1, "", domain.NewQuantityFromInt(20)}, {2, "", domain.NewQuantityFromInt(5)}, {3, "", domain.NewQuantityFromInt(20)}})
+	if err != nil {
+		t.Errorf("Got unexpected error: %#v", err)
+	}
+
+	err = p.ConvertShares("MO", "PM", 1, 1, domain.Money{}, "2000-01-05")
+	_, ok := err.(*portfolio.ConversionTickerNotInPortfolioError)
+	if !ok {
+		t.Errorf("Expected ConversionTickerNotInPortfolioError but got %#v", err)
+	}
+}
+
+func TestFractionalSharesPaidInCashAreRemovedFromTheOldestLots(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	fractionalShares, _ := domain.NewQuantity("0.5")
+	conversion := portfolio.NewSharesConvertedEvent("MO", "PM", 8, 1, fractionalShares, domain.NewMoneyFromFloat(10, "EUR"), "2000-01-04")
+	p.Apply(&conversion)
+
+	err := p.RemoveSharesFromPortfolio("PM", domain.NewQuantityFromInt(2), domain.NewMoneyFromFloat(79.92, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{1, "", domain.NewQuantityFromInt(1)}, {3, "", domain.NewQuantityFromInt(1)}})
+
+	_, ok := err.(*portfolio.NotEnoughSharesInLotError)
+	if !ok {
+		t.Errorf("Expected NotEnoughSharesInLotError but got %#v", err)
+	}
+}
+
+func TestConversionRatioMustBeGreaterThanZero(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.ConvertShares("MO", "PM", 1, 0, domain.Money{}, "2000-01-04")
+
+	_, ok := err.(*portfolio.InvalidConversionRatioError)
+	if !ok {
+		t.Errorf("Expected InvalidConversionRatioError but got %#v", err)
+	}
+}
+
+func TestCashInLieuMustNotBeNegative(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.ConvertShares("MO", "PM", 3, 2, domain.NewMoneyFromFloat(-1, "EUR"), "2000-01-04")
+
+	_, ok := err.(*portfolio.InvalidCashInLieuError)
+	if !ok {
+		t.Errorf("Expected InvalidCashInLieuError but got %#v", err)
+	}
+}
+
+func TestCashInLieuIsOnlyPaidForFractionalShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.ConvertShares("MO", "PM", 1, 2, domain.NewMoneyFromFloat(5, "EUR"), "2000-01-04")
+
+	_, ok := err.(*portfolio.CashInLieuWithoutFractionalSharesError)
+	if !ok {
+		t.Errorf("Expected CashInLieuWithoutFractionalSharesError but got %#v", err)
+	}
+}
+
+func TestCanSpinOffShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	costBasisPercentage, _ := domain.NewPercentage("20")
+
+	err := p.SpinOff("MO", "PM", 2, 1, costBasisPercentage, "2000-01-04")
+	if err != nil {
+		t.Errorf("Unexpected Error. %#v", err)
+	}
+
+	expectedEvent := portfolio.NewSharesSpunOffEvent("MO", "PM", 2, 1, costBasisPercentage, "2000-01-04")
+	expectedEventArray := []domain.DomainEvent{
+		&expectedEvent,
+	}
+	got := p.GetRecordedEvents()
+
+	if reflect.DeepEqual(got, expectedEventArray) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEventArray, got)
+	}
+}
+
+func TestSpunOffSharesKeepTheLotsOfTheParent(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	costBasisPercentage, _ := domain.NewPercentage("20")
+	spinOff := portfolio.NewSharesSpunOffEvent("MO", "PM", 2, 1, costBasisPercentage, "2000-01-04")
+	p.Apply(&spinOff)
+
+	err := p.RemoveSharesFromPortfolio("PM", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{3, "", domain.NewQuantityFromInt(5)}})
+	if err != nil {
+		t.Errorf("Got unexpected error: %#v", err)
+	}
+
+	err = p.RemoveSharesFromPortfolio("PM", domain.NewQuantityFromInt(6), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{{3, "", domain.NewQuantityFromInt(6)}})
+	_, ok := err.(*portfolio.NotEnoughSharesInLotError)
+	if !ok {
+		t.Errorf("Expected NotEnoughSharesInLotError but got %#v", err)
+	}
+
+	err = p.RemoveSharesFromPortfolio("PM", domain.NewQuantityFromInt(11), domain.NewMoneyFromFloat(9.99, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-05", []portfolio.LotSelection{})
+	_, ok = err.(*portfolio.CantSellMoreSharesThanExistingError)
+	if !ok {
+		t.Errorf("Expected CantSellMoreSharesThanExistingError but got %#v", err)
+	}
+}
+
+func TestSpinOffRatioMustBeGreaterThanZero(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.SpinOff("MO", "PM", 0, 1, domain.Percentage{}, "2000-01-04")
+
+	_, ok := err.(*portfolio.InvalidSpinOffRatioError)
+	if !ok {
+		t.Errorf("Expected InvalidSpinOffRatioError but got %#v", err)
+	}
+}
+
+func TestSpinOffNeedsSharesOfTheParent(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.SpinOff("KO", "PM", 2, 1, domain.Percentage{}, "2000-01-04")
+
+	_, ok := err.(*portfolio.SpinOffTickerNotInPortfolioError)
+	if !ok {
+		t.Errorf("Expected SpinOffTickerNotInPortfolioError but got %#v", err)
+	}
+}
+
+func TestCostBasisPercentageMustBeBetweenZeroAndHundred(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+	costBasisPercentage, _ := domain.NewPercentage("120")
+
+	err := p.SpinOff("MO", "PM", 2, 1, costBasisPercentage, "2000-01-04")
+
+	_, ok := err.(*portfolio.InvalidCostBasisPercentageError)
+	if !ok {
+		t.Errorf("Expected InvalidCostBasisPercentageError but got %#v", err)
+	}
+}
+
+func TestDividendCanBeReinvestedInFractionalShares(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.ReinvestDividend("MO", domain.NewMoneyFromFloat(5, "EUR"), domain.NewMoneyFromFloat(12.5, "EUR"), "2000-01-04")
+	if err != nil {
+		t.Errorf("Unexpected Error. %#v", err)
+	}
+
+	shares, _ := domain.NewQuantity("0.4")
+	expectedEvent := portfolio.NewSharesAddedToPortfolioEvent("MO", shares, domain.NewMoneyFromFloat(12.5, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04")
+	expectedEventArray := []domain.DomainEvent{
+		&expectedEvent,
+	}
+	got := p.GetRecordedEvents()
+
+	if reflect.DeepEqual(got, expectedEventArray) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEventArray, got)
+	}
+}
+
+func TestReinvestmentPriceMustBeGreaterThanZero(t *testing.T) {
+	p := portfolio.NewPortfolio()
+	applyBuys(&p)
+
+	err := p.ReinvestDividend("MO", domain.NewMoneyFromFloat(5, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-04")
+
+	_, ok := err.(*portfolio.InvalidReinvestmentPriceError)
+	if !ok {
+		t.Errorf("Expected InvalidReinvestmentPriceError but got %#v", err)
+	}
+}
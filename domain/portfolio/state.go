@@ -1,35 +1,152 @@
 package portfolio
 
+import (
+	"sort"
+	"stock-monitor/domain"
+)
+
 type PortfolioState struct {
 	positions map[string]Position
+	lots      map[string][]Lot
+	lastLotId int
 }
 
 type Position struct {
 	Ticker string
-	Shares int
+	Shares domain.Quantity
 }
 
 func NewPortfolioState() PortfolioState {
-	return PortfolioState{map[string]Position{}}
+	return PortfolioState{map[string]Position{}, map[string][]Lot{}, 0}
 }
 
-func (portfolioState *PortfolioState) GetNumberOfSharesForTicker(ticker string) int {
+func (portfolioState *PortfolioState) GetNumberOfSharesForTicker(ticker string) domain.Quantity {
 	return portfolioState.positions[ticker].Shares
 }
 
-func (portfolioState *PortfolioState) AddShares(ticker string, shares int) {
+func (portfolioState *PortfolioState) GetOpenLots(ticker string) []Lot {
+	return portfolioState.lots[ticker]
+}
+
+func (portfolioState *PortfolioState) AddShares(ticker string, shares domain.Quantity, date string) {
 	p, found := portfolioState.positions[ticker]
 	if !found {
 		portfolioState.positions[ticker] = Position{ticker, shares}
 	} else {
-		p.Shares += shares
+		p.Shares = p.Shares.Add(shares)
 		portfolioState.positions[ticker] = p
 	}
+
+	portfolioState.lastLotId++
+	portfolioState.lots[ticker] = append(portfolioState.lots[ticker], Lot{portfolioState.lastLotId, date, shares})
+}
+
+// shares not taken from selected lots are taken from the oldest lots first
+func (portfolioState *PortfolioState) RemoveShares(ticker string, shares domain.Quantity, selectedLots []SelectedLot) {
+	p := portfolioState.positions[ticker]
+
+	p.Shares = p.Shares.Sub(shares)
+	portfolioState.positions[ticker] = p
+
+	lots := portfolioState.lots[ticker]
+	remaining := shares
+	for _, selectedLot := range selectedLots {
+		for key, lot := range lots {
+			if lot.Id == selectedLot.LotId() {
+				lots[key].Shares = lot.Shares.Sub(selectedLot.Shares())
+				remaining = remaining.Sub(selectedLot.Shares())
+			}
+		}
+	}
+	for key := range lots {
+		if !remaining.IsPositive() {
+			break
+		}
+		taken := lots[key].Shares
+		if remaining.LessThan(taken) {
+			taken = remaining
+		}
+		lots[key].Shares = lots[key].Shares.Sub(taken)
+		remaining = remaining.Sub(taken)
+	}
+
+	openLots := []Lot{}
+	for _, lot := range lots {
+		if lot.Shares.IsPositive() {
+			openLots = append(openLots, lot)
+		}
+	}
+	portfolioState.lots[ticker] = openLots
+}
+
+func (portfolioState *PortfolioState) RenameTicker(old string, new string) {
+	portfolioState.positions[new] = portfolioState.positions[old]
+	delete(portfolioState.positions, old)
+
+	portfolioState.lots[new] = portfolioState.lots[old]
+	delete(portfolioState.lots, old)
 }
 
-func (portfolioState *PortfolioState) RemoveShares(ticker string, shares int) {
+func (portfolioState *PortfolioState) SplitShares(ticker string, ratioFrom int, ratioTo int) {
 	p := portfolioState.positions[ticker]
 
-	p.Shares -= shares
+	p.Shares = p.Shares.MulRatio(ratioTo, ratioFrom)
 	portfolioState.positions[ticker] = p
+
+	for key, lot := range portfolioState.lots[ticker] {
+		portfolioState.lots[ticker][key].Shares = lot.Shares.MulRatio(ratioTo, ratioFrom)
+	}
+}
+
+// the converted lots keep their ids and buy dates, the fractional shares paid in cash are taken from the oldest lots first
+func (portfolioState *PortfolioState) ConvertShares(ticker string, newTicker string, ratioFrom int, ratioTo int, fractionalShares domain.Quantity) {
+	converted := portfolioState.positions[ticker].Shares.MulRatio(ratioTo, ratioFrom).Sub(fractionalShares)
+	delete(portfolioState.positions, ticker)
+
+	p, found := portfolioState.positions[newTicker]
+	if !found {
+		p = Position{newTicker, domain.NewQuantityFromInt(0)}
+	}
+	p.Shares = p.Shares.Add(converted)
+	portfolioState.positions[newTicker] = p
+
+	lots := portfolioState.lots[newTicker]
+	remaining := fractionalShares
+	for _, lot := range portfolioState.lots[ticker] {
+		lot.Shares = lot.Shares.MulRatio(ratioTo, ratioFrom)
+		taken := remaining
+		if lot.Shares.LessThan(taken) {
+			taken = lot.Shares
+		}
+		lot.Shares = lot.Shares.Sub(taken)
+		remaining = remaining.Sub(taken)
+		if lot.Shares.IsPositive() {
+			lots = append(lots, lot)
+		}
+	}
+	delete(portfolioState.lots, ticker)
+
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].Id < lots[j].Id
+	})
+	portfolioState.lots[newTicker] = lots
+}
+
+// every lot of ticker gets a lot of newTicker with the same id and buy date
+func (portfolioState *PortfolioState) SpinOff(ticker string, newTicker string, ratioFrom int, ratioTo int) {
+	p, found := portfolioState.positions[newTicker]
+	if !found {
+		p = Position{newTicker, domain.NewQuantityFromInt(0)}
+	}
+	p.Shares = p.Shares.Add(portfolioState.positions[ticker].Shares.MulRatio(ratioTo, ratioFrom))
+	portfolioState.positions[newTicker] = p
+
+	lots := portfolioState.lots[newTicker]
+	for _, lot := range portfolioState.lots[ticker] {
+		lots = append(lots, Lot{lot.Id, lot.BuyDate, lot.Shares.MulRatio(ratioTo, ratioFrom)})
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].Id < lots[j].Id
+	})
+	portfolioState.lots[newTicker] = lots
 }
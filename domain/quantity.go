@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// divisions of quantities and money are rounded to 8 decimal places
+const decimalPrecision = 8
+
+type Quantity struct {
+	value decimal.Decimal
+}
+
+func NewQuantity(value string) (Quantity, error) {
+	parsed, err := decimal.NewFromString(value)
+	if err != nil {
+		return Quantity{}, NewInvalidQuantityError(value)
+	}
+
+	return newQuantity(parsed), nil
+}
+
+func NewQuantityFromInt(value int) Quantity {
+	return newQuantity(decimal.NewFromInt(int64(value)))
+}
+
+func NewQuantityFromFloat(value float64) Quantity {
+	return newQuantity(decimal.NewFromFloat(value))
+}
+
+func newQuantity(value decimal.Decimal) Quantity {
+	return Quantity{canonicalDecimal(value)}
+}
+
+// decimals are rebuilt from their canonical string so equal values are also deeply equal
+func canonicalDecimal(value decimal.Decimal) decimal.Decimal {
+	canonical, _ := decimal.NewFromString(value.String())
+
+	return canonical
+}
+
+func (quantity Quantity) Add(other Quantity) Quantity {
+	return newQuantity(quantity.value.Add(other.value))
+}
+
+func (quantity Quantity) Sub(other Quantity) Quantity {
+	return newQuantity(quantity.value.Sub(other.value))
+}
+
+func (quantity Quantity) MulRatio(numerator int, denominator int) Quantity {
+	multiplied := quantity.value.Mul(decimal.NewFromInt(int64(numerator)))
+
+	return newQuantity(multiplied.DivRound(decimal.NewFromInt(int64(denominator)), decimalPrecision))
+}
+
+// Floor drops the fraction, e.g. of the shares received in a conversion
+func (quantity Quantity) Floor() Quantity {
+	return newQuantity(quantity.value.Floor())
+}
+
+func (quantity Quantity) IsZero() bool {
+	return quantity.value.IsZero()
+}
+
+func (quantity Quantity) IsPositive() bool {
+	return quantity.value.IsPositive()
+}
+
+func (quantity Quantity) LessThan(other Quantity) bool {
+	return quantity.value.LessThan(other.value)
+}
+
+func (quantity Quantity) Equal(other Quantity) bool {
+	return quantity.value.Equal(other.value)
+}
+
+func (quantity Quantity) Float32() float32 {
+	value, _ := quantity.value.Float64()
+
+	return float32(value)
+}
+
+func (quantity Quantity) String() string {
+	return quantity.value.String()
+}
+
+func (quantity Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(quantity.value.String()), nil
+}
+
+func (quantity *Quantity) UnmarshalJSON(data []byte) error {
+	var value json.Number
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return NewInvalidQuantityError(string(data))
+	}
+
+	parsed, err := NewQuantity(value.String())
+	if err != nil {
+		return err
+	}
+	*quantity = parsed
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"stock-monitor/domain"
+	"testing"
+)
+
+func TestQuantityCanBeParsed(t *testing.T) {
+	got, err := domain.NewQuantity("0.4213")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if got.String() != "0.4213" {
+		t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", "0.4213", got.String())
+	}
+}
+
+func TestInvalidQuantityCanNotBeParsed(t *testing.T) {
+	_, err := domain.NewQuantity("many")
+
+	_, ok := err.(*domain.InvalidQuantityError)
+	if !ok {
+		t.Errorf("Expected InvalidQuantityError but got %#v", err)
+	}
+}
+
+func TestEqualQuantitiesAreDeeplyEqual(t *testing.T) {
+	parsed, _ := domain.NewQuantity("10.000")
+	calculated := domain.NewQuantityFromFloat(0.25).Add(domain.NewQuantityFromFloat(9.75))
+
+	if reflect.DeepEqual(parsed, domain.NewQuantityFromInt(10)) == false {
+		t.Errorf("Quantities unequal. Expected:%#v Got:%#v", domain.NewQuantityFromInt(10), parsed)
+	}
+	if reflect.DeepEqual(calculated, domain.NewQuantityFromInt(10)) == false {
+		t.Errorf("Quantities unequal. Expected:%#v Got:%#v", domain.NewQuantityFromInt(10), calculated)
+	}
+}
+
+func TestQuantityCalculationsAreExact(t *testing.T) {
+	quantity := domain.NewQuantityFromFloat(0.1).Add(domain.NewQuantityFromFloat(0.2))
+
+	if quantity.String() != "0.3" {
+		t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", "0.3", quantity.String())
+	}
+	if quantity.Sub(domain.NewQuantityFromFloat(0.3)).IsZero() == false {
+		t.Errorf("Expected quantity to be zero but got %#v", quantity.String())
+	}
+}
+
+func TestQuantityCanBeMultipliedByRatio(t *testing.T) {
+	quantity := domain.NewQuantityFromInt(10)
+
+	if got := quantity.MulRatio(4, 1).String(); got != "40" {
+		t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", "40", got)
+	}
+	if got := quantity.MulRatio(1, 3).String(); got != "3.33333333" {
+		t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", "3.33333333", got)
+	}
+}
+
+func TestQuantityCanBeFloored(t *testing.T) {
+	quantity, _ := domain.NewQuantity("13.33333333")
+
+	if got := quantity.Floor().String(); got != "13" {
+		t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", "13", got)
+	}
+}
+
+func TestQuantityIsMarshalledAsJsonNumber(t *testing.T) {
+	quantity, _ := domain.NewQuantity("0.4213")
+
+	got, _ := json.Marshal(map[string]domain.Quantity{"shares": quantity})
+
+	if string(got) != "{\"shares\":0.4213}" {
+		t.Errorf("Unexpected json. Expected:%#v Got:%#v", "{\"shares\":0.4213}", string(got))
+	}
+}
+
+func TestQuantityCanBeUnmarshalledFromJsonNumbersAndStrings(t *testing.T) {
+	want, _ := domain.NewQuantity("0.4213")
+
+	for _, input := range []string{"{\"shares\":0.4213}", "{\"shares\":\"0.4213\"}"} {
+		got := map[string]domain.Quantity{}
+		err := json.Unmarshal([]byte(input), &got)
+
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+		if reflect.DeepEqual(got["shares"], want) == false {
+			t.Errorf("Unexpected quantity. Expected:%#v Got:%#v", want, got["shares"])
+		}
+	}
+}
+
+func TestQuantityCanNotBeUnmarshalledFromInvalidJson(t *testing.T) {
+	got := map[string]domain.Quantity{}
+	err := json.Unmarshal([]byte("{\"shares\":\"many\"}"), &got)
+
+	if err == nil {
+		t.Errorf("Expected Error but got none")
+	}
+}
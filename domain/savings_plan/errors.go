@@ -0,0 +1,83 @@
+package savings_plan
+
+import "strconv"
+
+type AmountOrSharesError struct{}
+
+type UnknownIntervalError struct {
+	interval string
+}
+
+type InvalidExecutionDayError struct{}
+
+type InvalidPlanDateError struct {
+	start string
+	end   string
+}
+
+type EndBeforeStartError struct{}
+
+type PlanNotFoundError struct {
+	planId int
+}
+
+type OrderNotPendingError struct {
+	planId  int
+	dueDate string
+}
+
+type InvalidOrderSharesError struct{}
+
+type InvalidOrderPriceError struct{}
+
+func NewUnknownIntervalError(interval string) *UnknownIntervalError {
+	return &UnknownIntervalError{interval: interval}
+}
+
+func NewInvalidPlanDateError(start string, end string) *InvalidPlanDateError {
+	return &InvalidPlanDateError{start: start, end: end}
+}
+
+func NewPlanNotFoundError(planId int) *PlanNotFoundError {
+	return &PlanNotFoundError{planId: planId}
+}
+
+func NewOrderNotPendingError(planId int, dueDate string) *OrderNotPendingError {
+	return &OrderNotPendingError{planId: planId, dueDate: dueDate}
+}
+
+func (e *AmountOrSharesError) Error() string {
+	return "savings plan needs either an amount or a number of shares"
+}
+
+func (e *UnknownIntervalError) Error() string {
+	return "unknown savings plan interval. interval: " + e.interval
+}
+
+func (e *InvalidExecutionDayError) Error() string {
+	return "execution day must be between 1 and 31"
+}
+
+func (e *InvalidPlanDateError) Error() string {
+	return "invalid savings plan dates. Must be YYYY-MM-DD. start: " + e.start + " end: " + e.end
+}
+
+func (e *EndBeforeStartError) Error() string {
+	return "savings plan can't end before it starts"
+}
+
+func (e *PlanNotFoundError) Error() string {
+	return "savings plan not found. id: " + strconv.Itoa(e.planId)
+}
+
+func (e *OrderNotPendingError) Error() string {
+	return "no pending order of savings plan. id: " + strconv.Itoa(e.planId) + " due date: " + e.dueDate
+}
+
+func (e *InvalidOrderSharesError) Error() string {
+	return "shares of a savings plan order must be greater than 0"
+}
+
+func (e *InvalidOrderPriceError) Error() string {
+	return "price of a savings plan order must be greater than 0"
+}
@@ -0,0 +1,105 @@
+package savings_plan_test
+
+import (
+	"stock-monitor/domain/savings_plan"
+	"testing"
+)
+
+func TestAmountOrSharesError(t *testing.T) {
+	err := savings_plan.AmountOrSharesError{}
+
+	expected := "savings plan needs either an amount or a number of shares"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestUnknownIntervalError(t *testing.T) {
+	err := savings_plan.NewUnknownIntervalError("weekly")
+
+	expected := "unknown savings plan interval. interval: weekly"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidExecutionDayError(t *testing.T) {
+	err := savings_plan.InvalidExecutionDayError{}
+
+	expected := "execution day must be between 1 and 31"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidPlanDateError(t *testing.T) {
+	err := savings_plan.NewInvalidPlanDateError("01.01.2000", "")
+
+	expected := "invalid savings plan dates. Must be YYYY-MM-DD. start: 01.01.2000 end: "
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestEndBeforeStartError(t *testing.T) {
+	err := savings_plan.EndBeforeStartError{}
+
+	expected := "savings plan can't end before it starts"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestPlanNotFoundError(t *testing.T) {
+	err := savings_plan.NewPlanNotFoundError(3)
+
+	expected := "savings plan not found. id: 3"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestOrderNotPendingError(t *testing.T) {
+	err := savings_plan.NewOrderNotPendingError(3, "2000-01-15")
+
+	expected := "no pending order of savings plan. id: 3 due date: 2000-01-15"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidOrderSharesError(t *testing.T) {
+	err := savings_plan.InvalidOrderSharesError{}
+
+	expected := "shares of a savings plan order must be greater than 0"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidOrderPriceError(t *testing.T) {
+	err := savings_plan.InvalidOrderPriceError{}
+
+	expected := "price of a savings plan order must be greater than 0"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
@@ -0,0 +1,123 @@
+package savings_plan
+
+import "stock-monitor/domain"
+
+const SavingsPlanDefinedEventName = "SavingsPlan.SavingsPlanDefined"
+const OrderExecutedEventName = "SavingsPlan.OrderExecuted"
+
+const SavingsPlanDefinedEventVersion = 1
+const OrderExecutedEventVersion = 1
+
+type SavingsPlanDefinedEvent struct {
+	id           int
+	ticker       string
+	amount       domain.Money
+	shares       domain.Quantity
+	interval     Interval
+	executionDay int
+	start        string
+	end          string
+}
+
+func NewSavingsPlanDefinedEvent(id int, ticker string, amount domain.Money, shares domain.Quantity, interval Interval, executionDay int, start string, end string) SavingsPlanDefinedEvent {
+	return SavingsPlanDefinedEvent{id: id, ticker: ticker, amount: amount, shares: shares, interval: interval, executionDay: executionDay, start: start, end: end}
+}
+
+func (event *SavingsPlanDefinedEvent) Name() string {
+	return SavingsPlanDefinedEventName
+}
+
+func (event *SavingsPlanDefinedEvent) Version() int {
+	return SavingsPlanDefinedEventVersion
+}
+
+func (event *SavingsPlanDefinedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"id":            event.id,
+		"ticker":        event.ticker,
+		"amount":        event.amount.Amount(),
+		"currency":      event.amount.Currency(),
+		"shares":        event.shares.String(),
+		"interval":      string(event.interval),
+		"execution_day": event.executionDay,
+		"start":         event.start,
+		"end":           event.end,
+	}
+}
+
+func (event *SavingsPlanDefinedEvent) Id() int {
+	return event.id
+}
+
+func (event *SavingsPlanDefinedEvent) Ticker() string {
+	return event.ticker
+}
+
+func (event *SavingsPlanDefinedEvent) Amount() domain.Money {
+	return event.amount
+}
+
+func (event *SavingsPlanDefinedEvent) Shares() domain.Quantity {
+	return event.shares
+}
+
+func (event *SavingsPlanDefinedEvent) Interval() Interval {
+	return event.interval
+}
+
+func (event *SavingsPlanDefinedEvent) ExecutionDay() int {
+	return event.executionDay
+}
+
+func (event *SavingsPlanDefinedEvent) Start() string {
+	return event.start
+}
+
+func (event *SavingsPlanDefinedEvent) End() string {
+	return event.end
+}
+
+type OrderExecutedEvent struct {
+	planId  int
+	dueDate string
+	shares  domain.Quantity
+	price   domain.Money
+}
+
+func NewOrderExecutedEvent(planId int, dueDate string, shares domain.Quantity, price domain.Money) OrderExecutedEvent {
+	return OrderExecutedEvent{planId: planId, dueDate: dueDate, shares: shares, price: price}
+}
+
+func (event *OrderExecutedEvent) Name() string {
+	return OrderExecutedEventName
+}
+
+func (event *OrderExecutedEvent) Version() int {
+	return OrderExecutedEventVersion
+}
+
+func (event *OrderExecutedEvent) Payload() map[string]interface{} {
+	return map[string]interface{}{
+		"plan_id":  event.planId,
+		"due_date": event.dueDate,
+		"shares":   event.shares.String(),
+		"price":    event.price.Amount(),
+		"currency": event.price.Currency(),
+	}
+}
+
+func (event *OrderExecutedEvent) PlanId() int {
+	return event.planId
+}
+
+func (event *OrderExecutedEvent) DueDate() string {
+	return event.dueDate
+}
+
+func (event *OrderExecutedEvent) Shares() domain.Quantity {
+	return event.shares
+}
+
+func (event *OrderExecutedEvent) Price() domain.Money {
+	return event.price
+}
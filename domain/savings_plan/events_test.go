@@ -0,0 +1,50 @@
+package savings_plan_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/savings_plan"
+	"testing"
+)
+
+func TestSavingsPlanDefinedEventCanBeCreated(t *testing.T) {
+	event := savings_plan.NewSavingsPlanDefinedEvent(1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "2000-12-31")
+
+	if event.Name() != savings_plan.SavingsPlanDefinedEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", savings_plan.SavingsPlanDefinedEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"id":            1,
+		"ticker":        "MO",
+		"amount":        "100",
+		"currency":      "EUR",
+		"shares":        "0",
+		"interval":      "monthly",
+		"execution_day": 15,
+		"start":         "2000-01-01",
+		"end":           "2000-12-31",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
+
+func TestOrderExecutedEventCanBeCreated(t *testing.T) {
+	event := savings_plan.NewOrderExecutedEvent(1, "2000-01-15", domain.NewQuantityFromFloat(2.5), domain.NewMoneyFromFloat(40, "EUR"))
+
+	if event.Name() != savings_plan.OrderExecutedEventName {
+		t.Errorf("Unexpected events name. Expected:%#v Got:%#v", savings_plan.OrderExecutedEventName, event.Name())
+	}
+
+	expectedPayload := map[string]interface{}{
+		"plan_id":  1,
+		"due_date": "2000-01-15",
+		"shares":   "2.5",
+		"price":    "40",
+		"currency": "EUR",
+	}
+	if reflect.DeepEqual(event.Payload(), expectedPayload) == false {
+		t.Errorf("Unexpected event payload. Expected:%#v Got:%#v", expectedPayload, event.Payload())
+	}
+}
@@ -0,0 +1,221 @@
+package savings_plan
+
+import (
+	"sort"
+	"stock-monitor/domain"
+	"time"
+)
+
+type Interval string
+
+const (
+	Monthly      Interval = "monthly"
+	Quarterly    Interval = "quarterly"
+	SemiAnnually Interval = "semiannually"
+	Yearly       Interval = "yearly"
+)
+
+var intervalMonths = map[Interval]int{Monthly: 1, Quarterly: 3, SemiAnnually: 6, Yearly: 12}
+
+func ParseInterval(interval string) (Interval, error) {
+	_, found := intervalMonths[Interval(interval)]
+	if !found {
+		return "", NewUnknownIntervalError(interval)
+	}
+
+	return Interval(interval), nil
+}
+
+// Plan buys either a fixed Amount or a fixed number of Shares of Ticker every Interval on ExecutionDay
+// from Start on, an empty End keeps the plan running
+type Plan struct {
+	Id           int
+	Ticker       string
+	Amount       domain.Money
+	Shares       domain.Quantity
+	Interval     Interval
+	ExecutionDay int
+	Start        string
+	End          string
+}
+
+// DueDates are the execution days of the plan until and including the given date,
+// execution days beyond the end of a month fall on its last day
+func (plan Plan) DueDates(until string) []string {
+	dueDates := []string{}
+	start, _ := time.Parse("2006-01-02", plan.Start)
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for {
+		dueDate := executionDate(month, plan.ExecutionDay).Format("2006-01-02")
+		if dueDate > until || (plan.End != "" && dueDate > plan.End) {
+			return dueDates
+		}
+		if dueDate >= plan.Start {
+			dueDates = append(dueDates, dueDate)
+		}
+		month = month.AddDate(0, intervalMonths[plan.Interval], 0)
+	}
+}
+
+func executionDate(month time.Time, day int) time.Time {
+	lastDay := month.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// PendingOrder is a buy of a savings plan that is due but not executed yet
+type PendingOrder struct {
+	PlanId  int
+	Ticker  string
+	DueDate string
+	Amount  domain.Money
+	Shares  domain.Quantity
+}
+
+// SharesAt are the fixed shares of the plan or the (fractional) shares the amount buys at price
+func (order PendingOrder) SharesAt(price domain.Money) (domain.Quantity, error) {
+	if !price.IsPositive() {
+		return domain.Quantity{}, &InvalidOrderPriceError{}
+	}
+	if order.Shares.IsPositive() {
+		return order.Shares, nil
+	}
+
+	return order.Amount.DivMoney(price)
+}
+
+// SavingsPlans are the savings plans of a portfolio and the orders they executed
+type SavingsPlans struct {
+	plans    map[int]Plan
+	executed map[int]map[string]bool
+	lastId   int
+	events   []domain.DomainEvent
+}
+
+func NewSavingsPlans() SavingsPlans {
+	return SavingsPlans{map[int]Plan{}, map[int]map[string]bool{}, 0, []domain.DomainEvent{}}
+}
+
+// Define adds a plan with either an amount or a number of shares, an empty end keeps the plan running
+func (savingsPlans *SavingsPlans) Define(ticker string, amount domain.Money, shares domain.Quantity, interval string, executionDay int, start string, end string) error {
+	if amount.IsPositive() == shares.IsPositive() || amount.IsNegative() || shares.LessThan(domain.NewQuantityFromInt(0)) {
+		return &AmountOrSharesError{}
+	}
+	parsedInterval, err := ParseInterval(interval)
+	if err != nil {
+		return err
+	}
+	if executionDay < 1 || executionDay > 31 {
+		return &InvalidExecutionDayError{}
+	}
+	if !isDate(start) || (end != "" && !isDate(end)) {
+		return NewInvalidPlanDateError(start, end)
+	}
+	if end != "" && end < start {
+		return &EndBeforeStartError{}
+	}
+
+	if !amount.IsPositive() {
+		amount = domain.NewMoneyFromFloat(0, amount.Currency())
+	}
+	if !shares.IsPositive() {
+		shares = domain.NewQuantityFromInt(0)
+	}
+
+	savingsPlanDefinedEvent := NewSavingsPlanDefinedEvent(savingsPlans.lastId+1, ticker, amount, shares, parsedInterval, executionDay, start, end)
+	savingsPlans.events = append(savingsPlans.events, &savingsPlanDefinedEvent)
+
+	return nil
+}
+
+// ExecuteOrder records that the pending order of the plan on the due date was bought
+func (savingsPlans *SavingsPlans) ExecuteOrder(planId int, dueDate string, shares domain.Quantity, price domain.Money) error {
+	_, err := savingsPlans.PendingOrder(planId, dueDate)
+	if err != nil {
+		return err
+	}
+	if !shares.IsPositive() {
+		return &InvalidOrderSharesError{}
+	}
+	if !price.IsPositive() {
+		return &InvalidOrderPriceError{}
+	}
+
+	orderExecutedEvent := NewOrderExecutedEvent(planId, dueDate, shares, price)
+	savingsPlans.events = append(savingsPlans.events, &orderExecutedEvent)
+
+	return nil
+}
+
+func (savingsPlans *SavingsPlans) Plans() []Plan {
+	plans := []Plan{}
+	for _, plan := range savingsPlans.plans {
+		plans = append(plans, plan)
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].Id < plans[j].Id
+	})
+
+	return plans
+}
+
+// PendingOrders are the orders due until and including the given date, oldest first
+func (savingsPlans *SavingsPlans) PendingOrders(until string) []PendingOrder {
+	orders := []PendingOrder{}
+	for _, plan := range savingsPlans.Plans() {
+		for _, dueDate := range plan.DueDates(until) {
+			if savingsPlans.executed[plan.Id][dueDate] {
+				continue
+			}
+			orders = append(orders, PendingOrder{plan.Id, plan.Ticker, dueDate, plan.Amount, plan.Shares})
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].DueDate < orders[j].DueDate
+	})
+
+	return orders
+}
+
+func (savingsPlans *SavingsPlans) PendingOrder(planId int, dueDate string) (PendingOrder, error) {
+	plan, found := savingsPlans.plans[planId]
+	if !found {
+		return PendingOrder{}, NewPlanNotFoundError(planId)
+	}
+
+	dueDates := plan.DueDates(dueDate)
+	if len(dueDates) == 0 || dueDates[len(dueDates)-1] != dueDate || savingsPlans.executed[planId][dueDate] {
+		return PendingOrder{}, NewOrderNotPendingError(planId, dueDate)
+	}
+
+	return PendingOrder{plan.Id, plan.Ticker, dueDate, plan.Amount, plan.Shares}, nil
+}
+
+func (savingsPlans *SavingsPlans) GetRecordedEvents() []domain.DomainEvent {
+	return savingsPlans.events
+}
+
+func (savingsPlans *SavingsPlans) Apply(event domain.DomainEvent) {
+	switch event := event.(type) {
+	case *SavingsPlanDefinedEvent:
+		savingsPlans.plans[event.Id()] = Plan{event.Id(), event.Ticker(), event.Amount(), event.Shares(), event.Interval(), event.ExecutionDay(), event.Start(), event.End()}
+		savingsPlans.executed[event.Id()] = map[string]bool{}
+		if event.Id() > savingsPlans.lastId {
+			savingsPlans.lastId = event.Id()
+		}
+	case *OrderExecutedEvent:
+		executed, found := savingsPlans.executed[event.PlanId()]
+		if found {
+			executed[event.DueDate()] = true
+		}
+	}
+}
+
+func isDate(date string) bool {
+	_, err := time.Parse("2006-01-02", date)
+
+	return err == nil
+}
@@ -0,0 +1,202 @@
+package savings_plan_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/savings_plan"
+	"testing"
+)
+
+func TestCanDefineSavingsPlanWithAmount(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+
+	err := plans.Define("MO", domain.NewMoneyFromFloat(100, "EUR"), domain.Quantity{}, "monthly", 15, "2000-01-01", "")
+
+	expectedEvent := savings_plan.NewSavingsPlanDefinedEvent(1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "")
+	expectedEvents := []domain.DomainEvent{&expectedEvent}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(plans.GetRecordedEvents(), expectedEvents) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEvents, plans.GetRecordedEvents())
+	}
+}
+
+func TestCanDefineSavingsPlanWithShares(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+	definedEvent := savings_plan.NewSavingsPlanDefinedEvent(1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "")
+	plans.Apply(&definedEvent)
+
+	err := plans.Define("PG", domain.Money{}, domain.NewQuantityFromInt(2), "quarterly", 1, "2000-01-01", "2000-12-31")
+
+	expectedEvent := savings_plan.NewSavingsPlanDefinedEvent(2, "PG", domain.NewMoneyFromFloat(0, ""), domain.NewQuantityFromInt(2), savings_plan.Quarterly, 1, "2000-01-01", "2000-12-31")
+	expectedEvents := []domain.DomainEvent{&expectedEvent}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(plans.GetRecordedEvents(), expectedEvents) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEvents, plans.GetRecordedEvents())
+	}
+}
+
+func TestSavingsPlanNeedsEitherAmountOrShares(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+
+	err := plans.Define("MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(2), "monthly", 15, "2000-01-01", "")
+
+	_, ok := err.(*savings_plan.AmountOrSharesError)
+	if !ok {
+		t.Errorf("Expected AmountOrSharesError but got %#v", err)
+	}
+
+	err = plans.Define("MO", domain.Money{}, domain.Quantity{}, "monthly", 15, "2000-01-01", "")
+
+	_, ok = err.(*savings_plan.AmountOrSharesError)
+	if !ok {
+		t.Errorf("Expected AmountOrSharesError but got %#v", err)
+	}
+}
+
+func TestSavingsPlanDefinitionIsValidated(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+	amount := domain.NewMoneyFromFloat(100, "EUR")
+
+	err := plans.Define("MO", amount, domain.Quantity{}, "weekly", 15, "2000-01-01", "")
+	_, ok := err.(*savings_plan.UnknownIntervalError)
+	if !ok {
+		t.Errorf("Expected UnknownIntervalError but got %#v", err)
+	}
+
+	err = plans.Define("MO", amount, domain.Quantity{}, "monthly", 32, "2000-01-01", "")
+	_, ok = err.(*savings_plan.InvalidExecutionDayError)
+	if !ok {
+		t.Errorf("Expected InvalidExecutionDayError but got %#v", err)
+	}
+
+	err = plans.Define("MO", amount, domain.Quantity{}, "monthly", 15, "01.01.2000", "")
+	_, ok = err.(*savings_plan.InvalidPlanDateError)
+	if !ok {
+		t.Errorf("Expected InvalidPlanDateError but got %#v", err)
+	}
+
+	err = plans.Define("MO", amount, domain.Quantity{}, "monthly", 15, "2000-01-01", "1999-12-31")
+	_, ok = err.(*savings_plan.EndBeforeStartError)
+	if !ok {
+		t.Errorf("Expected EndBeforeStartError but got %#v", err)
+	}
+}
+
+func TestDueDatesFallOnTheLastDayOfShortMonths(t *testing.T) {
+	plan := savings_plan.Plan{1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 31, "2000-01-15", ""}
+
+	got := plan.DueDates("2000-04-30")
+
+	want := []string{"2000-01-31", "2000-02-29", "2000-03-31", "2000-04-30"}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected due dates. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestDueDatesStartAfterTheStartAndStopAtTheEnd(t *testing.T) {
+	plan := savings_plan.Plan{1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Quarterly, 1, "2000-01-15", "2000-10-01"}
+
+	got := plan.DueDates("2001-12-31")
+
+	want := []string{"2000-04-01", "2000-07-01", "2000-10-01"}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected due dates. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestPendingOrdersAreDueAndNotExecuted(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+	amountPlan := savings_plan.NewSavingsPlanDefinedEvent(1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "")
+	sharesPlan := savings_plan.NewSavingsPlanDefinedEvent(2, "PG", domain.NewMoneyFromFloat(0, ""), domain.NewQuantityFromInt(2), savings_plan.Yearly, 1, "2000-02-01", "")
+	executedEvent := savings_plan.NewOrderExecutedEvent(1, "2000-01-15", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(25, "EUR"))
+	plans.Apply(&amountPlan)
+	plans.Apply(&sharesPlan)
+	plans.Apply(&executedEvent)
+
+	got := plans.PendingOrders("2000-03-14")
+
+	want := []savings_plan.PendingOrder{
+		{2, "PG", "2000-02-01", domain.NewMoneyFromFloat(0, ""), domain.NewQuantityFromInt(2)},
+		{1, "MO", "2000-02-15", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0)},
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected pending orders. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestPendingOrderBuysTheSharesTheAmountIsWorth(t *testing.T) {
+	order := savings_plan.PendingOrder{1, "MO", "2000-01-15", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0)}
+
+	shares, err := order.SharesAt(domain.NewMoneyFromFloat(40, "EUR"))
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if shares.String() != "2.5" {
+		t.Errorf("Unexpected shares. Expected:%#v Got:%#v", "2.5", shares.String())
+	}
+}
+
+func TestCanExecutePendingOrder(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+	definedEvent := savings_plan.NewSavingsPlanDefinedEvent(1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "")
+	plans.Apply(&definedEvent)
+
+	err := plans.ExecuteOrder(1, "2000-01-15", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(25, "EUR"))
+
+	expectedEvent := savings_plan.NewOrderExecutedEvent(1, "2000-01-15", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(25, "EUR"))
+	expectedEvents := []domain.DomainEvent{&expectedEvent}
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(plans.GetRecordedEvents(), expectedEvents) == false {
+		t.Errorf("Expected domain event missing. Expected:%#v Got:%#v", expectedEvents, plans.GetRecordedEvents())
+	}
+}
+
+func TestCanOnlyExecutePendingOrders(t *testing.T) {
+	plans := savings_plan.NewSavingsPlans()
+	definedEvent := savings_plan.NewSavingsPlanDefinedEvent(1, "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.NewQuantityFromInt(0), savings_plan.Monthly, 15, "2000-01-01", "")
+	executedEvent := savings_plan.NewOrderExecutedEvent(1, "2000-01-15", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(25, "EUR"))
+	plans.Apply(&definedEvent)
+	plans.Apply(&executedEvent)
+	shares := domain.NewQuantityFromInt(4)
+	price := domain.NewMoneyFromFloat(25, "EUR")
+
+	err := plans.ExecuteOrder(2, "2000-01-15", shares, price)
+	_, ok := err.(*savings_plan.PlanNotFoundError)
+	if !ok {
+		t.Errorf("Expected PlanNotFoundError but got %#v", err)
+	}
+
+	err = plans.ExecuteOrder(1, "2000-01-15", shares, price)
+	_, ok = err.(*savings_plan.OrderNotPendingError)
+	if !ok {
+		t.Errorf("Expected OrderNotPendingError but got %#v", err)
+	}
+
+	err = plans.ExecuteOrder(1, "2000-02-14", shares, price)
+	_, ok = err.(*savings_plan.OrderNotPendingError)
+	if !ok {
+		t.Errorf("Expected OrderNotPendingError but got %#v", err)
+	}
+
+	err = plans.ExecuteOrder(1, "2000-02-15", shares, domain.NewMoneyFromFloat(0, "EUR"))
+	_, ok = err.(*savings_plan.InvalidOrderPriceError)
+	if !ok {
+		t.Errorf("Expected InvalidOrderPriceError but got %#v", err)
+	}
+
+	err = plans.ExecuteOrder(1, "2000-02-15", domain.NewQuantityFromInt(0), price)
+	_, ok = err.(*savings_plan.InvalidOrderSharesError)
+	if !ok {
+		t.Errorf("Expected InvalidOrderSharesError but got %#v", err)
+	}
+}
@@ -3,6 +3,8 @@ module stock-monitor
 go 1.15
 
 require (
-	github.com/labstack/echo/v4 v4.10.2 // indirect
+	github.com/labstack/echo/v4 v4.10.2
+	github.com/shopspring/decimal v1.3.1
 	github.com/urfave/cli/v2 v2.2.0
+	modernc.org/sqlite v1.21.2
 )
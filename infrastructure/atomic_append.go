@@ -0,0 +1,249 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"os"
+)
+
+// StreamBatch is a batch of events to be appended to an event stream at the expected version,
+// an empty batch only pins the version of its stream
+type StreamBatch struct {
+	EventStream     EventStream
+	Events          []Event
+	ExpectedVersion int
+}
+
+// lockableEventStream is an event stream whose writers are serialized by its own lock.
+// state and stage may only be called while that lock is held.
+type lockableEventStream interface {
+	lock() *streamLock
+	state() (version int, lastOccurredAt string, err error)
+	stage(events []Event) (stagedAppend, error)
+}
+
+// stagedAppend is a prepared append that becomes visible on commit. restore takes back a committed append
+// and is only safe while the lock of the stream is still held.
+type stagedAppend interface {
+	commit() error
+	discard()
+	restore() error
+}
+
+type pendingAppend struct {
+	eventStream    lockableEventStream
+	events         []Event
+	version        int
+	lastOccurredAt string
+}
+
+// AppendAtomically appends all batches or none of them. Sqlite streams sharing a database are appended in a
+// single transaction, the other streams are checked and written while holding their locks, so no other writer
+// can append in between.
+func AppendAtomically(batches []StreamBatch) error {
+	db, ok, err := sharedDatabase(batches)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return appendInTransaction(db, batches)
+	}
+
+	return appendLocked(batches)
+}
+
+// sharedDatabase returns the database of the batches when all of them go to sqlite event streams
+func sharedDatabase(batches []StreamBatch) (*sql.DB, bool, error) {
+	var db *sql.DB
+	sqliteBatches := 0
+	for _, batch := range batches {
+		eventStream, ok := batch.EventStream.(*SqliteEventStream)
+		if !ok {
+			continue
+		}
+		if db != nil && db != eventStream.db {
+			return nil, false, NewAtomicAppendNotSupportedError("Sqlite event streams of different databases can't be appended atomically")
+		}
+		db = eventStream.db
+		sqliteBatches++
+	}
+
+	if sqliteBatches > 0 && sqliteBatches < len(batches) {
+		return nil, false, NewAtomicAppendNotSupportedError("Sqlite event streams can't be appended atomically with other event streams")
+	}
+
+	return db, db != nil, nil
+}
+
+func appendInTransaction(db *sql.DB, batches []StreamBatch) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, batch := range batches {
+		err = batch.EventStream.(*SqliteEventStream).appendInTransaction(tx, batch.Events, batch.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func appendLocked(batches []StreamBatch) error {
+	eventStreams := []lockableEventStream{}
+	for _, batch := range batches {
+		eventStream, ok := batch.EventStream.(lockableEventStream)
+		if !ok {
+			return NewAtomicAppendNotSupportedError("Event stream can't be appended atomically")
+		}
+		eventStreams = append(eventStreams, eventStream)
+	}
+
+	locks := []*streamLock{}
+	for _, eventStream := range eventStreams {
+		locks = append(locks, eventStream.lock())
+	}
+	release, err := acquireAll(locks)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// a stream can receive several batches, each of them is checked against the ones before
+	pending := map[lockableEventStream]*pendingAppend{}
+	order := []*pendingAppend{}
+	for index, batch := range batches {
+		current, ok := pending[eventStreams[index]]
+		if !ok {
+			version, last, err := eventStreams[index].state()
+			if err != nil {
+				return err
+			}
+			current = &pendingAppend{eventStreams[index], []Event{}, version, last}
+			pending[eventStreams[index]] = current
+			order = append(order, current)
+		}
+
+		if batch.ExpectedVersion != AnyVersion && batch.ExpectedVersion != current.version {
+			return NewConcurrencyConflictError(batch.ExpectedVersion, current.version)
+		}
+
+		err := validateBatch(batch.Events, current.lastOccurredAt)
+		if err != nil {
+			return err
+		}
+
+		current.events = append(current.events, batch.Events...)
+		current.version += len(batch.Events)
+		if len(batch.Events) > 0 {
+			current.lastOccurredAt = lastOccurredAt(batch.Events)
+		}
+	}
+
+	staged := []stagedAppend{}
+	for _, current := range order {
+		if len(current.events) == 0 {
+			continue
+		}
+		stagedEvents, err := current.eventStream.stage(current.events)
+		if err != nil {
+			discardAll(staged)
+			return err
+		}
+		staged = append(staged, stagedEvents)
+	}
+
+	for index, stagedEvents := range staged {
+		err := stagedEvents.commit()
+		if err != nil {
+			discardAll(staged[index+1:])
+			restoreErr := restoreAll(staged[:index])
+			if restoreErr != nil {
+				return restoreErr
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func discardAll(staged []stagedAppend) {
+	for _, stagedEvents := range staged {
+		stagedEvents.discard()
+	}
+}
+
+// restoreAll takes back committed appends, the locks are still held so no other writer appended since
+func restoreAll(staged []stagedAppend) error {
+	for index := len(staged) - 1; index >= 0; index-- {
+		err := staged[index].restore()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type stagedInMemoryEvents struct {
+	eventStream *InMemoryEventStream
+	events      []Event
+	version     int
+}
+
+func (staged *stagedInMemoryEvents) commit() error {
+	staged.eventStream.Events = append(staged.eventStream.Events, staged.events...)
+	return nil
+}
+
+func (staged *stagedInMemoryEvents) discard() {}
+
+func (staged *stagedInMemoryEvents) restore() error {
+	staged.eventStream.Events = staged.eventStream.Events[:staged.version]
+	return nil
+}
+
+// stagedFile is the new content of an event stream file, written next to it and renamed over it on commit
+type stagedFile struct {
+	filePath string
+	tempPath string
+	previous []byte
+	existed  bool
+}
+
+func stageFile(filePath string, previous []byte, existed bool, data []byte) (*stagedFile, error) {
+	tempPath, err := writeTemporarily(filePath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stagedFile{filePath, tempPath, previous, existed}, nil
+}
+
+func (staged *stagedFile) commit() error {
+	return os.Rename(staged.tempPath, staged.filePath)
+}
+
+func (staged *stagedFile) discard() {
+	os.Remove(staged.tempPath)
+}
+
+func (staged *stagedFile) restore() error {
+	if !staged.existed {
+		return os.Remove(staged.filePath)
+	}
+
+	return WriteAtomically(staged.filePath, staged.previous)
+}
+
+func readFile(filePath string) ([]byte, bool, error) {
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return []byte{}, false, nil
+	}
+
+	return data, err == nil, err
+}
@@ -1,54 +1,332 @@
 package di
 
 import (
+	"database/sql"
+	"log"
 	"os"
+	"path/filepath"
+	cash_command_handler "stock-monitor/application/cash/command_handler"
+	cash_persistence "stock-monitor/application/cash/persistence"
 	command_handler2 "stock-monitor/application/dividend/command_handler"
 	persistence2 "stock-monitor/application/dividend/persistence"
 	"stock-monitor/application/event"
 	"stock-monitor/application/portfolio/command_handler"
 	"stock-monitor/application/portfolio/persistence"
+	savings_plan_command_handler "stock-monitor/application/savings_plan/command_handler"
+	savings_plan_persistence "stock-monitor/application/savings_plan/persistence"
+	"stock-monitor/application/shared"
+	"stock-monitor/application/transaction_import"
+	"stock-monitor/domain"
 	"stock-monitor/infrastructure"
 	"stock-monitor/query"
+	cash_account "stock-monitor/query/cash-account"
 	dividend_history "stock-monitor/query/dividend-history"
+	"stock-monitor/query/fees"
+	"stock-monitor/query/lots"
 	orderHistory "stock-monitor/query/order-history"
+	"stock-monitor/query/performance"
+	portfolio_history "stock-monitor/query/portfolio-history"
 	positionList "stock-monitor/query/position_list"
+	realized_gains "stock-monitor/query/realized-gains"
+	savings_plans "stock-monitor/query/savings-plans"
+	"strings"
 )
 
-func MakePortfolioEventStream() infrastructure.EventStream {
-	return &infrastructure.FileSystemEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), os.Getenv("PORTFOLIO_EVENT_STREAM_FILE")}
+var sqliteDatabase *sql.DB
+var priceStore query.PriceStore
+var valueTracker query.ValueTracker
+
+// the default portfolio is always available, PORTFOLIO_IDS lists additional portfolios separated by comma
+func PortfolioIds() []string {
+	portfolioIds := []string{shared.DefaultPortfolioId}
+	for _, portfolioId := range strings.Split(os.Getenv("PORTFOLIO_IDS"), ",") {
+		portfolioId = strings.TrimSpace(portfolioId)
+		if portfolioId == "" || portfolioId == shared.DefaultPortfolioId {
+			continue
+		}
+		portfolioIds = append(portfolioIds, portfolioId)
+	}
+
+	return portfolioIds
+}
+
+func MakePortfolioEventStream(portfolioId string) infrastructure.EventStream {
+	if os.Getenv("EVENT_STREAM_BACKEND") == "sqlite" {
+		return infrastructure.NewSqliteEventStream(makeSqliteDatabase(), streamName("portfolio", portfolioId))
+	}
+	if os.Getenv("EVENT_STREAM_BACKEND") == "jsonl" {
+		return &infrastructure.JsonLinesEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), infrastructure.JsonLinesFileName(streamName(os.Getenv("PORTFOLIO_EVENT_STREAM_FILE"), portfolioId))}
+	}
+
+	return &infrastructure.FileSystemEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), streamName(os.Getenv("PORTFOLIO_EVENT_STREAM_FILE"), portfolioId)}
+}
+
+func MakeDividendEventStream(portfolioId string) infrastructure.EventStream {
+	if os.Getenv("EVENT_STREAM_BACKEND") == "sqlite" {
+		return infrastructure.NewSqliteEventStream(makeSqliteDatabase(), streamName("dividend", portfolioId))
+	}
+	if os.Getenv("EVENT_STREAM_BACKEND") == "jsonl" {
+		return &infrastructure.JsonLinesEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), infrastructure.JsonLinesFileName(streamName(os.Getenv("DIVIDEND_EVENT_STREAM_FILE"), portfolioId))}
+	}
+
+	return &infrastructure.FileSystemEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), streamName(os.Getenv("DIVIDEND_EVENT_STREAM_FILE"), portfolioId)}
+}
+
+func MakeCashEventStream(portfolioId string) infrastructure.EventStream {
+	if os.Getenv("EVENT_STREAM_BACKEND") == "sqlite" {
+		return infrastructure.NewSqliteEventStream(makeSqliteDatabase(), streamName("cash", portfolioId))
+	}
+	if os.Getenv("EVENT_STREAM_BACKEND") == "jsonl" {
+		return &infrastructure.JsonLinesEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), infrastructure.JsonLinesFileName(streamName(os.Getenv("CASH_EVENT_STREAM_FILE"), portfolioId))}
+	}
+
+	return &infrastructure.FileSystemEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), streamName(os.Getenv("CASH_EVENT_STREAM_FILE"), portfolioId)}
+}
+
+func MakeSavingsPlanEventStream(portfolioId string) infrastructure.EventStream {
+	if os.Getenv("EVENT_STREAM_BACKEND") == "sqlite" {
+		return infrastructure.NewSqliteEventStream(makeSqliteDatabase(), streamName("savings_plan", portfolioId))
+	}
+	if os.Getenv("EVENT_STREAM_BACKEND") == "jsonl" {
+		return &infrastructure.JsonLinesEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), infrastructure.JsonLinesFileName(streamName(os.Getenv("SAVINGS_PLAN_EVENT_STREAM_FILE"), portfolioId))}
+	}
+
+	return &infrastructure.FileSystemEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), streamName(os.Getenv("SAVINGS_PLAN_EVENT_STREAM_FILE"), portfolioId)}
+}
+
+// EventStreamFiles are the configured gob files of the portfolio, dividend, cash and savings plan streams of every portfolio
+func EventStreamFiles() []string {
+	files := []string{}
+	for _, portfolioId := range PortfolioIds() {
+		for _, variable := range []string{"PORTFOLIO_EVENT_STREAM_FILE", "DIVIDEND_EVENT_STREAM_FILE", "CASH_EVENT_STREAM_FILE", "SAVINGS_PLAN_EVENT_STREAM_FILE"} {
+			files = append(files, os.Getenv("EVENT_STREAM_STORAGE_PATH")+streamName(os.Getenv(variable), portfolioId))
+		}
+	}
+
+	return files
+}
+
+// the default portfolio keeps the configured stream names, so existing streams stay in use
+func streamName(name string, portfolioId string) string {
+	if portfolioId == shared.DefaultPortfolioId {
+		return name
+	}
+
+	extension := filepath.Ext(name)
+	return strings.TrimSuffix(name, extension) + "_" + portfolioId + extension
+}
+
+func makeSqliteDatabase() *sql.DB {
+	if sqliteDatabase != nil {
+		return sqliteDatabase
+	}
+
+	db, err := infrastructure.OpenSqliteDatabase(os.Getenv("EVENT_STREAM_STORAGE_PATH") + os.Getenv("SQLITE_DATABASE_FILE"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sqliteDatabase = db
+
+	return sqliteDatabase
 }
 
-func MakeDividendEventStream() infrastructure.EventStream {
-	return &infrastructure.FileSystemEventStream{os.Getenv("EVENT_STREAM_STORAGE_PATH"), os.Getenv("DIVIDEND_EVENT_STREAM_FILE")}
+func BaseCurrency() string {
+	if os.Getenv("BASE_CURRENCY") != "" {
+		return os.Getenv("BASE_CURRENCY")
+	}
+
+	return domain.DefaultCurrency
 }
 
-func MakePositionListQuery() positionList.PositionListQuery {
-	eventStream := MakePortfolioEventStream()
-	return &positionList.EventStreamedPositionListQuery{eventStream, query.NewFinnHubValueTracker(os.Getenv("FINNHUB_TOKEN"))}
+func MakeExchangeRateProvider() query.ExchangeRateProvider {
+	return query.NewFileExchangeRateProvider(os.Getenv("EVENT_STREAM_STORAGE_PATH") + os.Getenv("EXCHANGE_RATES_FILE"))
 }
 
-func MakeOrderHistoryQuery() orderHistory.OrderHistoryQueryInterface {
-	eventStream := MakePortfolioEventStream()
+// all queries share one store, its lock serializes the snapshots of live values recorded by concurrent requests
+func MakePriceStore() query.PriceStore {
+	if priceStore == nil {
+		priceStore = query.NewFilePriceStore(os.Getenv("EVENT_STREAM_STORAGE_PATH") + os.Getenv("PRICE_HISTORY_FILE"))
+	}
+
+	return priceStore
+}
+
+// all queries share one tracker, so a ticker held in several portfolios is recorded once a day
+func MakeValueTracker() query.ValueTracker {
+	if valueTracker == nil {
+		valueTracker = query.NewRecordingValueTracker(query.NewFinnHubValueTracker(os.Getenv("FINNHUB_TOKEN")), MakePriceStore())
+	}
+
+	return valueTracker
+}
+
+func MakePositionListQuery(portfolioId string) positionList.PositionListQuery {
+	return &positionList.EventStreamedPositionListQuery{MakePortfolioEventStream(portfolioId), MakeValueTracker(), MakeExchangeRateProvider(), BaseCurrency(), LotMatchingMethod(), MakePriceStore()}
+}
+
+func MakeAggregatedPositionListQuery() positionList.PositionListQuery {
+	queries := []positionList.PositionListQuery{}
+	for _, portfolioId := range PortfolioIds() {
+		queries = append(queries, MakePositionListQuery(portfolioId))
+	}
+
+	return &positionList.AggregatedPositionListQuery{queries}
+}
+
+func MakeOrderHistoryQuery(portfolioId string) orderHistory.OrderHistoryQueryInterface {
+	eventStream := MakePortfolioEventStream(portfolioId)
 	return &orderHistory.OrderHistoryQuery{eventStream}
 }
 
-func MakeDividendHistoryQuery() dividend_history.DividendHistoryQueryInterface {
-	eventStream := MakeDividendEventStream()
-	dividendQuery := dividend_history.NewDividendHistoryQuery(eventStream)
+func MakeDividendHistoryQuery(portfolioId string) dividend_history.DividendHistoryQueryInterface {
+	eventStream := MakeDividendEventStream(portfolioId)
+	dividendQuery := dividend_history.NewDividendHistoryQuery(eventStream, MakeExchangeRateProvider(), BaseCurrency())
 	return &dividendQuery
 }
 
+// LOT_MATCHING_METHOD is one of fifo, lifo or average and defaults to fifo
+// an unset LOT_MATCHING_METHOD matches fifo, an unknown one stops the server instead of silently matching fifo
+func LotMatchingMethod() lots.MatchingMethod {
+	if os.Getenv("LOT_MATCHING_METHOD") == "" {
+		return lots.FirstInFirstOut
+	}
+
+	method, err := lots.ParseMatchingMethod(os.Getenv("LOT_MATCHING_METHOD"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return method
+}
+
+func MakeRealizedGainsQuery(portfolioId string) realized_gains.RealizedGainsQueryInterface {
+	eventStream := MakePortfolioEventStream(portfolioId)
+	return &realized_gains.RealizedGainsQuery{eventStream, MakeExchangeRateProvider(), BaseCurrency()}
+}
+
+func MakeLotsQuery(portfolioId string) lots.LotsQueryInterface {
+	eventStream := MakePortfolioEventStream(portfolioId)
+	return &lots.LotsQuery{eventStream}
+}
+
+func MakeFeesQuery(portfolioId string) fees.FeesQueryInterface {
+	return &fees.FeesQuery{MakeOrderHistoryQuery(portfolioId), MakeExchangeRateProvider(), BaseCurrency()}
+}
+
+func MakePerformanceQuery(portfolioId string) performance.PerformanceQueryInterface {
+	return &performance.PerformanceQuery{MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId), MakePriceStore(), MakeExchangeRateProvider(), BaseCurrency()}
+}
+
+func MakePortfolioHistoryQuery(portfolioId string) portfolio_history.PortfolioHistoryQueryInterface {
+	return &portfolio_history.PortfolioHistoryQuery{[]infrastructure.EventStream{MakePortfolioEventStream(portfolioId)}, []infrastructure.EventStream{MakeDividendEventStream(portfolioId)}, MakePriceStore(), MakeExchangeRateProvider(), BaseCurrency()}
+}
+
+func MakeAggregatedPortfolioHistoryQuery() portfolio_history.PortfolioHistoryQueryInterface {
+	portfolioEventStreams := []infrastructure.EventStream{}
+	dividendEventStreams := []infrastructure.EventStream{}
+	for _, portfolioId := range PortfolioIds() {
+		portfolioEventStreams = append(portfolioEventStreams, MakePortfolioEventStream(portfolioId))
+		dividendEventStreams = append(dividendEventStreams, MakeDividendEventStream(portfolioId))
+	}
+
+	return &portfolio_history.PortfolioHistoryQuery{portfolioEventStreams, dividendEventStreams, MakePriceStore(), MakeExchangeRateProvider(), BaseCurrency()}
+}
+
+func MakeCashAccountQuery(portfolioId string) cash_account.CashAccountQueryInterface {
+	return &cash_account.CashAccountQuery{MakeCashEventStream(portfolioId), MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId), MakeExchangeRateProvider(), BaseCurrency()}
+}
+
+func MakeSavingsPlansQuery(portfolioId string) savings_plans.SavingsPlansQueryInterface {
+	return &savings_plans.SavingsPlansQuery{MakeSavingsPlanEventStream(portfolioId)}
+}
+
+// REJECT_CASH_OVERDRAW=true rejects buys that cost more than the cash of the portfolio
+func RejectCashOverdraw() bool {
+	return os.Getenv("REJECT_CASH_OVERDRAW") == "true"
+}
+
+func makeCashRepository(portfolioId string) cash_persistence.CashRepository {
+	return makeCashRepositoryOf(MakeCashEventStream(portfolioId), MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId))
+}
+
+func makeCashRepositoryOf(cashEventStream infrastructure.EventStream, portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream) cash_persistence.CashRepository {
+	repository := cash_persistence.NewEventSourcedCashRepository(cashEventStream, portfolioEventStream, dividendEventStream)
+
+	return &repository
+}
+
 func MakePortfolioCommandHandler() command_handler.PortfolioCommandHandlerInterface {
-	eventStream := MakePortfolioEventStream()
-	publisher := event.NewEventPublisher(eventStream)
-	repository := persistence.NewEventSourcedPortfolioRepository(eventStream)
+	commandHandlers := map[string]command_handler.PortfolioCommandHandlerInterface{}
+	for _, portfolioId := range PortfolioIds() {
+		commandHandlers[portfolioId] = makePortfolioCommandHandler(MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId), MakeCashEventStream(portfolioId))
+	}
+
+	return command_handler.NewCommandRouter(commandHandlers)
+}
+
+func makePortfolioCommandHandler(portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream, cashEventStream infrastructure.EventStream) command_handler.PortfolioCommandHandlerInterface {
+	publisher := event.NewEventPublisher(portfolioEventStream)
+	repository := persistence.NewEventSourcedPortfolioRepository(portfolioEventStream)
+	if RejectCashOverdraw() {
+		return cash_command_handler.NewCashCoveredCommandHandler(&repository, publisher, makeCashRepositoryOf(cashEventStream, portfolioEventStream, dividendEventStream))
+	}
+
 	return command_handler.NewCommandHandler(&repository, publisher)
 }
 
 func MakeDividendCommandHandler() command_handler2.DividendCommandHandlerInterface {
-	dividendEventStream := MakeDividendEventStream()
-	portfolioEventStream := MakePortfolioEventStream()
+	commandHandlers := map[string]command_handler2.DividendCommandHandlerInterface{}
+	for _, portfolioId := range PortfolioIds() {
+		commandHandlers[portfolioId] = makeDividendCommandHandler(MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId))
+	}
+
+	return command_handler2.NewDividendCommandRouter(commandHandlers)
+}
+
+func makeDividendCommandHandler(portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream) command_handler2.DividendCommandHandlerInterface {
 	publisher := event.NewEventPublisher(dividendEventStream)
 	repository := persistence2.NewEventSourcedDividendRepository(portfolioEventStream)
-	return command_handler2.NewDividendCommandHandler(&repository, publisher)
+	portfolioRepository := persistence.NewEventSourcedPortfolioRepository(portfolioEventStream)
+
+	return command_handler2.NewDividendCommandHandler(&repository, publisher, &portfolioRepository, event.NewEventPublisher(portfolioEventStream))
+}
+
+// imported transactions go through the same command handlers as the api, dry runs through copies of the streams
+func MakeTransactionImporter(portfolioId string) transaction_import.Importer {
+	eventStreams := transaction_import.EventStreams{MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId), MakeCashEventStream(portfolioId)}
+
+	return transaction_import.NewImporter(eventStreams, func(eventStreams transaction_import.EventStreams) (command_handler.PortfolioCommandHandlerInterface, command_handler2.DividendCommandHandlerInterface) {
+		return makePortfolioCommandHandler(eventStreams.Portfolio, eventStreams.Dividend, eventStreams.Cash), makeDividendCommandHandler(eventStreams.Portfolio, eventStreams.Dividend)
+	})
+}
+
+func MakeCashCommandHandler() cash_command_handler.CashCommandHandlerInterface {
+	commandHandlers := map[string]cash_command_handler.CashCommandHandlerInterface{}
+	for _, portfolioId := range PortfolioIds() {
+		publisher := event.NewEventPublisher(MakeCashEventStream(portfolioId))
+		commandHandlers[portfolioId] = cash_command_handler.NewCashCommandHandler(makeCashRepository(portfolioId), publisher)
+	}
+
+	return cash_command_handler.NewCashCommandRouter(commandHandlers)
+}
+
+// executed orders are bought in the portfolio, pending orders are filled from the price store
+func MakeSavingsPlanCommandHandler() savings_plan_command_handler.SavingsPlanCommandHandlerInterface {
+	commandHandlers := map[string]savings_plan_command_handler.SavingsPlanCommandHandlerInterface{}
+	for _, portfolioId := range PortfolioIds() {
+		commandHandlers[portfolioId] = makeSavingsPlanCommandHandler(MakeSavingsPlanEventStream(portfolioId), MakePortfolioEventStream(portfolioId), MakeDividendEventStream(portfolioId), MakeCashEventStream(portfolioId))
+	}
+
+	return savings_plan_command_handler.NewSavingsPlanCommandRouter(commandHandlers)
+}
+
+// buys of executed orders pass the same cash check as the buys of the portfolio command handler
+func makeSavingsPlanCommandHandler(savingsPlanEventStream infrastructure.EventStream, portfolioEventStream infrastructure.EventStream, dividendEventStream infrastructure.EventStream, cashEventStream infrastructure.EventStream) savings_plan_command_handler.SavingsPlanCommandHandlerInterface {
+	repository := savings_plan_persistence.NewEventSourcedSavingsPlanRepository(savingsPlanEventStream)
+	portfolioRepository := persistence.NewEventSourcedPortfolioRepository(portfolioEventStream)
+	if RejectCashOverdraw() {
+		return savings_plan_command_handler.NewGuardedSavingsPlanCommandHandler(&repository, event.NewEventPublisher(savingsPlanEventStream), &portfolioRepository, event.NewEventPublisher(portfolioEventStream), MakePriceStore(), cash_command_handler.NewCashCoveredBuyGuard(makeCashRepositoryOf(cashEventStream, portfolioEventStream, dividendEventStream)))
+	}
+
+	return savings_plan_command_handler.NewSavingsPlanCommandHandler(&repository, event.NewEventPublisher(savingsPlanEventStream), &portfolioRepository, event.NewEventPublisher(portfolioEventStream), MakePriceStore())
 }
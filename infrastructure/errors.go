@@ -1,5 +1,7 @@
 package infrastructure
 
+import "strconv"
+
 type UnsupportedDateFormatError struct {
 	prob string
 }
@@ -8,6 +10,29 @@ type InvalidDateError struct {
 	prob string
 }
 
+type UnsupportedValueTypeError struct {
+	key string
+}
+
+type EventStreamNotEmptyError struct {
+	stream string
+}
+
+type ConcurrencyConflictError struct {
+	expected int
+	actual   int
+}
+
+type AtomicAppendNotSupportedError struct {
+	prob string
+}
+
+type CorruptEventStreamError struct {
+	stream string
+	line   int
+	err    error
+}
+
 func NewUnsupportedDateFormatError(prob string) *UnsupportedDateFormatError {
 	return &UnsupportedDateFormatError{prob: prob}
 }
@@ -16,6 +41,26 @@ func NewInvalidDateError(prob string) *InvalidDateError {
 	return &InvalidDateError{prob: prob}
 }
 
+func NewUnsupportedValueTypeError(key string) *UnsupportedValueTypeError {
+	return &UnsupportedValueTypeError{key: key}
+}
+
+func NewEventStreamNotEmptyError(stream string) *EventStreamNotEmptyError {
+	return &EventStreamNotEmptyError{stream: stream}
+}
+
+func NewConcurrencyConflictError(expected int, actual int) *ConcurrencyConflictError {
+	return &ConcurrencyConflictError{expected: expected, actual: actual}
+}
+
+func NewAtomicAppendNotSupportedError(prob string) *AtomicAppendNotSupportedError {
+	return &AtomicAppendNotSupportedError{prob: prob}
+}
+
+func NewCorruptEventStreamError(stream string, line int, err error) *CorruptEventStreamError {
+	return &CorruptEventStreamError{stream: stream, line: line, err: err}
+}
+
 func (e *UnsupportedDateFormatError) Error() string {
 	return e.prob
 }
@@ -23,3 +68,23 @@ func (e *UnsupportedDateFormatError) Error() string {
 func (e *InvalidDateError) Error() string {
 	return e.prob
 }
+
+func (e *UnsupportedValueTypeError) Error() string {
+	return "Unsupported value type can't be stored. Key: " + e.key
+}
+
+func (e *EventStreamNotEmptyError) Error() string {
+	return "Event stream already contains events. Stream: " + e.stream
+}
+
+func (e *ConcurrencyConflictError) Error() string {
+	return "event stream was modified concurrently. expected version: " + strconv.Itoa(e.expected) + " actual version: " + strconv.Itoa(e.actual)
+}
+
+func (e *AtomicAppendNotSupportedError) Error() string {
+	return e.prob
+}
+
+func (e *CorruptEventStreamError) Error() string {
+	return "Event stream can't be decoded. Stream: " + e.stream + " line: " + strconv.Itoa(e.line) + " error: " + e.err.Error()
+}
@@ -1,6 +1,7 @@
 package infrastructure_test
 
 import (
+	"errors"
 	"stock-monitor/infrastructure"
 	"testing"
 )
@@ -26,3 +27,58 @@ func TestInvalidDateError(t *testing.T) {
 		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
 	}
 }
+
+func TestUnsupportedValueTypeError(t *testing.T) {
+	err := infrastructure.NewUnsupportedValueTypeError("foo")
+
+	expected := "Unsupported value type can't be stored. Key: foo"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestEventStreamNotEmptyError(t *testing.T) {
+	err := infrastructure.NewEventStreamNotEmptyError("foo.jsonl")
+
+	expected := "Event stream already contains events. Stream: foo.jsonl"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestConcurrencyConflictError(t *testing.T) {
+	err := infrastructure.NewConcurrencyConflictError(2, 3)
+
+	expected := "event stream was modified concurrently. expected version: 2 actual version: 3"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestAtomicAppendNotSupportedError(t *testing.T) {
+	err := infrastructure.NewAtomicAppendNotSupportedError("Error text")
+
+	expected := "Error text"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestCorruptEventStreamError(t *testing.T) {
+	err := infrastructure.NewCorruptEventStreamError("foo.jsonl", 3, errors.New("Error text"))
+
+	expected := "Event stream can't be decoded. Stream: foo.jsonl line: 3 error: Error text"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
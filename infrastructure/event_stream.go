@@ -1,8 +1,8 @@
 package infrastructure
 
 import (
-	"encoding/gob"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -12,9 +12,13 @@ type Event struct {
 	MetaData map[string]interface{}
 }
 
+const AnyVersion = -1
+
 type EventStream interface {
 	Add(event Event) error
-	Get() []Event
+	AppendBatch(events []Event, expectedVersion int) error
+	Get() ([]Event, error)
+	Version() (int, error)
 }
 
 type InMemoryEventStream struct {
@@ -22,29 +26,54 @@ type InMemoryEventStream struct {
 }
 
 func (eventStream *InMemoryEventStream) Add(event Event) error {
-	occurredAt, ok := event.MetaData["occurred_at"].(string)
-	if !ok || !commandDateHasValidFormat(occurredAt) {
-		return NewUnsupportedDateFormatError("Unsupported date time format. Must be YYYY-MM-DD. Got: " + occurredAt)
-	}
-	if !occurredAtIsInThePast(occurredAt) {
-		return NewInvalidDateError("OccurredAt can't be in the future. Got: " + occurredAt)
-	}
+	return eventStream.AppendBatch([]Event{event}, AnyVersion)
+}
 
-	if len(eventStream.Events) > 0 {
-		lastOccurredAt := eventStream.Events[len(eventStream.Events)-1].MetaData["occurred_at"].(string)
+func (eventStream *InMemoryEventStream) AppendBatch(events []Event, expectedVersion int) error {
+	lock := eventStream.lock()
+	lock.acquire()
+	defer lock.release()
 
-		if !occurredAtIsLaterThanLastOccurredAt(occurredAt, lastOccurredAt) {
-			return NewInvalidDateError("OccurredAt can't be older than occurredAt of last event. Got: " + occurredAt)
-		}
+	if expectedVersion != AnyVersion && expectedVersion != len(eventStream.Events) {
+		return NewConcurrencyConflictError(expectedVersion, len(eventStream.Events))
 	}
 
-	eventStream.Events = append(eventStream.Events, event)
+	err := validateBatch(events, lastOccurredAt(eventStream.Events))
+	if err != nil {
+		return err
+	}
+
+	eventStream.Events = append(eventStream.Events, events...)
 
 	return nil
 }
 
-func (eventStream *InMemoryEventStream) Get() []Event {
-	return eventStream.Events
+func (eventStream *InMemoryEventStream) lock() *streamLock {
+	return lockOfMemory(eventStream)
+}
+
+func (eventStream *InMemoryEventStream) state() (int, string, error) {
+	return len(eventStream.Events), lastOccurredAt(eventStream.Events), nil
+}
+
+func (eventStream *InMemoryEventStream) stage(events []Event) (stagedAppend, error) {
+	return &stagedInMemoryEvents{eventStream, events, len(eventStream.Events)}, nil
+}
+
+func (eventStream *InMemoryEventStream) Get() ([]Event, error) {
+	lock := eventStream.lock()
+	lock.acquire()
+	defer lock.release()
+
+	return eventStream.Events, nil
+}
+
+func (eventStream *InMemoryEventStream) Version() (int, error) {
+	lock := eventStream.lock()
+	lock.acquire()
+	defer lock.release()
+
+	return len(eventStream.Events), nil
 }
 
 type FileSystemEventStream struct {
@@ -53,28 +82,34 @@ type FileSystemEventStream struct {
 }
 
 func (eventStream *FileSystemEventStream) Add(event Event) error {
-	occurredAt, ok := event.MetaData["occurred_at"].(string)
-	if !ok || !commandDateHasValidFormat(occurredAt) {
-		return NewUnsupportedDateFormatError("Unsupported date time format. Must be YYYY-MM-DD. Got: " + occurredAt)
-	}
-	if !occurredAtIsInThePast(occurredAt) {
-		return NewInvalidDateError("OccurredAt can't be in the future. Got: " + occurredAt)
+	return eventStream.AppendBatch([]Event{event}, AnyVersion)
+}
+
+func (eventStream *FileSystemEventStream) AppendBatch(newEvents []Event, expectedVersion int) error {
+	lock := eventStream.lock()
+	err := lock.acquire()
+	if err != nil {
+		return err
 	}
+	defer lock.release()
 
-	events := []Event{}
-	read(eventStream.StoragePath+eventStream.FileName, &events)
+	events, err := readEvents(eventStream.StoragePath + eventStream.FileName)
+	if err != nil {
+		return err
+	}
 
-	if len(events) > 0 {
-		lastOccurredAt := events[len(events)-1].MetaData["occurred_at"].(string)
+	if expectedVersion != AnyVersion && expectedVersion != len(events) {
+		return NewConcurrencyConflictError(expectedVersion, len(events))
+	}
 
-		if !occurredAtIsLaterThanLastOccurredAt(occurredAt, lastOccurredAt) {
-			return NewInvalidDateError("OccurredAt can't be older than occurredAt of last event. Got: " + occurredAt)
-		}
+	err = validateBatch(newEvents, lastOccurredAt(events))
+	if err != nil {
+		return err
 	}
 
-	events = append(events, event)
+	events = append(events, newEvents...)
 
-	err := write(eventStream.StoragePath+eventStream.FileName, events)
+	err = write(eventStream.StoragePath+eventStream.FileName, events)
 	if err != nil {
 		return err
 	}
@@ -82,31 +117,155 @@ func (eventStream *FileSystemEventStream) Add(event Event) error {
 	return nil
 }
 
-func (eventStream *FileSystemEventStream) Get() []Event {
-	storedEvents := []Event{}
-	read(eventStream.StoragePath+eventStream.FileName, &storedEvents)
+func (eventStream *FileSystemEventStream) lock() *streamLock {
+	return lockOfFile(eventStream.StoragePath + eventStream.FileName)
+}
+
+func (eventStream *FileSystemEventStream) state() (int, string, error) {
+	events, err := readEvents(eventStream.StoragePath + eventStream.FileName)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return len(events), lastOccurredAt(events), nil
+}
+
+func (eventStream *FileSystemEventStream) stage(newEvents []Event) (stagedAppend, error) {
+	filePath := eventStream.StoragePath + eventStream.FileName
+	previous, existed, err := readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := decodeEvents(previous)
+	if err != nil {
+		return nil, err
+	}
+	data, err := encodeGob(append(events, newEvents...))
+	if err != nil {
+		return nil, err
+	}
+
+	return stageFile(filePath, previous, existed, data)
+}
+
+func (eventStream *FileSystemEventStream) Get() ([]Event, error) {
+	lock := eventStream.lock()
+	err := lock.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	return readEvents(eventStream.StoragePath + eventStream.FileName)
+}
+
+func (eventStream *FileSystemEventStream) Version() (int, error) {
+	events, err := eventStream.Get()
 
-	return storedEvents
+	return len(events), err
 }
 
 func write(filePath string, object interface{}) error {
-	file, err := os.Create(filePath)
-	if err == nil {
-		encoder := gob.NewEncoder(file)
-		encoder.Encode(object)
+	data, err := encodeGob(object)
+	if err != nil {
+		return err
 	}
-	file.Close()
-	return err
+
+	return WriteAtomically(filePath, data)
 }
 
-func read(filePath string, object interface{}) error {
-	file, err := os.OpenFile(filePath, os.O_RDONLY|os.O_CREATE, 0644)
+// WriteAtomically replaces the file with the data, readers see either the old or the complete new content
+func WriteAtomically(filePath string, data []byte) error {
+	tempPath, err := writeTemporarily(filePath, data)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempPath)
+
+	// renaming is atomic, readers either see the old or the complete new stream
+	return os.Rename(tempPath, filePath)
+}
+
+// writeTemporarily writes the data to a temporary file next to the given one and returns its path
+func writeTemporarily(filePath string, data []byte) (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Write(data)
+	closeErr := file.Close()
 	if err == nil {
-		decoder := gob.NewDecoder(file)
-		err = decoder.Decode(object)
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// readEvents reads the gob file of an event stream, a missing or empty file is an empty stream
+func readEvents(filePath string) ([]Event, error) {
+	data, _, err := readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeEvents(data)
+}
+
+func decodeEvents(data []byte) ([]Event, error) {
+	events := []Event{}
+	if len(data) == 0 {
+		return events, nil
+	}
+
+	err := decodeGob(data, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func validateEvent(event Event, lastOccurredAt string) error {
+	occurredAt, ok := event.MetaData["occurred_at"].(string)
+	if !ok || !commandDateHasValidFormat(occurredAt) {
+		return NewUnsupportedDateFormatError("Unsupported date time format. Must be YYYY-MM-DD. Got: " + occurredAt)
+	}
+	if !occurredAtIsInThePast(occurredAt) {
+		return NewInvalidDateError("OccurredAt can't be in the future. Got: " + occurredAt)
 	}
-	file.Close()
-	return err
+	if lastOccurredAt != "" && !occurredAtIsLaterThanLastOccurredAt(occurredAt, lastOccurredAt) {
+		return NewInvalidDateError("OccurredAt can't be older than occurredAt of last event. Got: " + occurredAt)
+	}
+
+	return nil
+}
+
+func validateBatch(events []Event, lastOccurredAt string) error {
+	for _, event := range events {
+		err := validateEvent(event, lastOccurredAt)
+		if err != nil {
+			return err
+		}
+		lastOccurredAt = event.MetaData["occurred_at"].(string)
+	}
+
+	return nil
+}
+
+func lastOccurredAt(events []Event) string {
+	if len(events) == 0 {
+		return ""
+	}
+
+	occurredAt, _ := events[len(events)-1].MetaData["occurred_at"].(string)
+
+	return occurredAt
 }
 
 func commandDateHasValidFormat(date string) bool {
@@ -1,6 +1,7 @@
 package infrastructure_test
 
 import (
+	"database/sql"
 	"os"
 	"reflect"
 	"stock-monitor/infrastructure"
@@ -10,14 +11,21 @@ import (
 
 var tmpStorePath = "./tmp/"
 var tmpStoreFile = "test_events.gob"
+var tmpDatabaseFile = "test_events.db"
+var tmpJsonLinesFile = "test_events.jsonl"
+var tmpDatabase *sql.DB
 
 func TestCanNotAddEventsWithInvalidOccurredAtDateFormat(t *testing.T) {
 	inMemoryEventStream := infrastructure.InMemoryEventStream{}
 	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
 
 	eventStreams := map[string]infrastructure.EventStream{
 		"InMemoryEventStream":   &inMemoryEventStream,
 		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
 	}
 
 	for name, eventStream := range eventStreams {
@@ -40,6 +48,8 @@ func TestCanNotAddEventsWithInvalidOccurredAtDateFormat(t *testing.T) {
 	}
 
 	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
 		cleanUpFileSystemEventStream()
 	})
 }
@@ -47,10 +57,14 @@ func TestCanNotAddEventsWithInvalidOccurredAtDateFormat(t *testing.T) {
 func TestCanNotAddEventsWithoutOccurredAt(t *testing.T) {
 	inMemoryEventStream := infrastructure.InMemoryEventStream{}
 	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
 
 	eventStreams := map[string]infrastructure.EventStream{
 		"InMemoryEventStream":   &inMemoryEventStream,
 		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
 	}
 	for name, eventStream := range eventStreams {
 		t.Run(name, func(t *testing.T) {
@@ -70,6 +84,8 @@ func TestCanNotAddEventsWithoutOccurredAt(t *testing.T) {
 	}
 
 	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
 		cleanUpFileSystemEventStream()
 	})
 }
@@ -77,11 +93,15 @@ func TestCanNotAddEventsWithoutOccurredAt(t *testing.T) {
 func TestOccurredAtCanNotBeInTheFuture(t *testing.T) {
 	inMemoryEventStream := infrastructure.InMemoryEventStream{}
 	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
 	today := time.Now()
 
 	eventStreams := map[string]infrastructure.EventStream{
 		"InMemoryEventStream":   &inMemoryEventStream,
 		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
 	}
 	for name, eventStream := range eventStreams {
 		t.Run(name, func(t *testing.T) {
@@ -103,6 +123,8 @@ func TestOccurredAtCanNotBeInTheFuture(t *testing.T) {
 	}
 
 	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
 		cleanUpFileSystemEventStream()
 	})
 }
@@ -110,10 +132,14 @@ func TestOccurredAtCanNotBeInTheFuture(t *testing.T) {
 func TestOccurredAtCanNotBeOlderThanOccurredAtOfLastEvent(t *testing.T) {
 	inMemoryEventStream := infrastructure.InMemoryEventStream{}
 	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
 
 	eventStreams := map[string]infrastructure.EventStream{
 		"InMemoryEventStream":   &inMemoryEventStream,
 		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
 	}
 	for name, eventStream := range eventStreams {
 		t.Run(name, func(t *testing.T) {
@@ -144,6 +170,8 @@ func TestOccurredAtCanNotBeOlderThanOccurredAtOfLastEvent(t *testing.T) {
 	}
 
 	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
 		cleanUpFileSystemEventStream()
 	})
 }
@@ -151,10 +179,14 @@ func TestOccurredAtCanNotBeOlderThanOccurredAtOfLastEvent(t *testing.T) {
 func TestAddEvents(t *testing.T) {
 	inMemoryEventStream := infrastructure.InMemoryEventStream{}
 	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
 
 	eventStreams := map[string]infrastructure.EventStream{
 		"InMemoryEventStream":   &inMemoryEventStream,
 		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
 	}
 
 	for name, eventStream := range eventStreams {
@@ -179,7 +211,7 @@ func TestAddEvents(t *testing.T) {
 				},
 			})
 
-			got := eventStream.Get()
+			got, _ := eventStream.Get()
 			want := []infrastructure.Event{
 				{
 					"EventName", map[string]interface{}{
@@ -207,6 +239,8 @@ func TestAddEvents(t *testing.T) {
 	}
 
 	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
 		cleanUpFileSystemEventStream()
 	})
 }
@@ -218,5 +252,462 @@ func setUpFileSystemEventStream() infrastructure.FileSystemEventStream {
 
 func cleanUpFileSystemEventStream() {
 	os.Remove(tmpStorePath + tmpStoreFile)
+	os.Remove(tmpStorePath + tmpStoreFile + ".lock")
 	os.Remove(tmpStorePath)
 }
+
+func setUpSqliteEventStream() *infrastructure.SqliteEventStream {
+	os.Mkdir(tmpStorePath, 0777)
+	tmpDatabase, _ = infrastructure.OpenSqliteDatabase(tmpStorePath + tmpDatabaseFile)
+	return infrastructure.NewSqliteEventStream(tmpDatabase, "test")
+}
+
+func cleanUpSqliteEventStream() {
+	tmpDatabase.Close()
+	os.Remove(tmpStorePath + tmpDatabaseFile)
+}
+
+func TestVersionIsTheNumberOfEventsInTheStream(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+
+	eventStreams := map[string]infrastructure.EventStream{
+		"InMemoryEventStream":   &inMemoryEventStream,
+		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
+	}
+
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			version, _ := eventStream.Version()
+			if version != 0 {
+				t.Errorf("Unexpected version of empty stream. Expected:%#v Got:%#v", 0, version)
+			}
+
+			eventStream.Add(infrastructure.Event{
+				"EventName",
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			})
+			eventStream.Add(infrastructure.Event{
+				"EventName",
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			})
+
+			version, _ = eventStream.Version()
+			if version != 2 {
+				t.Errorf("Unexpected version. Expected:%#v Got:%#v", 2, version)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestEventsCanBeAddedAtTheExpectedVersion(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+
+	eventStreams := map[string]infrastructure.EventStream{
+		"InMemoryEventStream":   &inMemoryEventStream,
+		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
+	}
+
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			err := eventStream.AppendBatch([]infrastructure.Event{{
+				"EventName",
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			}}, 0)
+			if err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+			}
+
+			err = eventStream.AppendBatch([]infrastructure.Event{{
+				"EventName",
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			}}, 1)
+			if err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestEventsCanNotBeAddedWhenStreamWasModifiedConcurrently(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+
+	eventStreams := map[string]infrastructure.EventStream{
+		"InMemoryEventStream":   &inMemoryEventStream,
+		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
+	}
+
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			eventStream.Add(infrastructure.Event{
+				"EventName",
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			})
+			err := eventStream.AppendBatch([]infrastructure.Event{{
+				"EventName",
+				map[string]interface{}{"foo": "bar"},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			}}, 0)
+
+			_, ok := err.(*infrastructure.ConcurrencyConflictError)
+			if !ok {
+				t.Errorf("Expected ConcurrencyConflictError but got %#v", err)
+			}
+			version, _ := eventStream.Version()
+			if version != 1 {
+				t.Errorf("Unexpected version. Expected:%#v Got:%#v", 1, version)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestBatchIsAppendedCompletely(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+
+	eventStreams := map[string]infrastructure.EventStream{
+		"InMemoryEventStream":   &inMemoryEventStream,
+		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
+	}
+
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			err := eventStream.AppendBatch([]infrastructure.Event{
+				{
+					"EventName",
+					map[string]interface{}{"foo": "bar"},
+					map[string]interface{}{"occurred_at": "2000-01-01"},
+				},
+				{
+					"EventName2",
+					map[string]interface{}{"foo": "buz"},
+					map[string]interface{}{"occurred_at": "2000-01-02"},
+				},
+			}, infrastructure.AnyVersion)
+
+			got, _ := eventStream.Get()
+			want := []infrastructure.Event{
+				{
+					"EventName",
+					map[string]interface{}{"foo": "bar"},
+					map[string]interface{}{"occurred_at": "2000-01-01"},
+				},
+				{
+					"EventName2",
+					map[string]interface{}{"foo": "buz"},
+					map[string]interface{}{"occurred_at": "2000-01-02"},
+				},
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+			}
+			if reflect.DeepEqual(got, want) == false {
+				t.Errorf("Event store state unequal. Expected:%#v Got:%#v", want, got)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestNoEventOfABatchIsAppendedWhenOneEventIsInvalid(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+
+	eventStreams := map[string]infrastructure.EventStream{
+		"InMemoryEventStream":   &inMemoryEventStream,
+		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
+	}
+
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			err := eventStream.AppendBatch([]infrastructure.Event{
+				{
+					"EventName",
+					map[string]interface{}{"foo": "bar"},
+					map[string]interface{}{"occurred_at": "2000-01-02"},
+				},
+				{
+					"EventName",
+					map[string]interface{}{"foo": "bar"},
+					map[string]interface{}{"occurred_at": "2000-01-02"},
+				},
+				{
+					"EventName",
+					map[string]interface{}{"foo": "bar"},
+					map[string]interface{}{"occurred_at": "2000-01-01"},
+				},
+			}, infrastructure.AnyVersion)
+
+			_, ok := err.(*infrastructure.InvalidDateError)
+			if !ok {
+				t.Errorf("Expected InvalidDateError but got %#v", err)
+			}
+			events, _ := eventStream.Get()
+			if len(events) != 0 {
+				t.Errorf("Expected no events to be appended but got %#v", events)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func setUpJsonLinesEventStream() infrastructure.JsonLinesEventStream {
+	os.Mkdir(tmpStorePath, 0777)
+	return infrastructure.JsonLinesEventStream{tmpStorePath, tmpJsonLinesFile}
+}
+
+func cleanUpJsonLinesEventStream() {
+	os.Remove(tmpStorePath + tmpJsonLinesFile)
+	os.Remove(tmpStorePath + tmpJsonLinesFile + ".lock")
+}
+
+func TestBatchesAreAppendedAtomically(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+
+	err := infrastructure.AppendAtomically([]infrastructure.StreamBatch{
+		{&dividendEventStream, []infrastructure.Event{{"EventName", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, infrastructure.AnyVersion},
+		{&portfolioEventStream, []infrastructure.Event{{"EventName2", map[string]interface{}{"foo": "buz"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, 0},
+	})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if len(dividendEventStream.Events) != 1 || len(portfolioEventStream.Events) != 1 {
+		t.Errorf("Expected one event in each stream but got %#v and %#v", dividendEventStream.Events, portfolioEventStream.Events)
+	}
+}
+
+func TestNoBatchIsAppendedWhenOneOfThemConflicts(t *testing.T) {
+	portfolioEventStream := infrastructure.InMemoryEventStream{}
+	dividendEventStream := infrastructure.InMemoryEventStream{}
+
+	err := infrastructure.AppendAtomically([]infrastructure.StreamBatch{
+		{&dividendEventStream, []infrastructure.Event{{"EventName", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, infrastructure.AnyVersion},
+		{&portfolioEventStream, []infrastructure.Event{{"EventName2", map[string]interface{}{"foo": "buz"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, 1},
+	})
+
+	_, ok := err.(*infrastructure.ConcurrencyConflictError)
+	if !ok {
+		t.Errorf("Expected ConcurrencyConflictError but got %#v", err)
+	}
+	if len(dividendEventStream.Events) != 0 || len(portfolioEventStream.Events) != 0 {
+		t.Errorf("Expected no events to be appended but got %#v and %#v", dividendEventStream.Events, portfolioEventStream.Events)
+	}
+}
+
+func TestNoBatchIsAppendedWhenItConflictsWithAnEarlierBatch(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	fileSystemEventStream := setUpFileSystemEventStream()
+	sqliteEventStream := setUpSqliteEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+
+	eventStreams := map[string]infrastructure.EventStream{
+		"InMemoryEventStream":   &inMemoryEventStream,
+		"FileSystemEventStream": &fileSystemEventStream,
+		"SqliteEventStream":     sqliteEventStream,
+		"JsonLinesEventStream":  &jsonLinesEventStream,
+	}
+
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			eventStream.Add(infrastructure.Event{"EventName", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"occurred_at": "2000-01-01"}})
+
+			// the second batch expects the version the stream had before the first one
+			err := infrastructure.AppendAtomically([]infrastructure.StreamBatch{
+				{eventStream, []infrastructure.Event{{"EventName2", map[string]interface{}{"foo": "buz"}, map[string]interface{}{"occurred_at": "2000-01-02"}}}, 1},
+				{eventStream, []infrastructure.Event{{"EventName3", map[string]interface{}{"foo": "baz"}, map[string]interface{}{"occurred_at": "2000-01-03"}}}, 1},
+			})
+
+			got, _ := eventStream.Get()
+			want := []infrastructure.Event{
+				{"EventName", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"occurred_at": "2000-01-01"}},
+			}
+
+			_, ok := err.(*infrastructure.ConcurrencyConflictError)
+			if !ok {
+				t.Errorf("Expected ConcurrencyConflictError but got %#v", err)
+			}
+			if reflect.DeepEqual(got, want) == false {
+				t.Errorf("Event store state unequal. Expected:%#v Got:%#v", want, got)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestConcurrentAppendsAreKeptWhenALaterBatchFails(t *testing.T) {
+	fileSystemEventStream := setUpFileSystemEventStream()
+	otherFileSystemEventStream := infrastructure.FileSystemEventStream{tmpStorePath, "other_" + tmpStoreFile}
+	sqliteEventStream := setUpSqliteEventStream()
+	otherSqliteEventStream := infrastructure.NewSqliteEventStream(tmpDatabase, "other")
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+	otherJsonLinesEventStream := infrastructure.JsonLinesEventStream{tmpStorePath, "other_" + tmpJsonLinesFile}
+
+	eventStreams := map[string][]infrastructure.EventStream{
+		"FileSystemEventStream": {&fileSystemEventStream, &otherFileSystemEventStream},
+		"SqliteEventStream":     {sqliteEventStream, otherSqliteEventStream},
+		"JsonLinesEventStream":  {&jsonLinesEventStream, &otherJsonLinesEventStream},
+	}
+
+	for name, streams := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			concurrentAppends := 20
+			done := make(chan bool)
+			go func() {
+				for i := 0; i < concurrentAppends; i++ {
+					streams[0].Add(infrastructure.Event{"ConcurrentEvent", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"occurred_at": "2000-01-01"}})
+				}
+				done <- true
+			}()
+
+			// the second batch can't be encoded, so it fails after the first one was prepared
+			for i := 0; i < concurrentAppends; i++ {
+				err := infrastructure.AppendAtomically([]infrastructure.StreamBatch{
+					{streams[0], []infrastructure.Event{{"EventName", map[string]interface{}{"foo": "buz"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, infrastructure.AnyVersion},
+					{streams[1], []infrastructure.Event{{"EventName2", map[string]interface{}{"foo": struct{}{}}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, infrastructure.AnyVersion},
+				})
+				if err == nil {
+					t.Errorf("Expected an error for the second batch")
+				}
+			}
+			<-done
+
+			got, _ := streams[0].Get()
+			if len(got) != concurrentAppends {
+				t.Errorf("Expected %d concurrently appended events but got %#v", concurrentAppends, got)
+			}
+			for _, event := range got {
+				if event.Name != "ConcurrentEvent" {
+					t.Errorf("Expected only concurrently appended events but got %#v", event)
+				}
+			}
+			other, _ := streams[1].Get()
+			if len(other) != 0 {
+				t.Errorf("Expected no events in the second stream but got %#v", other)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpJsonLinesEventStream()
+		os.Remove(tmpStorePath + "other_" + tmpJsonLinesFile)
+		os.Remove(tmpStorePath + "other_" + tmpJsonLinesFile + ".lock")
+		os.Remove(tmpStorePath + "other_" + tmpStoreFile)
+		os.Remove(tmpStorePath + "other_" + tmpStoreFile + ".lock")
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestSqliteEventStreamsCanNotBeAppendedAtomicallyWithOtherEventStreams(t *testing.T) {
+	inMemoryEventStream := infrastructure.InMemoryEventStream{}
+	sqliteEventStream := setUpSqliteEventStream()
+
+	err := infrastructure.AppendAtomically([]infrastructure.StreamBatch{
+		{&inMemoryEventStream, []infrastructure.Event{{"EventName", map[string]interface{}{"foo": "bar"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, infrastructure.AnyVersion},
+		{sqliteEventStream, []infrastructure.Event{{"EventName2", map[string]interface{}{"foo": "buz"}, map[string]interface{}{"occurred_at": "2000-01-01"}}}, infrastructure.AnyVersion},
+	})
+
+	_, ok := err.(*infrastructure.AtomicAppendNotSupportedError)
+	if !ok {
+		t.Errorf("Expected AtomicAppendNotSupportedError but got %#v", err)
+	}
+	version, _ := sqliteEventStream.Version()
+	if len(inMemoryEventStream.Events) != 0 || version != 0 {
+		t.Errorf("Expected no events to be appended")
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestFileSystemEventStreamFailsOnAFileThatCanNotBeDecoded(t *testing.T) {
+	eventStream := setUpFileSystemEventStream()
+	os.WriteFile(tmpStorePath+tmpStoreFile, []byte("not a gob file"), 0644)
+
+	_, err := eventStream.Get()
+
+	if err == nil {
+		t.Errorf("Expected an error from Get")
+	}
+
+	_, err = eventStream.Version()
+
+	if err == nil {
+		t.Errorf("Expected an error from Version")
+	}
+
+	err = eventStream.Add(infrastructure.Event{"EventName", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}})
+
+	if err == nil {
+		t.Errorf("Expected an error from Add")
+	}
+
+	t.Cleanup(func() {
+		cleanUpFileSystemEventStream()
+	})
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package infrastructure
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile blocks until the process holds the exclusive lock of the file
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
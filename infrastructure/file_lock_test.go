@@ -0,0 +1,88 @@
+//go:build !windows
+// +build !windows
+
+package infrastructure_test
+
+import (
+	"os"
+	"stock-monitor/infrastructure"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// holdLock takes the lock file of a stream like another process would, through its own file description
+func holdLock(t *testing.T, filePath string) func() {
+	file, err := os.OpenFile(filePath+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}
+}
+
+func TestFileEventStreamsWaitForTheLockOfOtherProcesses(t *testing.T) {
+	fileSystemEventStream := setUpFileSystemEventStream()
+	jsonLinesEventStream := setUpJsonLinesEventStream()
+	event := infrastructure.Event{"Event", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}}
+
+	eventStreams := map[string]struct {
+		eventStream infrastructure.EventStream
+		filePath    string
+	}{
+		"file system": {&fileSystemEventStream, tmpStorePath + tmpStoreFile},
+		"json lines":  {&jsonLinesEventStream, tmpStorePath + tmpJsonLinesFile},
+	}
+	for name, eventStream := range eventStreams {
+		t.Run(name, func(t *testing.T) {
+			release := holdLock(t, eventStream.filePath)
+			added := make(chan error)
+			go func() {
+				added <- eventStream.eventStream.Add(event)
+			}()
+
+			select {
+			case <-added:
+				t.Fatalf("Expected the append to wait for the lock")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			release()
+			if err := <-added; err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+			}
+			if version, _ := eventStream.eventStream.Version(); version != 1 {
+				t.Errorf("Expected the event to be appended but got version %#v", version)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestFileEventStreamsDoNotWaitForTheLocksOfOtherStreams(t *testing.T) {
+	fileSystemEventStream := setUpFileSystemEventStream()
+	release := holdLock(t, tmpStorePath+tmpJsonLinesFile)
+	defer release()
+
+	err := fileSystemEventStream.Add(infrastructure.Event{"Event", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-01"}})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
@@ -0,0 +1,12 @@
+package infrastructure
+
+import "os"
+
+// lockFile is a no-op on windows, writers of other processes are not excluded there
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	dividend_command "stock-monitor/application/dividend/command"
 	dividend_command_handler "stock-monitor/application/dividend/command_handler"
 	"stock-monitor/application/shared"
+	"stock-monitor/domain"
 )
 
 type AddDividendsHandler struct {
@@ -13,14 +14,15 @@ type AddDividendsHandler struct {
 }
 
 type Dividend struct {
-	Ticker string  `json:"ticker"`
-	Net    float32 `json:"net"`
-	Gross  float32 `json:"gross"`
-	Date   string  `json:"date"`
+	Ticker string       `json:"ticker"`
+	Net    domain.Money `json:"net"`
+	Gross  domain.Money `json:"gross"`
+	Date   string       `json:"date"`
 }
 
 type Dividends struct {
-	Dividends []Dividend `json:"dividends"`
+	PortfolioId string     `json:"portfolio_id"`
+	Dividends   []Dividend `json:"dividends"`
 }
 
 func (handler *AddDividendsHandler) AddDividends(c echo.Context) error {
@@ -31,10 +33,13 @@ func (handler *AddDividendsHandler) AddDividends(c echo.Context) error {
 	}
 
 	for _, dividend := range dividends.Dividends {
-		recordDividendCommand := dividend_command.NewRecordDividendCommand(dividend.Ticker, dividend.Net, dividend.Gross, shared.CommandDate(dividend.Date))
+		recordDividendCommand := dividend_command.NewRecordDividendCommand(shared.PortfolioId(dividends.PortfolioId), dividend.Ticker, dividend.Net, dividend.Gross, shared.CommandDate(dividend.Date))
 
 		err := handler.CommandHandler.HandleRecordDividend(recordDividendCommand)
 
+		if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+			return c.String(http.StatusNotFound, err.Error())
+		}
 		if err != nil {
 			return c.String(http.StatusUnprocessableEntity, err.Error())
 		}
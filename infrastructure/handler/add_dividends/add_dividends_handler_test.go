@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"stock-monitor/application/dividend/command"
+	"stock-monitor/application/shared"
 	"stock-monitor/infrastructure/handler/add_dividends"
 	"strings"
 	"testing"
@@ -21,6 +22,10 @@ func (mockDividendCommandHandler *mockDividendCommandHandler) HandleRecordDivide
 	return mockDividendCommandHandler.expectedError
 }
 
+func (mockDividendCommandHandler *mockDividendCommandHandler) HandleReinvestDividend(command command.ReinvestDividendCommand) error {
+	return mockDividendCommandHandler.expectedError
+}
+
 func (mockDividendCommandHandler *mockDividendCommandHandler) expectError(err error) {
 	mockDividendCommandHandler.expectedError = err
 }
@@ -61,6 +66,27 @@ func TestAddDividend(t *testing.T) {
 		}
 	})
 
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockDividendCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"portfolio_id\":\"foo\",\"dividends\":[{\"ticker\":\"FOO\"}]}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := add_dividends.AddDividendsHandler{&mock}
+		handler.AddDividends(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+		if mock.recordDividendCommand.PortfolioId != "foo" {
+			t.Errorf("Unexpected portfolio id. Expected:%#v Got:%#v", "foo", mock.recordDividendCommand.PortfolioId)
+		}
+	})
+
 	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
 		mock := mockDividendCommandHandler{}
 
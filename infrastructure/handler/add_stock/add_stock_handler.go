@@ -6,6 +6,8 @@ import (
 	"stock-monitor/application/portfolio/command"
 	"stock-monitor/application/portfolio/command_handler"
 	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
 )
 
 type AddStockHandler struct {
@@ -13,10 +15,13 @@ type AddStockHandler struct {
 }
 
 type BuyOrder struct {
-	Ticker string  `json:"ticker"`
-	Shares int     `json:"shares"`
-	Price  float32 `json:"price"`
-	Date   string  `json:"date"`
+	PortfolioId string          `json:"portfolio_id"`
+	Ticker      string          `json:"ticker"`
+	Shares      domain.Quantity `json:"shares"`
+	Price       domain.Money    `json:"price"`
+	Fee         domain.Money    `json:"fee"`
+	Taxes       domain.Money    `json:"taxes"`
+	Date        string          `json:"date"`
 }
 
 func (handler *AddStockHandler) AddStock(c echo.Context) error {
@@ -25,10 +30,16 @@ func (handler *AddStockHandler) AddStock(c echo.Context) error {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	addSharesCommand := command.NewAddSharesToPortfolioCommand(buyOrder.Ticker, buyOrder.Shares, buyOrder.Price, shared.CommandDate(buyOrder.Date))
+	addSharesCommand := command.NewAddSharesToPortfolioCommand(shared.PortfolioId(buyOrder.PortfolioId), buyOrder.Ticker, buyOrder.Shares, buyOrder.Price, buyOrder.Fee, buyOrder.Taxes, shared.CommandDate(buyOrder.Date))
 
 	err := handler.CommandHandler.HandleAddSharesToPortfolio(addSharesCommand)
 
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return c.String(http.StatusUnprocessableEntity, err.Error())
 	}
@@ -5,7 +5,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
 	"stock-monitor/infrastructure/handler/add_stock"
 	"strings"
 	"testing"
@@ -29,6 +33,18 @@ func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleRenameTick
 	return mockPortfolioCommandHandler.expectedError
 }
 
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSplitStock(command command.SplitStockCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleConvertShares(command command.ConvertSharesCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSpinOff(command command.SpinOffCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
 func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) expectError(err error) {
 	mockPortfolioCommandHandler.expectedError = err
 }
@@ -51,6 +67,43 @@ func TestAddStock(t *testing.T) {
 		}
 	})
 
+	t.Run("it accepts fractional shares", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"ticker\":\"MO\",\"shares\":0.4213,\"price\":9.99,\"date\":\"2000-01-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := add_stock.AddStockHandler{&mock}
+		handler.AddStock(c)
+
+		shares, _ := domain.NewQuantity("0.4213")
+		expected := command.AddSharesToPortfolioCommand{"default", "MO", shares, domain.NewMoneyFromFloat(9.99, "EUR"), domain.Money{}, domain.Money{}, "2000-01-01"}
+		if reflect.DeepEqual(mock.addSharesCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.addSharesCommand)
+		}
+	})
+
+	t.Run("it accepts prices in other currencies", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"ticker\":\"MO\",\"shares\":10,\"price\":{\"amount\":9.99,\"currency\":\"USD\"},\"fee\":{\"amount\":1,\"currency\":\"USD\"},\"date\":\"2000-01-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := add_stock.AddStockHandler{&mock}
+		handler.AddStock(c)
+
+		expected := command.AddSharesToPortfolioCommand{"default", "MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(9.99, "USD"), domain.NewMoneyFromFloat(1, "USD"), domain.Money{}, "2000-01-01"}
+		if reflect.DeepEqual(mock.addSharesCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.addSharesCommand)
+		}
+	})
+
 	t.Run("it fails with 422 when command failed", func(t *testing.T) {
 		mock := mockPortfolioCommandHandler{}
 		mock.expectError(errors.New("some error happened"))
@@ -69,6 +122,42 @@ func TestAddStock(t *testing.T) {
 		}
 	})
 
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := add_stock.AddStockHandler{&mock}
+		handler.AddStock(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := add_stock.AddStockHandler{&mock}
+		handler.AddStock(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
 	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
 		mock := mockPortfolioCommandHandler{}
 
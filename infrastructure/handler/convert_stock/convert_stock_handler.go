@@ -0,0 +1,48 @@
+package convert_stock
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/portfolio/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type ConvertStockHandler struct {
+	CommandHandler command_handler.PortfolioCommandHandlerInterface
+}
+
+type ConversionOrder struct {
+	PortfolioId string       `json:"portfolio_id"`
+	Ticker      string       `json:"ticker"`
+	NewTicker   string       `json:"new_ticker"`
+	RatioFrom   int          `json:"ratio_from"`
+	RatioTo     int          `json:"ratio_to"`
+	CashInLieu  domain.Money `json:"cash_in_lieu"`
+	Date        string       `json:"date"`
+}
+
+func (handler *ConvertStockHandler) ConvertStock(c echo.Context) error {
+	conversionOrder := new(ConversionOrder)
+	if err := c.Bind(conversionOrder); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	convertSharesCommand := command.NewConvertSharesCommand(shared.PortfolioId(conversionOrder.PortfolioId), conversionOrder.Ticker, conversionOrder.NewTicker, conversionOrder.RatioFrom, conversionOrder.RatioTo, conversionOrder.CashInLieu, shared.CommandDate(conversionOrder.Date))
+
+	err := handler.CommandHandler.HandleConvertShares(convertSharesCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
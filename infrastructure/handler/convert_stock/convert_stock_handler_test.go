@@ -0,0 +1,158 @@
+package convert_stock_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/infrastructure/handler/convert_stock"
+	"strings"
+	"testing"
+)
+
+type mockPortfolioCommandHandler struct {
+	convertSharesCommand command.ConvertSharesCommand
+	expectedError        error
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleAddSharesToPortfolio(command command.AddSharesToPortfolioCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleRemoveSharesFromPortfolio(command command.RemoveSharesFromPortfolioCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleRenameTicker(command command.RenameTickerCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSplitStock(command command.SplitStockCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleConvertShares(command command.ConvertSharesCommand) error {
+	mockPortfolioCommandHandler.convertSharesCommand = command
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSpinOff(command command.SpinOffCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) expectError(err error) {
+	mockPortfolioCommandHandler.expectedError = err
+}
+
+func TestConvertStock(t *testing.T) {
+	t.Run("it succeeds", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := convert_stock.ConvertStockHandler{&mock}
+		handler.ConvertStock(c)
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusCreated, rec.Code)
+		}
+	})
+
+	t.Run("it passes the cash in lieu", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"ticker\":\"FOO\",\"new_ticker\":\"BAR\",\"ratio_from\":3,\"ratio_to\":2,\"cash_in_lieu\":{\"amount\":12.5,\"currency\":\"USD\"},\"date\":\"2023-01-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := convert_stock.ConvertStockHandler{&mock}
+		handler.ConvertStock(c)
+
+		expected := command.NewConvertSharesCommand("", "FOO", "BAR", 3, 2, domain.NewMoneyFromFloat(12.5, "USD"), "2023-01-01")
+		if reflect.DeepEqual(mock.convertSharesCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.convertSharesCommand)
+		}
+	})
+
+	t.Run("it fails with 422 when command failed", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(errors.New("some error happened"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := convert_stock.ConvertStockHandler{&mock}
+		handler.ConvertStock(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := convert_stock.ConvertStockHandler{&mock}
+		handler.ConvertStock(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := convert_stock.ConvertStockHandler{&mock}
+		handler.ConvertStock(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"ratio_from\":\"1\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := convert_stock.ConvertStockHandler{&mock}
+		handler.ConvertStock(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
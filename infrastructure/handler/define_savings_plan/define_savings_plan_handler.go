@@ -0,0 +1,50 @@
+package define_savings_plan
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type DefineSavingsPlanHandler struct {
+	CommandHandler command_handler.SavingsPlanCommandHandlerInterface
+}
+
+type SavingsPlan struct {
+	PortfolioId  string          `json:"portfolio_id"`
+	Ticker       string          `json:"ticker"`
+	Amount       domain.Money    `json:"amount"`
+	Shares       domain.Quantity `json:"shares"`
+	Interval     string          `json:"interval"`
+	ExecutionDay int             `json:"execution_day"`
+	Start        string          `json:"start"`
+	End          string          `json:"end"`
+	Date         string          `json:"date"`
+}
+
+func (handler *DefineSavingsPlanHandler) DefineSavingsPlan(c echo.Context) error {
+	savingsPlan := new(SavingsPlan)
+	if err := c.Bind(savingsPlan); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	defineSavingsPlanCommand := command.NewDefineSavingsPlanCommand(shared.PortfolioId(savingsPlan.PortfolioId), savingsPlan.Ticker, savingsPlan.Amount, savingsPlan.Shares, savingsPlan.Interval, savingsPlan.ExecutionDay, savingsPlan.Start, savingsPlan.End, shared.CommandDate(savingsPlan.Date))
+
+	err := handler.CommandHandler.HandleDefineSavingsPlan(defineSavingsPlanCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
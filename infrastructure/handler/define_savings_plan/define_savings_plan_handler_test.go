@@ -0,0 +1,133 @@
+package define_savings_plan_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/infrastructure/handler/define_savings_plan"
+	"strings"
+	"testing"
+)
+
+type mockSavingsPlanCommandHandler struct {
+	defineSavingsPlanCommand command.DefineSavingsPlanCommand
+	expectedError            error
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) HandleDefineSavingsPlan(command command.DefineSavingsPlanCommand) error {
+	mockSavingsPlanCommandHandler.defineSavingsPlanCommand = command
+	return mockSavingsPlanCommandHandler.expectedError
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) HandleExecuteSavingsPlanOrder(command command.ExecuteSavingsPlanOrderCommand) error {
+	return mockSavingsPlanCommandHandler.expectedError
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) HandleFillSavingsPlanOrders(command command.FillSavingsPlanOrdersCommand) (command_handler.FillReport, error) {
+	return command_handler.FillReport{}, mockSavingsPlanCommandHandler.expectedError
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) expectError(err error) {
+	mockSavingsPlanCommandHandler.expectedError = err
+}
+
+func TestDefineSavingsPlan(t *testing.T) {
+	t.Run("it succeeds", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"ticker\":\"MO\",\"amount\":{\"amount\":100,\"currency\":\"EUR\"},\"interval\":\"monthly\",\"execution_day\":15,\"start\":\"2023-01-01\",\"date\":\"2023-01-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := define_savings_plan.DefineSavingsPlanHandler{&mock}
+		handler.DefineSavingsPlan(c)
+
+		expected := command.NewDefineSavingsPlanCommand("", "MO", domain.NewMoneyFromFloat(100, "EUR"), domain.Quantity{}, "monthly", 15, "2023-01-01", "", "2023-01-01")
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusCreated, rec.Code)
+		}
+		if reflect.DeepEqual(mock.defineSavingsPlanCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.defineSavingsPlanCommand)
+		}
+	})
+
+	t.Run("it fails with 422 when command failed", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.expectError(errors.New("some error happened"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := define_savings_plan.DefineSavingsPlanHandler{&mock}
+		handler.DefineSavingsPlan(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := define_savings_plan.DefineSavingsPlanHandler{&mock}
+		handler.DefineSavingsPlan(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := define_savings_plan.DefineSavingsPlanHandler{&mock}
+		handler.DefineSavingsPlan(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"execution_day\":\"15\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := define_savings_plan.DefineSavingsPlanHandler{&mock}
+		handler.DefineSavingsPlan(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package deposit_cash
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/cash/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type DepositCashHandler struct {
+	CommandHandler command_handler.CashCommandHandlerInterface
+}
+
+type Deposit struct {
+	PortfolioId string       `json:"portfolio_id"`
+	Amount      domain.Money `json:"amount"`
+	Date        string       `json:"date"`
+}
+
+func (handler *DepositCashHandler) DepositCash(c echo.Context) error {
+	deposit := new(Deposit)
+	if err := c.Bind(deposit); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	depositCashCommand := command.NewDepositCashCommand(shared.PortfolioId(deposit.PortfolioId), deposit.Amount, shared.CommandDate(deposit.Date))
+
+	err := handler.CommandHandler.HandleDepositCash(depositCashCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
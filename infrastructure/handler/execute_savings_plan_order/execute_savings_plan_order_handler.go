@@ -0,0 +1,47 @@
+package execute_savings_plan_order
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type ExecuteSavingsPlanOrderHandler struct {
+	CommandHandler command_handler.SavingsPlanCommandHandlerInterface
+}
+
+type Execution struct {
+	PortfolioId string       `json:"portfolio_id"`
+	PlanId      int          `json:"plan_id"`
+	DueDate     string       `json:"due_date"`
+	Price       domain.Money `json:"price"`
+	Fee         domain.Money `json:"fee"`
+	Date        string       `json:"date"`
+}
+
+func (handler *ExecuteSavingsPlanOrderHandler) ExecuteSavingsPlanOrder(c echo.Context) error {
+	execution := new(Execution)
+	if err := c.Bind(execution); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	executeSavingsPlanOrderCommand := command.NewExecuteSavingsPlanOrderCommand(shared.PortfolioId(execution.PortfolioId), execution.PlanId, execution.DueDate, execution.Price, execution.Fee, shared.CommandDate(execution.Date))
+
+	err := handler.CommandHandler.HandleExecuteSavingsPlanOrder(executeSavingsPlanOrderCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
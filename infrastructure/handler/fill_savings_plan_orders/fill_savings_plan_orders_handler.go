@@ -0,0 +1,42 @@
+package fill_savings_plan_orders
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/infrastructure"
+)
+
+type FillSavingsPlanOrdersHandler struct {
+	CommandHandler command_handler.SavingsPlanCommandHandlerInterface
+}
+
+type Fill struct {
+	PortfolioId string `json:"portfolio_id"`
+	Date        string `json:"date"`
+}
+
+func (handler *FillSavingsPlanOrdersHandler) FillSavingsPlanOrders(c echo.Context) error {
+	fill := new(Fill)
+	if err := c.Bind(fill); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	fillSavingsPlanOrdersCommand := command.NewFillSavingsPlanOrdersCommand(shared.PortfolioId(fill.PortfolioId), shared.CommandDate(fill.Date))
+
+	report, err := handler.CommandHandler.HandleFillSavingsPlanOrders(fillSavingsPlanOrdersCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, report)
+}
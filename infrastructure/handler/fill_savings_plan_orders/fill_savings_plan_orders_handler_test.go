@@ -0,0 +1,141 @@
+package fill_savings_plan_orders_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stock-monitor/application/savings_plan/command"
+	"stock-monitor/application/savings_plan/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/infrastructure"
+	"stock-monitor/infrastructure/handler/fill_savings_plan_orders"
+	"strings"
+	"testing"
+)
+
+type mockSavingsPlanCommandHandler struct {
+	fillSavingsPlanOrdersCommand command.FillSavingsPlanOrdersCommand
+	report                       command_handler.FillReport
+	expectedError                error
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) HandleDefineSavingsPlan(command command.DefineSavingsPlanCommand) error {
+	return mockSavingsPlanCommandHandler.expectedError
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) HandleExecuteSavingsPlanOrder(command command.ExecuteSavingsPlanOrderCommand) error {
+	return mockSavingsPlanCommandHandler.expectedError
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) HandleFillSavingsPlanOrders(command command.FillSavingsPlanOrdersCommand) (command_handler.FillReport, error) {
+	mockSavingsPlanCommandHandler.fillSavingsPlanOrdersCommand = command
+	return mockSavingsPlanCommandHandler.report, mockSavingsPlanCommandHandler.expectedError
+}
+
+func (mockSavingsPlanCommandHandler *mockSavingsPlanCommandHandler) expectError(err error) {
+	mockSavingsPlanCommandHandler.expectedError = err
+}
+
+func TestFillSavingsPlanOrders(t *testing.T) {
+	t.Run("it succeeds", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.report = command_handler.FillReport{[]command_handler.OrderResult{
+			{1, "2023-01-15", command_handler.Filled, ""},
+			{2, "2023-01-15", command_handler.Failed, "some error happened"},
+		}}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"portfolio_id\":\"retirement\",\"date\":\"2023-02-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := fill_savings_plan_orders.FillSavingsPlanOrdersHandler{&mock}
+		handler.FillSavingsPlanOrders(c)
+
+		expected := command.NewFillSavingsPlanOrdersCommand("retirement", "2023-02-01")
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusCreated, rec.Code)
+		}
+		if reflect.DeepEqual(mock.fillSavingsPlanOrdersCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.fillSavingsPlanOrdersCommand)
+		}
+		expectedBody := "{\"orders\":[{\"plan_id\":1,\"due_date\":\"2023-01-15\",\"status\":\"FILLED\"},{\"plan_id\":2,\"due_date\":\"2023-01-15\",\"status\":\"FAILED\",\"error\":\"some error happened\"}]}\n"
+		if rec.Body.String() != expectedBody {
+			t.Errorf("Unexpected body. Expected:%#v Got:%#v", expectedBody, rec.Body.String())
+		}
+	})
+
+	t.Run("it fails with 422 when command failed", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.expectError(errors.New("some error happened"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := fill_savings_plan_orders.FillSavingsPlanOrdersHandler{&mock}
+		handler.FillSavingsPlanOrders(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := fill_savings_plan_orders.FillSavingsPlanOrdersHandler{&mock}
+		handler.FillSavingsPlanOrders(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := fill_savings_plan_orders.FillSavingsPlanOrdersHandler{&mock}
+		handler.FillSavingsPlanOrders(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
+		mock := mockSavingsPlanCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"date\":1}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := fill_savings_plan_orders.FillSavingsPlanOrdersHandler{&mock}
+		handler.FillSavingsPlanOrders(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
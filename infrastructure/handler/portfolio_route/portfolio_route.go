@@ -0,0 +1,44 @@
+package portfolio_route
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/shared"
+)
+
+// PortfolioRoutes collects the handlers of every portfolio per path, each path is registered once
+// and dispatches on the portfolio id of the request
+type PortfolioRoutes struct {
+	paths    []string
+	handlers map[string]map[string]echo.HandlerFunc
+}
+
+func NewPortfolioRoutes() *PortfolioRoutes {
+	return &PortfolioRoutes{[]string{}, map[string]map[string]echo.HandlerFunc{}}
+}
+
+func (routes *PortfolioRoutes) Add(portfolioId string, path string, handler echo.HandlerFunc) {
+	if _, ok := routes.handlers[path]; !ok {
+		routes.paths = append(routes.paths, path)
+		routes.handlers[path] = map[string]echo.HandlerFunc{}
+	}
+	routes.handlers[path][portfolioId] = handler
+}
+
+// Register adds a GET route below /portfolios/:id for every path, unknown portfolio ids are answered with 404
+func (routes *PortfolioRoutes) Register(e *echo.Echo) {
+	for _, path := range routes.paths {
+		e.GET("/portfolios/:id"+path, dispatch(routes.handlers[path]))
+	}
+}
+
+func dispatch(handlers map[string]echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		handler, ok := handlers[c.Param("id")]
+		if !ok {
+			return c.String(http.StatusNotFound, shared.NewUnknownPortfolioError(c.Param("id")).Error())
+		}
+
+		return handler(c)
+	}
+}
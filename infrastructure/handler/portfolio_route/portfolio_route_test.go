@@ -0,0 +1,63 @@
+package portfolio_route_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/infrastructure/handler/portfolio_route"
+	"testing"
+)
+
+func respondWith(body string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.String(http.StatusOK, body)
+	}
+}
+
+func TestPortfolioRoutes(t *testing.T) {
+	e := echo.New()
+	routes := portfolio_route.NewPortfolioRoutes()
+	routes.Add("default", "/positions", respondWith("default positions"))
+	routes.Add("retirement", "/positions", respondWith("retirement positions"))
+	routes.Add("default", "/cash", respondWith("default cash"))
+	routes.Register(e)
+
+	t.Run("it registers every path once", func(t *testing.T) {
+		if len(e.Routes()) != 2 {
+			t.Errorf("Unexpected number of routes. Expected:%#v Got:%#v", 2, len(e.Routes()))
+		}
+	})
+
+	t.Run("it dispatches to the handler of the portfolio", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/portfolios/retirement/positions", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if rec.Body.String() != "retirement positions" {
+			t.Errorf("Unexpected body. Expected:%#v Got:%#v", "retirement positions", rec.Body.String())
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/portfolios/unknown/positions", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio has no handler for the path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/portfolios/retirement/cash", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package reinvest_dividend
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	dividend_command "stock-monitor/application/dividend/command"
+	dividend_command_handler "stock-monitor/application/dividend/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type ReinvestDividendHandler struct {
+	CommandHandler dividend_command_handler.DividendCommandHandlerInterface
+}
+
+type Reinvestment struct {
+	PortfolioId string       `json:"portfolio_id"`
+	Ticker      string       `json:"ticker"`
+	Net         domain.Money `json:"net"`
+	Gross       domain.Money `json:"gross"`
+	Price       domain.Money `json:"price"`
+	Date        string       `json:"date"`
+}
+
+func (handler *ReinvestDividendHandler) ReinvestDividend(c echo.Context) error {
+	reinvestment := new(Reinvestment)
+	if err := c.Bind(reinvestment); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	reinvestDividendCommand := dividend_command.NewReinvestDividendCommand(shared.PortfolioId(reinvestment.PortfolioId), reinvestment.Ticker, reinvestment.Net, reinvestment.Gross, reinvestment.Price, shared.CommandDate(reinvestment.Date))
+
+	err := handler.CommandHandler.HandleReinvestDividend(reinvestDividendCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -0,0 +1,128 @@
+package reinvest_dividend_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stock-monitor/application/dividend/command"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/infrastructure/handler/reinvest_dividend"
+	"strings"
+	"testing"
+)
+
+type mockDividendCommandHandler struct {
+	reinvestDividendCommand command.ReinvestDividendCommand
+	expectedError           error
+}
+
+func (mockDividendCommandHandler *mockDividendCommandHandler) HandleRecordDividend(command command.RecordDividendCommand) error {
+	return mockDividendCommandHandler.expectedError
+}
+
+func (mockDividendCommandHandler *mockDividendCommandHandler) HandleReinvestDividend(command command.ReinvestDividendCommand) error {
+	mockDividendCommandHandler.reinvestDividendCommand = command
+	return mockDividendCommandHandler.expectedError
+}
+
+func (mockDividendCommandHandler *mockDividendCommandHandler) expectError(err error) {
+	mockDividendCommandHandler.expectedError = err
+}
+
+func TestReinvestDividend(t *testing.T) {
+	t.Run("it succeeds", func(t *testing.T) {
+		mock := mockDividendCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"ticker\":\"MO\",\"net\":{\"amount\":20,\"currency\":\"USD\"},\"gross\":{\"amount\":25,\"currency\":\"USD\"},\"price\":{\"amount\":16,\"currency\":\"USD\"},\"date\":\"2023-01-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := reinvest_dividend.ReinvestDividendHandler{&mock}
+		handler.ReinvestDividend(c)
+
+		expected := command.NewReinvestDividendCommand("", "MO", domain.NewMoneyFromFloat(20, "USD"), domain.NewMoneyFromFloat(25, "USD"), domain.NewMoneyFromFloat(16, "USD"), "2023-01-01")
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusCreated, rec.Code)
+		}
+		if reflect.DeepEqual(mock.reinvestDividendCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.reinvestDividendCommand)
+		}
+	})
+
+	t.Run("it fails with 422 when command failed", func(t *testing.T) {
+		mock := mockDividendCommandHandler{}
+		mock.expectError(errors.New("some error happened"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := reinvest_dividend.ReinvestDividendHandler{&mock}
+		handler.ReinvestDividend(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockDividendCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := reinvest_dividend.ReinvestDividendHandler{&mock}
+		handler.ReinvestDividend(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockDividendCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"portfolio_id\":\"foo\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := reinvest_dividend.ReinvestDividendHandler{&mock}
+		handler.ReinvestDividend(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
+		mock := mockDividendCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"net\":\"many\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := reinvest_dividend.ReinvestDividendHandler{&mock}
+		handler.ReinvestDividend(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"stock-monitor/application/portfolio/command"
 	"stock-monitor/application/portfolio/command_handler"
 	"stock-monitor/application/shared"
+	"stock-monitor/infrastructure"
 )
 
 type RenameStockHandler struct {
@@ -13,9 +14,10 @@ type RenameStockHandler struct {
 }
 
 type RenameOrder struct {
-	OldTicker string `json:"old_ticker"`
-	NewTicker string `json:"new_ticker"`
-	Date      string `json:"date"`
+	PortfolioId string `json:"portfolio_id"`
+	OldTicker   string `json:"old_ticker"`
+	NewTicker   string `json:"new_ticker"`
+	Date        string `json:"date"`
 }
 
 func (handler *RenameStockHandler) RenameStock(c echo.Context) error {
@@ -24,10 +26,16 @@ func (handler *RenameStockHandler) RenameStock(c echo.Context) error {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	renameTickerCommand := command.NewRenameTickerCommand(renameOrder.OldTicker, renameOrder.NewTicker, shared.CommandDate(renameOrder.Date))
+	renameTickerCommand := command.NewRenameTickerCommand(shared.PortfolioId(renameOrder.PortfolioId), renameOrder.OldTicker, renameOrder.NewTicker, shared.CommandDate(renameOrder.Date))
 
 	err := handler.CommandHandler.HandleRenameTicker(renameTickerCommand)
 
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return c.String(http.StatusUnprocessableEntity, err.Error())
 	}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/shared"
+	"stock-monitor/infrastructure"
 	"stock-monitor/infrastructure/handler/rename_stock"
 	"strings"
 	"testing"
@@ -29,6 +31,18 @@ func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleRenameTick
 	return mockPortfolioCommandHandler.expectedError
 }
 
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSplitStock(command command.SplitStockCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleConvertShares(command command.ConvertSharesCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSpinOff(command command.SpinOffCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
 func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) expectError(err error) {
 	mockPortfolioCommandHandler.expectedError = err
 }
@@ -69,6 +83,42 @@ func TestRenameStock(t *testing.T) {
 		}
 	})
 
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := rename_stock.RenameStockHandler{&mock}
+		handler.RenameStock(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := rename_stock.RenameStockHandler{&mock}
+		handler.RenameStock(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
 	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
 		mock := mockPortfolioCommandHandler{}
 
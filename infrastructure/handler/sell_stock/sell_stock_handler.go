@@ -6,6 +6,9 @@ import (
 	"stock-monitor/application/portfolio/command"
 	"stock-monitor/application/portfolio/command_handler"
 	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
 )
 
 type SellStockHandler struct {
@@ -13,10 +16,21 @@ type SellStockHandler struct {
 }
 
 type SellOrder struct {
-	Ticker string  `json:"ticker"`
-	Shares int     `json:"shares"`
-	Price  float32 `json:"price"`
-	Date   string  `json:"date"`
+	PortfolioId string          `json:"portfolio_id"`
+	Ticker      string          `json:"ticker"`
+	Shares      domain.Quantity `json:"shares"`
+	Price       domain.Money    `json:"price"`
+	Fee         domain.Money    `json:"fee"`
+	Taxes       domain.Money    `json:"taxes"`
+	Date        string          `json:"date"`
+	Lots        []SoldLot       `json:"lots"`
+}
+
+// a lot is named either by its id or by its buy date
+type SoldLot struct {
+	LotId   int             `json:"lot_id"`
+	BuyDate string          `json:"buy_date"`
+	Shares  domain.Quantity `json:"shares"`
 }
 
 func (handler *SellStockHandler) SellStock(c echo.Context) error {
@@ -25,10 +39,21 @@ func (handler *SellStockHandler) SellStock(c echo.Context) error {
 		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand(sellOrder.Ticker, sellOrder.Shares, sellOrder.Price, shared.CommandDate(sellOrder.Date))
+	lots := []portfolio.LotSelection{}
+	for _, lot := range sellOrder.Lots {
+		lots = append(lots, portfolio.LotSelection{lot.LotId, lot.BuyDate, lot.Shares})
+	}
+
+	removeSharesCommand := command.NewRemoveSharesFromPortfolioCommand(shared.PortfolioId(sellOrder.PortfolioId), sellOrder.Ticker, sellOrder.Shares, sellOrder.Price, sellOrder.Fee, sellOrder.Taxes, shared.CommandDate(sellOrder.Date), lots)
 
 	err := handler.CommandHandler.HandleRemoveSharesFromPortfolio(removeSharesCommand)
 
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return c.String(http.StatusUnprocessableEntity, err.Error())
 	}
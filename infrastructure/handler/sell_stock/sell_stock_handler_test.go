@@ -5,7 +5,12 @@ import (
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
 	"stock-monitor/infrastructure/handler/sell_stock"
 	"strings"
 	"testing"
@@ -29,6 +34,18 @@ func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleRenameTick
 	return mockPortfolioCommandHandler.expectedError
 }
 
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSplitStock(command command.SplitStockCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleConvertShares(command command.ConvertSharesCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
+func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) HandleSpinOff(command command.SpinOffCommand) error {
+	return mockPortfolioCommandHandler.expectedError
+}
+
 func (mockPortfolioCommandHandler *mockPortfolioCommandHandler) expectError(err error) {
 	mockPortfolioCommandHandler.expectedError = err
 }
@@ -51,6 +68,26 @@ func TestSellStock(t *testing.T) {
 		}
 	})
 
+	t.Run("it passes selected lots to the command", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"ticker":"MO","shares":5,"price":10,"lots":[{"lot_id":3,"shares":2},{"buy_date":"2000-01-01","shares":3}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := sell_stock.SellStockHandler{&mock}
+		handler.SellStock(c)
+
+		want := []portfolio.LotSelection{
+			{3, "", domain.NewQuantityFromInt(2)},
+			{0, "2000-01-01", domain.NewQuantityFromInt(3)},
+		}
+		if reflect.DeepEqual(mock.removeSharesCommand.Lots, want) == false {
+			t.Errorf("Unexpected lots. Expected:%#v Got:%#v", want, mock.removeSharesCommand.Lots)
+		}
+	})
 	t.Run("it fails with 422 when command failed", func(t *testing.T) {
 		mock := mockPortfolioCommandHandler{}
 		mock.expectError(errors.New("some error happened"))
@@ -69,6 +106,42 @@ func TestSellStock(t *testing.T) {
 		}
 	})
 
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := sell_stock.SellStockHandler{&mock}
+		handler.SellStock(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockPortfolioCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := sell_stock.SellStockHandler{&mock}
+		handler.SellStock(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+
 	t.Run("it fails with 400 when input is not accepted", func(t *testing.T) {
 		mock := mockPortfolioCommandHandler{}
 
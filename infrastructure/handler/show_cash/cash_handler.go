@@ -0,0 +1,24 @@
+package show_cash
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/query"
+	cash_account "stock-monitor/query/cash-account"
+)
+
+type ShowCashHandler struct {
+	Query cash_account.CashAccountQueryInterface
+}
+
+func (handler *ShowCashHandler) ShowCash(c echo.Context) error {
+	cashAccount, err := handler.Query.GetCashAccount()
+	if _, unknownRate := err.(*query.UnknownExchangeRateError); unknownRate {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, cashAccount)
+}
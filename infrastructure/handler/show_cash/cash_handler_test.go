@@ -0,0 +1,39 @@
+package show_cash_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure/handler/show_cash"
+	cash_account "stock-monitor/query/cash-account"
+	"strings"
+	"testing"
+)
+
+type mockCashAccountQuery struct{}
+
+func (mockQuery *mockCashAccountQuery) GetCashAccount() (cash_account.CashAccount, error) {
+	return cash_account.CashAccount{
+		[]domain.Money{domain.NewMoneyFromFloat(100, "EUR")},
+		domain.NewMoneyFromFloat(100, "EUR"),
+		[]cash_account.Transaction{{cash_account.Deposit, "", "2000-01-01", domain.NewMoneyFromFloat(100, "EUR"), domain.NewMoneyFromFloat(100, "EUR")}},
+	}, nil
+}
+
+func TestShowCash(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := show_cash.ShowCashHandler{&mockCashAccountQuery{}}
+	handler.ShowCash(c)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "\"Type\":\"DEPOSIT\"") {
+		t.Errorf("Expected deposit in response but got %#v", rec.Body.String())
+	}
+}
@@ -3,6 +3,8 @@ package show_dividend_history
 import (
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"stock-monitor/domain"
+	"stock-monitor/query"
 	dividend_history "stock-monitor/query/dividend-history"
 	"strconv"
 )
@@ -12,15 +14,17 @@ type ShowDividendHistoryHandler struct {
 }
 
 type DividendResponse struct {
-	Ticker string
-	Net    float32
-	Gross  float32
-	Date   string
+	Ticker              string
+	Net                 domain.Money
+	Gross               domain.Money
+	Date                string
+	NetInBaseCurrency   domain.Money
+	GrossInBaseCurrency domain.Money
 }
 
 type DividendHistoryResponse struct {
 	Dividends      []DividendResponse
-	TotalDividends float32
+	TotalDividends domain.Money
 }
 
 func (handler *ShowDividendHistoryHandler) ShowDividendHistory(c echo.Context) error {
@@ -41,18 +45,35 @@ func (handler *ShowDividendHistoryHandler) ShowDividendHistory(c echo.Context) e
 		filter.ByTicker(ticker)
 	}
 
-	for _, dividend := range handler.Query.GetDividends(filter) {
+	recordedDividends, err := handler.Query.GetDividends(filter)
+	if _, unknownRate := err.(*query.UnknownExchangeRateError); unknownRate {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	totalDividends, err := handler.Query.GetSum(filter)
+	if _, unknownRate := err.(*query.UnknownExchangeRateError); unknownRate {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	for _, dividend := range recordedDividends {
 		dividends = append(dividends, DividendResponse{
-			Ticker: dividend.Ticker,
-			Net:    dividend.Net,
-			Gross:  dividend.Gross,
-			Date:   dividend.Date,
+			Ticker:              dividend.Ticker,
+			Net:                 dividend.Net,
+			Gross:               dividend.Gross,
+			Date:                dividend.Date,
+			NetInBaseCurrency:   dividend.NetInBaseCurrency,
+			GrossInBaseCurrency: dividend.GrossInBaseCurrency,
 		})
 	}
 
 	dividendHistoryResponse := DividendHistoryResponse{
 		Dividends:      dividends,
-		TotalDividends: handler.Query.GetSum(filter),
+		TotalDividends: totalDividends,
 	}
 
 	return c.JSON(http.StatusOK, dividendHistoryResponse)
@@ -0,0 +1,35 @@
+package show_fees
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/query"
+	"stock-monitor/query/fees"
+	"strconv"
+)
+
+type ShowFeesHandler struct {
+	Query fees.FeesQueryInterface
+}
+
+func (handler *ShowFeesHandler) ShowFees(c echo.Context) error {
+	year := 0
+	yearParam := c.QueryParam("year")
+	if yearParam != "" {
+		parsed, err := strconv.Atoi(yearParam)
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		year = parsed
+	}
+
+	fees, err := handler.Query.GetFees(year)
+	if _, unknownRate := err.(*query.UnknownExchangeRateError); unknownRate {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, fees)
+}
@@ -0,0 +1,92 @@
+package show_fees_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure/handler/show_fees"
+	"stock-monitor/query"
+	"stock-monitor/query/fees"
+	"testing"
+)
+
+type mockFeesQuery struct {
+	year          int
+	expectedError error
+}
+
+func (mockQuery *mockFeesQuery) GetFees(year int) (fees.Fees, error) {
+	mockQuery.year = year
+	return fees.Fees{[]fees.OrderFees{}, domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}, mockQuery.expectedError
+}
+
+func TestShowFees(t *testing.T) {
+	t.Run("it filters by year", func(t *testing.T) {
+		mock := mockFeesQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?year=2023", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_fees.ShowFeesHandler{&mock}
+		handler.ShowFees(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if mock.year != 2023 {
+			t.Errorf("Unexpected year. Expected:%#v Got:%#v", 2023, mock.year)
+		}
+	})
+
+	t.Run("it fails with 400 for an invalid year", func(t *testing.T) {
+		mock := mockFeesQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?year=last", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_fees.ShowFeesHandler{&mock}
+		handler.ShowFees(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 500 when the events can't be read", func(t *testing.T) {
+		mock := mockFeesQuery{expectedError: errors.New("no decoder registered for event")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_fees.ShowFeesHandler{&mock}
+		handler.ShowFees(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 422 when an exchange rate is missing", func(t *testing.T) {
+		mock := mockFeesQuery{expectedError: query.NewUnknownExchangeRateError("GBP", "EUR")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_fees.ShowFeesHandler{&mock}
+		handler.ShowFees(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+}
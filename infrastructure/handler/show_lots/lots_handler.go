@@ -0,0 +1,96 @@
+package show_lots
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/query/lots"
+)
+
+type ShowLotsHandler struct {
+	Query         lots.LotsQueryInterface
+	DefaultMethod lots.MatchingMethod
+}
+
+type OpenLotResponse struct {
+	Id                int
+	Ticker            string
+	BuyDate           string
+	NumberOfShares    domain.Quantity
+	Price             domain.Money
+	Fees              domain.Money
+	CostBasis         domain.Money
+	HoldingPeriodDays int
+	Term              string
+}
+
+type ClosedLotResponse struct {
+	Id                int
+	Ticker            string
+	BuyDate           string
+	SellDate          string
+	NumberOfShares    domain.Quantity
+	Price             domain.Money
+	SellPrice         domain.Money
+	Fees              domain.Money
+	CostBasis         domain.Money
+	HoldingPeriodDays int
+	Term              string
+}
+
+type LotsResponse struct {
+	Method     lots.MatchingMethod
+	OpenLots   []OpenLotResponse
+	ClosedLots []ClosedLotResponse
+}
+
+func (handler *ShowLotsHandler) ShowLots(c echo.Context) error {
+	method := handler.DefaultMethod
+	if c.QueryParam("method") != "" {
+		parsed, err := lots.ParseMatchingMethod(c.QueryParam("method"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		method = parsed
+	}
+
+	result, err := handler.Query.GetLots(method, c.QueryParam("ticker"), shared.CommandDate("").Get())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	openLots := []OpenLotResponse{}
+	for _, openLot := range result.OpenLots {
+		openLots = append(openLots, OpenLotResponse{
+			Id:                openLot.Lot.Id,
+			Ticker:            openLot.Lot.Ticker,
+			BuyDate:           openLot.Lot.BuyDate,
+			NumberOfShares:    openLot.Lot.NumberOfShares,
+			Price:             openLot.Lot.Price,
+			Fees:              openLot.Lot.Fees,
+			CostBasis:         openLot.Lot.CostBasis(),
+			HoldingPeriodDays: openLot.HoldingPeriod.Days,
+			Term:              openLot.HoldingPeriod.Term,
+		})
+	}
+
+	closedLots := []ClosedLotResponse{}
+	for _, closedLot := range result.ClosedLots {
+		closedLots = append(closedLots, ClosedLotResponse{
+			Id:                closedLot.Lot.Id,
+			Ticker:            closedLot.Lot.Ticker,
+			BuyDate:           closedLot.Lot.BuyDate,
+			SellDate:          closedLot.SellDate,
+			NumberOfShares:    closedLot.Lot.NumberOfShares,
+			Price:             closedLot.Lot.Price,
+			SellPrice:         closedLot.SellPrice,
+			Fees:              closedLot.Lot.Fees,
+			CostBasis:         closedLot.Lot.CostBasis(),
+			HoldingPeriodDays: closedLot.HoldingPeriod.Days,
+			Term:              closedLot.HoldingPeriod.Term,
+		})
+	}
+
+	return c.JSON(http.StatusOK, LotsResponse{method, openLots, closedLots})
+}
@@ -0,0 +1,76 @@
+package show_lots_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/infrastructure/handler/show_lots"
+	"stock-monitor/query/lots"
+	"testing"
+)
+
+type mockLotsQuery struct {
+	method        lots.MatchingMethod
+	ticker        string
+	expectedError error
+}
+
+func (mockQuery *mockLotsQuery) GetLots(method lots.MatchingMethod, ticker string, date string) (lots.Lots, error) {
+	mockQuery.method = method
+	mockQuery.ticker = ticker
+	return lots.Lots{[]lots.OpenLot{}, []lots.ClosedLot{}}, mockQuery.expectedError
+}
+
+func TestShowLots(t *testing.T) {
+	t.Run("it filters by ticker", func(t *testing.T) {
+		mock := mockLotsQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?ticker=MO&method=lifo", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_lots.ShowLotsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowLots(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if mock.ticker != "MO" || mock.method != lots.LastInFirstOut {
+			t.Errorf("Unexpected query. Got ticker:%#v method:%#v", mock.ticker, mock.method)
+		}
+	})
+
+	t.Run("it fails with 400 for unknown methods", func(t *testing.T) {
+		mock := mockLotsQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?method=random", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_lots.ShowLotsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowLots(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 500 when the events can't be read", func(t *testing.T) {
+		mock := mockLotsQuery{expectedError: errors.New("no decoder registered for event")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_lots.ShowLotsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowLots(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
+}
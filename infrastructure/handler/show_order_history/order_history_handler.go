@@ -3,6 +3,7 @@ package show_order_history
 import (
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"stock-monitor/domain"
 	orderHistory "stock-monitor/query/order-history"
 )
 
@@ -11,25 +12,37 @@ type ShowOrderHistoryHandler struct {
 }
 
 type OrderResponse struct {
-	OrderType      string
-	Ticker         string
-	Aliases        []string
-	NumberOfShares int
-	Price          float32
-	Date           string
+	OrderType              string
+	Ticker                 string
+	Aliases                []string
+	NumberOfShares         domain.Quantity
+	Price                  domain.Money
+	Fee                    domain.Money
+	Taxes                  domain.Money
+	AdjustedNumberOfShares domain.Quantity
+	AdjustedPrice          domain.Money
+	Date                   string
 }
 
 func (handler *ShowOrderHistoryHandler) ShowOrderHistory(c echo.Context) error {
-	orderResponse := []OrderResponse{}
+	orders, err := handler.Query.GetOrders()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
 
-	for _, order := range handler.Query.GetOrders() {
+	orderResponse := []OrderResponse{}
+	for _, order := range orders {
 		orderResponse = append(orderResponse, OrderResponse{
-			OrderType:      order.OrderType,
-			Ticker:         order.Ticker,
-			Aliases:        order.Aliases,
-			NumberOfShares: order.NumberOfShares,
-			Price:          order.Price,
-			Date:           order.Date,
+			OrderType:              order.OrderType,
+			Ticker:                 order.Ticker,
+			Aliases:                order.Aliases,
+			NumberOfShares:         order.NumberOfShares,
+			Price:                  order.Price,
+			Fee:                    order.Fee,
+			Taxes:                  order.Taxes,
+			AdjustedNumberOfShares: order.AdjustedNumberOfShares,
+			AdjustedPrice:          order.AdjustedPrice,
+			Date:                   order.Date,
 		})
 	}
 
@@ -1,21 +1,24 @@
 package show_order_history_test
 
 import (
+	"errors"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"stock-monitor/domain"
 	"stock-monitor/infrastructure/handler/show_order_history"
 	orderHistory "stock-monitor/query/order-history"
 	"testing"
 )
 
 type MockOrderHistoryQuery struct {
-	orders []orderHistory.Order
+	orders        []orderHistory.Order
+	expectedError error
 }
 
-func (mockOrderHistory *MockOrderHistoryQuery) GetOrders() []orderHistory.Order {
-	return mockOrderHistory.orders
+func (mockOrderHistory *MockOrderHistoryQuery) GetOrders() ([]orderHistory.Order, error) {
+	return mockOrderHistory.orders, mockOrderHistory.expectedError
 }
 
 func TestShowOrderHistory(t *testing.T) {
@@ -24,10 +27,14 @@ func TestShowOrderHistory(t *testing.T) {
 			"BUY",
 			"MO",
 			[]string{"FOO"},
-			10,
-			10.00,
+			domain.NewQuantityFromInt(10),
+			domain.NewMoneyFromFloat(10.00, "EUR"),
+			domain.NewMoneyFromFloat(1.00, "EUR"),
+			domain.NewMoneyFromFloat(0.00, "EUR"),
+			domain.NewQuantityFromInt(10),
+			domain.NewMoneyFromFloat(10.00, "EUR"),
 			"2001-01-01",
-		}}}
+		}}, nil}
 
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -41,4 +48,20 @@ func TestShowOrderHistory(t *testing.T) {
 			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
 		}
 	})
+
+	t.Run("it fails with 500 when the events can't be read", func(t *testing.T) {
+		mock := MockOrderHistoryQuery{nil, errors.New("no decoder registered for event")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_order_history.ShowOrderHistoryHandler{&mock}
+		handler.ShowOrderHistory(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
 }
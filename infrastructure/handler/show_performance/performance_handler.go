@@ -0,0 +1,39 @@
+package show_performance
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/query"
+	"stock-monitor/query/performance"
+	"time"
+)
+
+type ShowPerformanceHandler struct {
+	Query performance.PerformanceQueryInterface
+}
+
+func (handler *ShowPerformanceHandler) ShowPerformance(c echo.Context) error {
+	from := c.QueryParam("from")
+	to := c.QueryParam("to")
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return c.String(http.StatusBadRequest, "from must not be after to")
+	}
+
+	result, err := handler.Query.GetPerformance(from, to)
+	if _, unreadable := err.(*query.UnreadableEventStreamError); unreadable {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
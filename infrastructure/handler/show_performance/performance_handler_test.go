@@ -0,0 +1,109 @@
+package show_performance_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/infrastructure/handler/show_performance"
+	"stock-monitor/query"
+	"stock-monitor/query/performance"
+	"testing"
+)
+
+type mockPerformanceQuery struct {
+	from          string
+	to            string
+	expectedError error
+}
+
+func (mockQuery *mockPerformanceQuery) GetPerformance(from string, to string) (performance.Performance, error) {
+	mockQuery.from = from
+	mockQuery.to = to
+	return performance.Performance{}, mockQuery.expectedError
+}
+
+func TestShowPerformance(t *testing.T) {
+	t.Run("it passes the period", func(t *testing.T) {
+		mock := mockPerformanceQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?from=2023-01-01&to=2023-06-30", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_performance.ShowPerformanceHandler{&mock}
+		handler.ShowPerformance(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if mock.from != "2023-01-01" || mock.to != "2023-06-30" {
+			t.Errorf("Unexpected period. Got:%#v %#v", mock.from, mock.to)
+		}
+	})
+
+	t.Run("it fails with 400 for an invalid date", func(t *testing.T) {
+		mock := mockPerformanceQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?from=yesterday", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_performance.ShowPerformanceHandler{&mock}
+		handler.ShowPerformance(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 400 when from is after to", func(t *testing.T) {
+		mock := mockPerformanceQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?from=2023-02-01&to=2023-01-01", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_performance.ShowPerformanceHandler{&mock}
+		handler.ShowPerformance(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 422 when prices are missing", func(t *testing.T) {
+		mock := mockPerformanceQuery{expectedError: errors.New("no price found")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_performance.ShowPerformanceHandler{&mock}
+		handler.ShowPerformance(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 500 when the events can't be read", func(t *testing.T) {
+		mock := mockPerformanceQuery{expectedError: query.NewUnreadableEventStreamError(errors.New("no decoder registered for event"))}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_performance.ShowPerformanceHandler{&mock}
+		handler.ShowPerformance(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
+}
@@ -3,25 +3,100 @@ package show_portfolio
 import (
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"stock-monitor/domain"
+	"stock-monitor/query"
 	positionList "stock-monitor/query/position_list"
+	"time"
 )
 
 type ShowPortfolioHandler struct {
-	Query positionList.PositionListQuery
+	Query        positionList.PositionListQuery
+	BaseCurrency string
 }
 
 type PositionResponse struct {
-	Ticker       string
-	Shares       int
-	CurrentValue float32
+	Ticker                     string
+	Shares                     domain.Quantity
+	CurrentValue               domain.Money
+	CurrentValueInBaseCurrency domain.Money
+	AverageBuyPrice            domain.Money
+	CostBasis                  domain.Money
+	UnrealizedGain             domain.Money
+	UnrealizedGainPercentage   domain.Percentage
 }
 
+type TotalsResponse struct {
+	TotalValue                    domain.Money
+	TotalCostBasis                domain.Money
+	TotalUnrealizedGain           domain.Money
+	TotalUnrealizedGainPercentage domain.Percentage
+}
+
+// answers the positions by ticker, the shape clients of GET /portfolio rely on
 func (handler *ShowPortfolioHandler) ShowPortfolio(c echo.Context) error {
-	positionsResponse := map[string]PositionResponse{}
+	positions, status, err := handler.positions(c)
+	if err != nil {
+		return c.String(status, err.Error())
+	}
 
-	for _, position := range handler.Query.GetPositions() {
-		positionsResponse[position.Ticker] = PositionResponse{position.Ticker, position.Shares, position.CurrentValue}
+	positionsResponse := map[string]PositionResponse{}
+	for _, position := range positions {
+		positionsResponse[position.Ticker] = PositionResponse{
+			Ticker:                     position.Ticker,
+			Shares:                     position.Shares,
+			CurrentValue:               position.CurrentValue,
+			CurrentValueInBaseCurrency: position.CurrentValueInBaseCurrency,
+			AverageBuyPrice:            position.AverageBuyPrice,
+			CostBasis:                  position.CostBasis,
+			UnrealizedGain:             position.UnrealizedGain,
+			UnrealizedGainPercentage:   position.UnrealizedGainPercentage,
+		}
 	}
 
 	return c.JSON(http.StatusOK, positionsResponse)
 }
+
+func (handler *ShowPortfolioHandler) ShowTotals(c echo.Context) error {
+	positions, status, err := handler.positions(c)
+	if err != nil {
+		return c.String(status, err.Error())
+	}
+
+	totals, err := positionList.CalculateTotals(positions, handler.BaseCurrency)
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, TotalsResponse{
+		TotalValue:                    totals.Value,
+		TotalCostBasis:                totals.CostBasis,
+		TotalUnrealizedGain:           totals.UnrealizedGain,
+		TotalUnrealizedGainPercentage: totals.UnrealizedGainPercentage,
+	})
+}
+
+// with as_of the positions held at the end of that day are valued at its close,
+// a missing close or exchange rate is answered with 422
+func (handler *ShowPortfolioHandler) positions(c echo.Context) (map[string]positionList.Position, int, error) {
+	var positions map[string]positionList.Position
+	var err error
+
+	asOf := c.QueryParam("as_of")
+	if asOf == "" {
+		positions, err = handler.Query.GetPositions()
+	} else {
+		if _, err := time.Parse("2006-01-02", asOf); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		positions, err = handler.Query.GetPositionsAsOf(asOf)
+	}
+
+	if _, unreadable := err.(*query.UnreadableEventStreamError); unreadable {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+
+	return positions, http.StatusOK, nil
+}
@@ -1,31 +1,38 @@
 package show_portfolio_test
 
 import (
+	"errors"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"stock-monitor/domain"
 	showPortfolioHandler "stock-monitor/infrastructure/handler/show_portfolio"
+	"stock-monitor/query"
 	positionList "stock-monitor/query/position_list"
+	"strings"
 	"testing"
 )
 
 type MockPositionList struct {
-	positions map[string]positionList.Position
+	positions     map[string]positionList.Position
+	asOf          string
+	expectedError error
 }
 
-func (mockPositionList *MockPositionList) GetPositions() map[string]positionList.Position {
-	return mockPositionList.positions
+func (mockPositionList *MockPositionList) GetPositions() (map[string]positionList.Position, error) {
+	return mockPositionList.positions, mockPositionList.expectedError
+}
+
+func (mockPositionList *MockPositionList) GetPositionsAsOf(date string) (map[string]positionList.Position, error) {
+	mockPositionList.asOf = date
+	return mockPositionList.positions, mockPositionList.expectedError
 }
 
 func TestShowPortfolio(t *testing.T) {
 	t.Run("should return 200 status ok", func(t *testing.T) {
 		mock := MockPositionList{positions: map[string]positionList.Position{
-			"MO": {
-				Ticker:       "MO",
-				Shares:       10,
-				CurrentValue: 10,
-			},
+			"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(8, "EUR"), domain.NewMoneyFromFloat(8, "EUR")),
 		}}
 
 		e := echo.New()
@@ -33,11 +40,131 @@ func TestShowPortfolio(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 
-		handler := showPortfolioHandler.ShowPortfolioHandler{&mock}
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
 		handler.ShowPortfolio(c)
 
 		if reflect.DeepEqual(rec.Code, http.StatusOK) == false {
 			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
 		}
 	})
+	t.Run("should return the positions by ticker", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{
+			"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(8, "EUR"), domain.NewMoneyFromFloat(8, "EUR")),
+		}}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+		handler.ShowPortfolio(c)
+
+		expected := `{"MO":{"Ticker":"MO","Shares":10,`
+		if strings.HasPrefix(rec.Body.String(), expected) == false {
+			t.Errorf("Unexpected response body. Expected to start with:%#v Got:%#v", expected, rec.Body.String())
+		}
+	})
+	t.Run("should return portfolio totals", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{
+			"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(8, "EUR"), domain.NewMoneyFromFloat(8, "EUR")),
+		}}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+		handler.ShowTotals(c)
+
+		expected := `"TotalUnrealizedGain":{"Amount":2,"Currency":"EUR"},"TotalUnrealizedGainPercentage":25`
+		if strings.Contains(rec.Body.String(), expected) == false {
+			t.Errorf("Unexpected response body. Expected to contain:%#v Got:%#v", expected, rec.Body.String())
+		}
+	})
+	t.Run("should return positions as of a date", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{}}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?as_of=2022-12-31", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+		handler.ShowPortfolio(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if mock.asOf != "2022-12-31" {
+			t.Errorf("Unexpected date. Expected:%#v Got:%#v", "2022-12-31", mock.asOf)
+		}
+	})
+	t.Run("should return 400 for an invalid as of date", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{}}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?as_of=yesterday", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+		handler.ShowPortfolio(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+	t.Run("should return 422 when the close of the day is missing", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{}, expectedError: errors.New("no price found")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?as_of=2022-12-31", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+		handler.ShowPortfolio(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+	t.Run("should return 422 naming the missing exchange rate", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{}, expectedError: query.NewUnknownExchangeRateError("GBP", "EUR")}
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+
+		for name, show := range map[string]func(echo.Context) error{"positions": handler.ShowPortfolio, "totals": handler.ShowTotals} {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			show(c)
+
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Unexpected status code of %s. Expected:%#v Got:%#v", name, http.StatusUnprocessableEntity, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "from: GBP to: EUR") == false {
+				t.Errorf("Unexpected response body of %s: %#v", name, rec.Body.String())
+			}
+		}
+	})
+	t.Run("should return 500 when the events can't be read", func(t *testing.T) {
+		mock := MockPositionList{positions: map[string]positionList.Position{}, expectedError: query.NewUnreadableEventStreamError(errors.New("unknown event"))}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := showPortfolioHandler.ShowPortfolioHandler{&mock, "EUR"}
+		handler.ShowPortfolio(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
 }
@@ -0,0 +1,48 @@
+package show_portfolio_history
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/query"
+	portfolio_history "stock-monitor/query/portfolio-history"
+	"time"
+)
+
+type ShowPortfolioHistoryHandler struct {
+	Query portfolio_history.PortfolioHistoryQueryInterface
+}
+
+func (handler *ShowPortfolioHistoryHandler) ShowPortfolioHistory(c echo.Context) error {
+	from := c.QueryParam("from")
+	to := c.QueryParam("to")
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return c.String(http.StatusBadRequest, "from must not be after to")
+	}
+
+	interval := portfolio_history.Day
+	if c.QueryParam("interval") != "" {
+		parsed, err := portfolio_history.ParseInterval(c.QueryParam("interval"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		interval = parsed
+	}
+
+	points, err := handler.Query.GetHistory(from, to, interval)
+	if _, unreadable := err.(*query.UnreadableEventStreamError); unreadable {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, points)
+}
@@ -0,0 +1,127 @@
+package show_portfolio_history_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/infrastructure/handler/show_portfolio_history"
+	"stock-monitor/query"
+	portfolio_history "stock-monitor/query/portfolio-history"
+	"testing"
+)
+
+type mockPortfolioHistoryQuery struct {
+	from          string
+	to            string
+	interval      portfolio_history.Interval
+	expectedError error
+}
+
+func (mockQuery *mockPortfolioHistoryQuery) GetHistory(from string, to string, interval portfolio_history.Interval) ([]portfolio_history.Point, error) {
+	mockQuery.from = from
+	mockQuery.to = to
+	mockQuery.interval = interval
+	return []portfolio_history.Point{}, mockQuery.expectedError
+}
+
+func TestShowPortfolioHistory(t *testing.T) {
+	t.Run("it passes period and interval", func(t *testing.T) {
+		mock := mockPortfolioHistoryQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?from=2023-01-01&to=2023-06-30&interval=week", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_portfolio_history.ShowPortfolioHistoryHandler{&mock}
+		handler.ShowPortfolioHistory(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if mock.from != "2023-01-01" || mock.to != "2023-06-30" || mock.interval != portfolio_history.Week {
+			t.Errorf("Unexpected parameters. Got:%#v %#v %#v", mock.from, mock.to, mock.interval)
+		}
+	})
+
+	t.Run("it defaults to daily points", func(t *testing.T) {
+		mock := mockPortfolioHistoryQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_portfolio_history.ShowPortfolioHistoryHandler{&mock}
+		handler.ShowPortfolioHistory(c)
+
+		if mock.interval != portfolio_history.Day {
+			t.Errorf("Unexpected interval. Expected:%#v Got:%#v", portfolio_history.Day, mock.interval)
+		}
+	})
+
+	t.Run("it fails with 400 for an invalid interval", func(t *testing.T) {
+		mock := mockPortfolioHistoryQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?interval=hour", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_portfolio_history.ShowPortfolioHistoryHandler{&mock}
+		handler.ShowPortfolioHistory(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 400 for an invalid date", func(t *testing.T) {
+		mock := mockPortfolioHistoryQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?to=tomorrow", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_portfolio_history.ShowPortfolioHistoryHandler{&mock}
+		handler.ShowPortfolioHistory(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 422 when prices are missing", func(t *testing.T) {
+		mock := mockPortfolioHistoryQuery{expectedError: errors.New("no price found")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_portfolio_history.ShowPortfolioHistoryHandler{&mock}
+		handler.ShowPortfolioHistory(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 500 when the events can't be read", func(t *testing.T) {
+		mock := mockPortfolioHistoryQuery{expectedError: query.NewUnreadableEventStreamError(errors.New("no decoder registered for event"))}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_portfolio_history.ShowPortfolioHistoryHandler{&mock}
+		handler.ShowPortfolioHistory(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package show_realized_gains
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/domain"
+	"stock-monitor/query"
+	"stock-monitor/query/lots"
+	realized_gains "stock-monitor/query/realized-gains"
+	"strconv"
+)
+
+type ShowRealizedGainsHandler struct {
+	Query         realized_gains.RealizedGainsQueryInterface
+	DefaultMethod lots.MatchingMethod
+}
+
+type LotResponse struct {
+	Id             int
+	BuyDate        string
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fees           domain.Money
+	CostBasis      domain.Money
+}
+
+type RealizedGainResponse struct {
+	Ticker         string
+	Aliases        []string
+	Date           string
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fees           domain.Money
+	Proceeds       domain.Money
+	CostBasis      domain.Money
+	Gain           domain.Money
+	MatchedLots    []LotResponse
+}
+
+type RealizedGainsResponse struct {
+	Method    lots.MatchingMethod
+	Sales     []RealizedGainResponse
+	TotalGain domain.Money
+}
+
+func (handler *ShowRealizedGainsHandler) ShowRealizedGains(c echo.Context) error {
+	method := handler.DefaultMethod
+	if c.QueryParam("method") != "" {
+		parsed, err := lots.ParseMatchingMethod(c.QueryParam("method"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		method = parsed
+	}
+
+	filter := realized_gains.NewFilter()
+	yearParam := c.QueryParam("year")
+	if yearParam != "" {
+		year, err := strconv.Atoi(yearParam)
+		if err != nil {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+		filter.ByYear(year)
+	}
+
+	ticker := c.QueryParam("ticker")
+	if ticker != "" {
+		filter.ByTicker(ticker)
+	}
+
+	realizedGains, err := handler.Query.GetRealizedGains(method, filter)
+	if _, unknownRate := err.(*query.UnknownExchangeRateError); unknownRate {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	sales := []RealizedGainResponse{}
+	for _, sale := range realizedGains.Sales {
+		matchedLots := []LotResponse{}
+		for _, lot := range sale.MatchedLots {
+			matchedLots = append(matchedLots, LotResponse{lot.Id, lot.BuyDate, lot.NumberOfShares, lot.Price, lot.Fees, lot.CostBasis()})
+		}
+
+		sales = append(sales, RealizedGainResponse{
+			Ticker:         sale.Ticker,
+			Aliases:        sale.Aliases,
+			Date:           sale.Date,
+			NumberOfShares: sale.NumberOfShares,
+			Price:          sale.Price,
+			Fees:           sale.Fees,
+			Proceeds:       sale.Proceeds,
+			CostBasis:      sale.CostBasis,
+			Gain:           sale.Gain,
+			MatchedLots:    matchedLots,
+		})
+	}
+
+	return c.JSON(http.StatusOK, RealizedGainsResponse{method, sales, realizedGains.TotalGain})
+}
@@ -0,0 +1,109 @@
+package show_realized_gains_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure/handler/show_realized_gains"
+	"stock-monitor/query"
+	"stock-monitor/query/lots"
+	realized_gains "stock-monitor/query/realized-gains"
+	"testing"
+)
+
+type mockRealizedGainsQuery struct {
+	method        lots.MatchingMethod
+	expectedError error
+}
+
+func (mockQuery *mockRealizedGainsQuery) GetRealizedGains(method lots.MatchingMethod, filter realized_gains.Filter) (realized_gains.RealizedGains, error) {
+	mockQuery.method = method
+	return realized_gains.RealizedGains{[]realized_gains.RealizedGain{}, domain.NewMoneyFromFloat(0, "EUR")}, mockQuery.expectedError
+}
+
+func TestShowRealizedGains(t *testing.T) {
+	t.Run("it uses the default method", func(t *testing.T) {
+		mock := mockRealizedGainsQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?year=2023&ticker=MO", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_realized_gains.ShowRealizedGainsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowRealizedGains(c)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+		}
+		if mock.method != lots.FirstInFirstOut {
+			t.Errorf("Unexpected method. Expected:%#v Got:%#v", lots.FirstInFirstOut, mock.method)
+		}
+	})
+
+	t.Run("it uses the requested method", func(t *testing.T) {
+		mock := mockRealizedGainsQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?method=lifo", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_realized_gains.ShowRealizedGainsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowRealizedGains(c)
+
+		if mock.method != lots.LastInFirstOut {
+			t.Errorf("Unexpected method. Expected:%#v Got:%#v", lots.LastInFirstOut, mock.method)
+		}
+	})
+
+	t.Run("it fails with 400 for unknown methods", func(t *testing.T) {
+		mock := mockRealizedGainsQuery{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?method=random", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_realized_gains.ShowRealizedGainsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowRealizedGains(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 500 when the events can't be read", func(t *testing.T) {
+		mock := mockRealizedGainsQuery{expectedError: errors.New("no decoder registered for event")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_realized_gains.ShowRealizedGainsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowRealizedGains(c)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusInternalServerError, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 422 when an exchange rate is missing", func(t *testing.T) {
+		mock := mockRealizedGainsQuery{expectedError: query.NewUnknownExchangeRateError("GBP", "EUR")}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := show_realized_gains.ShowRealizedGainsHandler{&mock, lots.FirstInFirstOut}
+		handler.ShowRealizedGains(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+}
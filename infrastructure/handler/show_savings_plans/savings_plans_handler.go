@@ -0,0 +1,20 @@
+package show_savings_plans
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	savings_plans "stock-monitor/query/savings-plans"
+)
+
+type ShowSavingsPlansHandler struct {
+	Query savings_plans.SavingsPlansQueryInterface
+}
+
+func (handler *ShowSavingsPlansHandler) ShowSavingsPlans(c echo.Context) error {
+	savingsPlans, err := handler.Query.GetSavingsPlans()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, savingsPlans)
+}
@@ -0,0 +1,42 @@
+package show_savings_plans_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"stock-monitor/domain"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure/handler/show_savings_plans"
+	savings_plans "stock-monitor/query/savings-plans"
+	"strings"
+	"testing"
+)
+
+type mockSavingsPlansQuery struct{}
+
+func (mockQuery *mockSavingsPlansQuery) GetSavingsPlans() (savings_plans.SavingsPlans, error) {
+	amount := domain.NewMoneyFromFloat(100, "EUR")
+	shares := domain.NewQuantityFromInt(0)
+
+	return savings_plans.SavingsPlans{
+		[]savings_plan.Plan{{1, "MO", amount, shares, savings_plan.Monthly, 15, "2000-01-01", ""}},
+		[]savings_plan.PendingOrder{{1, "MO", "2000-01-15", amount, shares}},
+	}, nil
+}
+
+func TestShowSavingsPlans(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := show_savings_plans.ShowSavingsPlansHandler{&mockSavingsPlansQuery{}}
+	handler.ShowSavingsPlans(c)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusOK, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "\"DueDate\":\"2000-01-15\"") {
+		t.Errorf("Expected pending order in response but got %#v", rec.Body.String())
+	}
+}
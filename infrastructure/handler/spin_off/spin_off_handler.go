@@ -0,0 +1,48 @@
+package spin_off
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/portfolio/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type SpinOffHandler struct {
+	CommandHandler command_handler.PortfolioCommandHandlerInterface
+}
+
+type SpinOffOrder struct {
+	PortfolioId         string            `json:"portfolio_id"`
+	Ticker              string            `json:"ticker"`
+	NewTicker           string            `json:"new_ticker"`
+	RatioFrom           int               `json:"ratio_from"`
+	RatioTo             int               `json:"ratio_to"`
+	CostBasisPercentage domain.Percentage `json:"cost_basis_percentage"`
+	Date                string            `json:"date"`
+}
+
+func (handler *SpinOffHandler) SpinOff(c echo.Context) error {
+	spinOffOrder := new(SpinOffOrder)
+	if err := c.Bind(spinOffOrder); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	spinOffCommand := command.NewSpinOffCommand(shared.PortfolioId(spinOffOrder.PortfolioId), spinOffOrder.Ticker, spinOffOrder.NewTicker, spinOffOrder.RatioFrom, spinOffOrder.RatioTo, spinOffOrder.CostBasisPercentage, shared.CommandDate(spinOffOrder.Date))
+
+	err := handler.CommandHandler.HandleSpinOff(spinOffCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
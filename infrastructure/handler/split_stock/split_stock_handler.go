@@ -0,0 +1,45 @@
+package split_stock
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/portfolio/command"
+	"stock-monitor/application/portfolio/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/infrastructure"
+)
+
+type SplitStockHandler struct {
+	CommandHandler command_handler.PortfolioCommandHandlerInterface
+}
+
+type SplitOrder struct {
+	PortfolioId string `json:"portfolio_id"`
+	Ticker      string `json:"ticker"`
+	RatioFrom   int    `json:"ratio_from"`
+	RatioTo     int    `json:"ratio_to"`
+	Date        string `json:"date"`
+}
+
+func (handler *SplitStockHandler) SplitStock(c echo.Context) error {
+	splitOrder := new(SplitOrder)
+	if err := c.Bind(splitOrder); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	splitStockCommand := command.NewSplitStockCommand(shared.PortfolioId(splitOrder.PortfolioId), splitOrder.Ticker, splitOrder.RatioFrom, splitOrder.RatioTo, shared.CommandDate(splitOrder.Date))
+
+	err := handler.CommandHandler.HandleSplitStock(splitStockCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
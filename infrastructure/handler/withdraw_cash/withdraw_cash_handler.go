@@ -0,0 +1,44 @@
+package withdraw_cash
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/cash/command_handler"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+)
+
+type WithdrawCashHandler struct {
+	CommandHandler command_handler.CashCommandHandlerInterface
+}
+
+type Withdrawal struct {
+	PortfolioId string       `json:"portfolio_id"`
+	Amount      domain.Money `json:"amount"`
+	Date        string       `json:"date"`
+}
+
+func (handler *WithdrawCashHandler) WithdrawCash(c echo.Context) error {
+	withdrawal := new(Withdrawal)
+	if err := c.Bind(withdrawal); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	withdrawCashCommand := command.NewWithdrawCashCommand(shared.PortfolioId(withdrawal.PortfolioId), withdrawal.Amount, shared.CommandDate(withdrawal.Date))
+
+	err := handler.CommandHandler.HandleWithdrawCash(withdrawCashCommand)
+
+	if _, unknown := err.(*shared.UnknownPortfolioError); unknown {
+		return c.String(http.StatusNotFound, err.Error())
+	}
+	if _, conflict := err.(*infrastructure.ConcurrencyConflictError); conflict {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
@@ -0,0 +1,128 @@
+package withdraw_cash_test
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"stock-monitor/application/cash/command"
+	"stock-monitor/application/shared"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/infrastructure/handler/withdraw_cash"
+	"strings"
+	"testing"
+)
+
+type mockCashCommandHandler struct {
+	withdrawCashCommand command.WithdrawCashCommand
+	expectedError       error
+}
+
+func (mockCashCommandHandler *mockCashCommandHandler) HandleDepositCash(command command.DepositCashCommand) error {
+	return nil
+}
+
+func (mockCashCommandHandler *mockCashCommandHandler) HandleWithdrawCash(command command.WithdrawCashCommand) error {
+	mockCashCommandHandler.withdrawCashCommand = command
+	return mockCashCommandHandler.expectedError
+}
+
+func (mockCashCommandHandler *mockCashCommandHandler) expectError(err error) {
+	mockCashCommandHandler.expectedError = err
+}
+
+func TestWithdrawCash(t *testing.T) {
+	t.Run("it succeeds", func(t *testing.T) {
+		mock := mockCashCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"amount\":{\"amount\":100,\"currency\":\"USD\"},\"date\":\"2000-01-01\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := withdraw_cash.WithdrawCashHandler{&mock}
+		handler.WithdrawCash(c)
+
+		expected := command.WithdrawCashCommand{"default", domain.NewMoneyFromFloat(100, "USD"), "2000-01-01"}
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusCreated, rec.Code)
+		}
+		if reflect.DeepEqual(mock.withdrawCashCommand, expected) == false {
+			t.Errorf("Unexpected command. Expected:%#v Got:%#v", expected, mock.withdrawCashCommand)
+		}
+	})
+
+	t.Run("it fails with 400 when request is invalid", func(t *testing.T) {
+		mock := mockCashCommandHandler{}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"amount\":\"foo\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := withdraw_cash.WithdrawCashHandler{&mock}
+		handler.WithdrawCash(c)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 422 when command failed", func(t *testing.T) {
+		mock := mockCashCommandHandler{}
+		mock.expectError(errors.New("some error happened"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := withdraw_cash.WithdrawCashHandler{&mock}
+		handler.WithdrawCash(c)
+
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 409 when event stream was modified concurrently", func(t *testing.T) {
+		mock := mockCashCommandHandler{}
+		mock.expectError(infrastructure.NewConcurrencyConflictError(1, 2))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"foo\":\"bar\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := withdraw_cash.WithdrawCashHandler{&mock}
+		handler.WithdrawCash(c)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("it fails with 404 when portfolio is unknown", func(t *testing.T) {
+		mock := mockCashCommandHandler{}
+		mock.expectError(shared.NewUnknownPortfolioError("foo"))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{\"portfolio_id\":\"foo\"}"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := withdraw_cash.WithdrawCashHandler{&mock}
+		handler.WithdrawCash(c)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Unexpected status code. Expected:%#v Got:%#v", http.StatusNotFound, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type JsonLinesEventStream struct {
+	StoragePath string
+	FileName    string
+}
+
+type jsonLine struct {
+	Name     string                 `json:"name"`
+	Payload  map[string]interface{} `json:"payload"`
+	MetaData map[string]interface{} `json:"metadata"`
+}
+
+// JsonLinesFileName replaces the extension of a configured stream file, so a json lines stream never opens a gob file
+func JsonLinesFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".jsonl"
+}
+
+func (eventStream *JsonLinesEventStream) Add(event Event) error {
+	return eventStream.AppendBatch([]Event{event}, AnyVersion)
+}
+
+func (eventStream *JsonLinesEventStream) AppendBatch(events []Event, expectedVersion int) error {
+	lock := eventStream.lock()
+	err := lock.acquire()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	version, lastEvent, err := eventStream.tail()
+	if err != nil {
+		return err
+	}
+
+	if expectedVersion != AnyVersion && expectedVersion != version {
+		return NewConcurrencyConflictError(expectedVersion, version)
+	}
+
+	last := ""
+	if version > 0 {
+		last = lastOccurredAt([]Event{lastEvent})
+	}
+	err = validateBatch(events, last)
+	if err != nil {
+		return err
+	}
+
+	lines := bytes.Buffer{}
+	for _, event := range events {
+		line, err := encodeJsonLine(event)
+		if err != nil {
+			return err
+		}
+		lines.Write(line)
+		lines.WriteString("\n")
+	}
+
+	file, err := os.OpenFile(eventStream.StoragePath+eventStream.FileName, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(lines.Bytes())
+	if err != nil {
+		// drop a partially written batch
+		file.Truncate(info.Size())
+		return err
+	}
+
+	return file.Sync()
+}
+
+func (eventStream *JsonLinesEventStream) lock() *streamLock {
+	return lockOfFile(eventStream.StoragePath + eventStream.FileName)
+}
+
+func (eventStream *JsonLinesEventStream) state() (int, string, error) {
+	version, lastEvent, err := eventStream.tail()
+	if err != nil || version == 0 {
+		return version, "", err
+	}
+
+	return version, lastOccurredAt([]Event{lastEvent}), nil
+}
+
+func (eventStream *JsonLinesEventStream) stage(events []Event) (stagedAppend, error) {
+	filePath := eventStream.StoragePath + eventStream.FileName
+	previous, existed, err := readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := bytes.NewBuffer(append([]byte{}, previous...))
+	if len(previous) > 0 && previous[len(previous)-1] != '\n' {
+		lines.WriteString("\n")
+	}
+	for _, event := range events {
+		line, err := encodeJsonLine(event)
+		if err != nil {
+			return nil, err
+		}
+		lines.Write(line)
+		lines.WriteString("\n")
+	}
+
+	return stageFile(filePath, previous, existed, lines.Bytes())
+}
+
+// Get fails when a line can't be decoded, as skipping it would hide events from the projections
+func (eventStream *JsonLinesEventStream) Get() ([]Event, error) {
+	lock := eventStream.lock()
+	err := lock.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	return eventStream.read()
+}
+
+func (eventStream *JsonLinesEventStream) Version() (int, error) {
+	events, err := eventStream.Get()
+
+	return len(events), err
+}
+
+// tail returns the version and the last event, it fails like Get when any line can't be decoded
+func (eventStream *JsonLinesEventStream) tail() (int, Event, error) {
+	events, err := eventStream.read()
+	if err != nil || len(events) == 0 {
+		return 0, Event{}, err
+	}
+
+	return len(events), events[len(events)-1], nil
+}
+
+func (eventStream *JsonLinesEventStream) read() ([]Event, error) {
+	events := []Event{}
+	var decodeErr error
+	err := eventStream.scan(func(number int, line []byte) {
+		if decodeErr != nil {
+			return
+		}
+		event, err := decodeJsonLine(line)
+		if err != nil {
+			decodeErr = NewCorruptEventStreamError(eventStream.FileName, number, err)
+			return
+		}
+		events = append(events, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return events, nil
+}
+
+func (eventStream *JsonLinesEventStream) scan(handleLine func(number int, line []byte)) error {
+	file, err := os.Open(eventStream.StoragePath + eventStream.FileName)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		handleLine(number, scanner.Bytes())
+	}
+
+	return scanner.Err()
+}
+
+// events are stored with the payloads of the event registry, which hold strings, booleans and integers only,
+// so numbers are read back as int and never have to be guessed
+func encodeJsonLine(event Event) ([]byte, error) {
+	err := checkValueTypes(event.Payload)
+	if err != nil {
+		return nil, err
+	}
+	err = checkValueTypes(event.MetaData)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonLine{event.Name, event.Payload, event.MetaData})
+}
+
+func decodeJsonLine(line []byte) (Event, error) {
+	decoded := jsonLine{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return Event{}, err
+	}
+
+	payload, err := typedValues(decoded.Payload)
+	if err != nil {
+		return Event{}, err
+	}
+	metaData, err := typedValues(decoded.MetaData)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{decoded.Name, payload, metaData}, nil
+}
+
+func checkValueTypes(values map[string]interface{}) error {
+	for key, value := range values {
+		switch value.(type) {
+		case string, bool, int:
+			continue
+		default:
+			return NewUnsupportedValueTypeError(key)
+		}
+	}
+
+	return nil
+}
+
+func typedValues(values map[string]interface{}) (map[string]interface{}, error) {
+	typed := map[string]interface{}{}
+	for key, value := range values {
+		number, isNumber := value.(json.Number)
+		if !isNumber {
+			typed[key] = value
+			continue
+		}
+
+		i, err := strconv.ParseInt(number.String(), 10, 0)
+		if err != nil {
+			return nil, err
+		}
+		typed[key] = int(i)
+	}
+
+	return typed, nil
+}
+
+func MigrateToJsonLines(from EventStream, to *JsonLinesEventStream) error {
+	events, err := from.Get()
+	if err != nil {
+		return err
+	}
+
+	lock := to.lock()
+	err = lock.acquire()
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	version, _, err := to.tail()
+	if err != nil {
+		return err
+	}
+	if version != 0 {
+		return NewEventStreamNotEmptyError(to.FileName)
+	}
+
+	lines := bytes.Buffer{}
+	for _, event := range events {
+		line, err := encodeJsonLine(event)
+		if err != nil {
+			return err
+		}
+		lines.Write(line)
+		lines.WriteString("\n")
+	}
+
+	return WriteAtomically(to.StoragePath+to.FileName, lines.Bytes())
+}
@@ -0,0 +1,189 @@
+package infrastructure_test
+
+import (
+	"os"
+	"reflect"
+	"stock-monitor/infrastructure"
+	"strings"
+	"testing"
+)
+
+func TestJsonLinesEventStreamStoresOneEventPerLine(t *testing.T) {
+	eventStream := setUpJsonLinesEventStream()
+
+	eventStream.Add(infrastructure.Event{
+		"EventName",
+		map[string]interface{}{"ticker": "MO", "shares": "10", "price": "9.99", "execution_day": 15},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 2},
+	})
+	eventStream.Add(infrastructure.Event{
+		"EventName2",
+		map[string]interface{}{"ticker": "PG"},
+		map[string]interface{}{"occurred_at": "2000-01-02"},
+	})
+
+	content, _ := os.ReadFile(tmpStorePath + tmpJsonLinesFile)
+	got := strings.Split(strings.TrimSpace(string(content)), "\n")
+	want := []string{
+		`{"name":"EventName","payload":{"execution_day":15,"price":"9.99","shares":"10","ticker":"MO"},"metadata":{"occurred_at":"2000-01-01","version":2}}`,
+		`{"name":"EventName2","payload":{"ticker":"PG"},"metadata":{"occurred_at":"2000-01-02"}}`,
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected file content. Expected:%#v Got:%#v", want, got)
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestJsonLinesEventStreamReadsNumbersAsIntegers(t *testing.T) {
+	eventStream := setUpJsonLinesEventStream()
+
+	eventStream.Add(infrastructure.Event{
+		"EventName",
+		map[string]interface{}{"int": 10, "string": "9.99", "bool": true},
+		map[string]interface{}{"occurred_at": "2000-01-01", "version": 3},
+	})
+
+	got, _ := eventStream.Get()
+	want := []infrastructure.Event{
+		{
+			"EventName",
+			map[string]interface{}{"int": 10, "string": "9.99", "bool": true},
+			map[string]interface{}{"occurred_at": "2000-01-01", "version": 3},
+		},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Event store state unequal. Expected:%#v Got:%#v", want, got)
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestJsonLinesEventStreamRejectsUnsupportedValueTypes(t *testing.T) {
+	for name, value := range map[string]interface{}{"float32": float32(9.99), "float64": 9.99, "map": map[string]string{}} {
+		t.Run(name, func(t *testing.T) {
+			eventStream := setUpJsonLinesEventStream()
+
+			err := eventStream.Add(infrastructure.Event{
+				"EventName",
+				map[string]interface{}{"foo": value},
+				map[string]interface{}{"occurred_at": "2000-01-01"},
+			})
+
+			_, ok := err.(*infrastructure.UnsupportedValueTypeError)
+			if !ok {
+				t.Errorf("Expected UnsupportedValueTypeError but got %#v", err)
+			}
+		})
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestJsonLinesFileNameReplacesTheExtensionOfTheConfiguredFile(t *testing.T) {
+	got := infrastructure.JsonLinesFileName("portfolio_event_stream_family.gob")
+
+	if got != "portfolio_event_stream_family.jsonl" {
+		t.Errorf("Unexpected file name. Expected:%#v Got:%#v", "portfolio_event_stream_family.jsonl", got)
+	}
+}
+
+func TestEventStreamCanBeMigratedToJsonLines(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			"EventName",
+			map[string]interface{}{"ticker": "MO", "shares": "10", "price": "9.99"},
+			map[string]interface{}{},
+		},
+		{
+			"EventName2",
+			map[string]interface{}{"ticker": "PG", "shares": "20", "price": "19.99", "plan_id": 1},
+			map[string]interface{}{"occurred_at": "2000-01-01"},
+		},
+	}
+	from := infrastructure.InMemoryEventStream{events}
+	to := setUpJsonLinesEventStream()
+
+	err := infrastructure.MigrateToJsonLines(&from, &to)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	got, _ := to.Get()
+	if reflect.DeepEqual(got, events) == false {
+		t.Errorf("Event store state unequal. Expected:%#v Got:%#v", events, got)
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestEventStreamCanNotBeMigratedIntoNonEmptyJsonLinesStream(t *testing.T) {
+	from := infrastructure.InMemoryEventStream{}
+	to := setUpJsonLinesEventStream()
+	to.Add(infrastructure.Event{
+		"EventName",
+		map[string]interface{}{},
+		map[string]interface{}{"occurred_at": "2000-01-01"},
+	})
+
+	err := infrastructure.MigrateToJsonLines(&from, &to)
+
+	_, ok := err.(*infrastructure.EventStreamNotEmptyError)
+	if !ok {
+		t.Errorf("Expected EventStreamNotEmptyError but got %#v", err)
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestJsonLinesEventStreamFailsOnLinesThatCanNotBeDecoded(t *testing.T) {
+	eventStream := setUpJsonLinesEventStream()
+	os.WriteFile(tmpStorePath+tmpJsonLinesFile, []byte(
+		`{"name":"EventName","payload":{},"metadata":{"occurred_at":"2000-01-01"}}`+"\n"+
+			`{"name":"EventName2",`+"\n"+
+			`{"name":"EventName3","payload":{},"metadata":{"occurred_at":"2000-01-03"}}`+"\n",
+	), 0644)
+
+	err := eventStream.AppendBatch([]infrastructure.Event{{"EventName4", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2000-01-04"}}}, 3)
+
+	_, ok := err.(*infrastructure.CorruptEventStreamError)
+	if !ok {
+		t.Errorf("Expected CorruptEventStreamError but got %#v", err)
+	}
+
+	_, err = eventStream.Get()
+
+	_, ok = err.(*infrastructure.CorruptEventStreamError)
+	if !ok {
+		t.Errorf("Expected CorruptEventStreamError from Get but got %#v", err)
+	}
+
+	_, err = eventStream.Version()
+
+	_, ok = err.(*infrastructure.CorruptEventStreamError)
+	if !ok {
+		t.Errorf("Expected CorruptEventStreamError from Version but got %#v", err)
+	}
+
+	t.Cleanup(func() {
+		cleanUpJsonLinesEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
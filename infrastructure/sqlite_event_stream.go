@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	stream      TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	occurred_at TEXT NOT NULL,
+	payload     BLOB NOT NULL,
+	meta_data   BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_stream_name ON events (stream, name);
+CREATE INDEX IF NOT EXISTS events_stream_occurred_at ON events (stream, occurred_at);
+`
+
+type SqliteEventStream struct {
+	db     *sql.DB
+	stream string
+}
+
+func OpenSqliteDatabase(filePath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", filePath)
+	if err != nil {
+		return nil, err
+	}
+	// sqlite only allows one writer at a time, sharing a single connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func NewSqliteEventStream(db *sql.DB, stream string) *SqliteEventStream {
+	return &SqliteEventStream{db: db, stream: stream}
+}
+
+func (eventStream *SqliteEventStream) Add(event Event) error {
+	return eventStream.AppendBatch([]Event{event}, AnyVersion)
+}
+
+func (eventStream *SqliteEventStream) AppendBatch(events []Event, expectedVersion int) error {
+	tx, err := eventStream.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = eventStream.appendInTransaction(tx, events, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (eventStream *SqliteEventStream) appendInTransaction(tx *sql.Tx, events []Event, expectedVersion int) error {
+	var version int
+	var lastOccurredAt sql.NullString
+	err := tx.QueryRow(
+		"SELECT COUNT(*), MAX(occurred_at) FROM events WHERE stream = ?",
+		eventStream.stream,
+	).Scan(&version, &lastOccurredAt)
+	if err != nil {
+		return err
+	}
+
+	if expectedVersion != AnyVersion && expectedVersion != version {
+		return NewConcurrencyConflictError(expectedVersion, version)
+	}
+
+	err = validateBatch(events, lastOccurredAt.String)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		payload, err := encodeGob(event.Payload)
+		if err != nil {
+			return err
+		}
+		metaData, err := encodeGob(event.MetaData)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			"INSERT INTO events (stream, name, occurred_at, payload, meta_data) VALUES (?, ?, ?, ?, ?)",
+			eventStream.stream,
+			event.Name,
+			event.MetaData["occurred_at"].(string),
+			payload,
+			metaData,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (eventStream *SqliteEventStream) Version() (int, error) {
+	var version int
+	err := eventStream.db.QueryRow("SELECT COUNT(*) FROM events WHERE stream = ?", eventStream.stream).Scan(&version)
+
+	return version, err
+}
+
+func (eventStream *SqliteEventStream) Get() ([]Event, error) {
+	return eventStream.query(
+		"SELECT name, payload, meta_data FROM events WHERE stream = ? ORDER BY id",
+		eventStream.stream,
+	)
+}
+
+func (eventStream *SqliteEventStream) GetByName(name string) ([]Event, error) {
+	return eventStream.query(
+		"SELECT name, payload, meta_data FROM events WHERE stream = ? AND name = ? ORDER BY id",
+		eventStream.stream,
+		name,
+	)
+}
+
+func (eventStream *SqliteEventStream) GetBetween(from string, to string) ([]Event, error) {
+	return eventStream.query(
+		"SELECT name, payload, meta_data FROM events WHERE stream = ? AND occurred_at >= ? AND occurred_at <= ? ORDER BY id",
+		eventStream.stream,
+		from,
+		to,
+	)
+}
+
+// a locked database or a row that can't be decoded fails the query instead of looking like a shorter stream
+func (eventStream *SqliteEventStream) query(query string, args ...interface{}) ([]Event, error) {
+	rows, err := eventStream.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var name string
+		var payload, metaData []byte
+		err = rows.Scan(&name, &payload, &metaData)
+		if err != nil {
+			return nil, err
+		}
+
+		event := Event{name, map[string]interface{}{}, map[string]interface{}{}}
+		err = decodeGob(payload, &event.Payload)
+		if err != nil {
+			return nil, err
+		}
+		err = decodeGob(metaData, &event.MetaData)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func encodeGob(object interface{}) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	err := gob.NewEncoder(&buffer).Encode(object)
+
+	return buffer.Bytes(), err
+}
+
+func decodeGob(data []byte, object interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(object)
+}
@@ -0,0 +1,159 @@
+package infrastructure_test
+
+import (
+	"reflect"
+	"stock-monitor/infrastructure"
+	"testing"
+)
+
+func TestSqliteEventStreamsAreSeparatedByStreamName(t *testing.T) {
+	portfolioEventStream := setUpSqliteEventStream()
+	dividendEventStream := infrastructure.NewSqliteEventStream(tmpDatabase, "other")
+
+	portfolioEventStream.Add(infrastructure.Event{
+		"EventName",
+		map[string]interface{}{"foo": "bar"},
+		map[string]interface{}{"occurred_at": "2000-01-02"},
+	})
+	err := dividendEventStream.Add(infrastructure.Event{
+		"OtherEventName",
+		map[string]interface{}{"foo": "buz"},
+		map[string]interface{}{"occurred_at": "2000-01-01"},
+	})
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	got, _ := dividendEventStream.Get()
+	want := []infrastructure.Event{
+		{
+			"OtherEventName",
+			map[string]interface{}{"foo": "buz"},
+			map[string]interface{}{"occurred_at": "2000-01-01"},
+		},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Event store state unequal. Expected:%#v Got:%#v", want, got)
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestSqliteEventStreamCanBeReadByEventName(t *testing.T) {
+	eventStream := setUpSqliteEventStream()
+
+	eventStream.Add(infrastructure.Event{
+		"EventName",
+		map[string]interface{}{"foo": "bar"},
+		map[string]interface{}{"occurred_at": "2000-01-01"},
+	})
+	eventStream.Add(infrastructure.Event{
+		"EventName2",
+		map[string]interface{}{"foo": "buz"},
+		map[string]interface{}{"occurred_at": "2000-01-02"},
+	})
+
+	got, _ := eventStream.GetByName("EventName2")
+	want := []infrastructure.Event{
+		{
+			"EventName2",
+			map[string]interface{}{"foo": "buz"},
+			map[string]interface{}{"occurred_at": "2000-01-02"},
+		},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Event store state unequal. Expected:%#v Got:%#v", want, got)
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestSqliteEventStreamCanBeReadByDateRange(t *testing.T) {
+	eventStream := setUpSqliteEventStream()
+
+	for _, date := range []string{"2000-01-01", "2000-01-02", "2000-01-03", "2000-01-04"} {
+		eventStream.Add(infrastructure.Event{
+			"EventName",
+			map[string]interface{}{"date": date},
+			map[string]interface{}{"occurred_at": date},
+		})
+	}
+
+	got, _ := eventStream.GetBetween("2000-01-02", "2000-01-03")
+	want := []infrastructure.Event{
+		{
+			"EventName",
+			map[string]interface{}{"date": "2000-01-02"},
+			map[string]interface{}{"occurred_at": "2000-01-02"},
+		},
+		{
+			"EventName",
+			map[string]interface{}{"date": "2000-01-03"},
+			map[string]interface{}{"occurred_at": "2000-01-03"},
+		},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Event store state unequal. Expected:%#v Got:%#v", want, got)
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestSqliteEventStreamFailsOnRowsThatCanNotBeDecoded(t *testing.T) {
+	eventStream := setUpSqliteEventStream()
+	tmpDatabase.Exec(
+		"INSERT INTO events (stream, name, occurred_at, payload, meta_data) VALUES (?, ?, ?, ?, ?)",
+		"test", "EventName", "2000-01-01", []byte("not a gob payload"), []byte("not gob meta data"),
+	)
+
+	_, err := eventStream.Get()
+
+	if err == nil {
+		t.Errorf("Expected an error from Get")
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
+
+func TestSqliteEventStreamFailsWhenTheDatabaseCanNotBeRead(t *testing.T) {
+	eventStream := setUpSqliteEventStream()
+	eventStream.Add(infrastructure.Event{
+		"EventName",
+		map[string]interface{}{"foo": "bar"},
+		map[string]interface{}{"occurred_at": "2000-01-01"},
+	})
+	tmpDatabase.Close()
+
+	_, err := eventStream.Version()
+
+	if err == nil {
+		t.Errorf("Expected an error from Version instead of an empty stream")
+	}
+
+	_, err = eventStream.Get()
+
+	if err == nil {
+		t.Errorf("Expected an error from Get instead of an empty stream")
+	}
+
+	t.Cleanup(func() {
+		cleanUpSqliteEventStream()
+		cleanUpFileSystemEventStream()
+	})
+}
@@ -0,0 +1,112 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// streamLock serializes the writers of one event stream, within the process by its mutex and, for streams
+// stored in a file, across processes by an exclusive lock on a lock file next to it
+type streamLock struct {
+	key      string
+	lockPath string
+	mutex    sync.Mutex
+	file     *os.File
+}
+
+var streamLocksMutex sync.Mutex
+var streamLocks = map[string]*streamLock{}
+
+// lockOfFile is the lock of the stream stored in the file, streams opened on the same path share it
+func lockOfFile(filePath string) *streamLock {
+	key, err := filepath.Abs(filePath)
+	if err != nil {
+		key = filepath.Clean(filePath)
+	}
+
+	return lockOf("file:"+key, filePath+".lock")
+}
+
+// lockOfMemory is the lock of an in memory stream, it only guards against writers of the same process
+func lockOfMemory(eventStream *InMemoryEventStream) *streamLock {
+	return lockOf(fmt.Sprintf("memory:%p", eventStream), "")
+}
+
+func lockOf(key string, lockPath string) *streamLock {
+	streamLocksMutex.Lock()
+	defer streamLocksMutex.Unlock()
+
+	lock, ok := streamLocks[key]
+	if !ok {
+		lock = &streamLock{key: key, lockPath: lockPath}
+		streamLocks[key] = lock
+	}
+
+	return lock
+}
+
+func (lock *streamLock) acquire() error {
+	lock.mutex.Lock()
+	if lock.lockPath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(lock.lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		lock.mutex.Unlock()
+		return err
+	}
+	err = lockFile(file)
+	if err != nil {
+		file.Close()
+		lock.mutex.Unlock()
+		return err
+	}
+	lock.file = file
+
+	return nil
+}
+
+func (lock *streamLock) release() {
+	if lock.file != nil {
+		unlockFile(lock.file)
+		lock.file.Close()
+		lock.file = nil
+	}
+	lock.mutex.Unlock()
+}
+
+// acquireAll takes the locks ordered by their key, so that two atomic appends never deadlock
+func acquireAll(locks []*streamLock) (func(), error) {
+	required := map[*streamLock]bool{}
+	ordered := []*streamLock{}
+	for _, lock := range locks {
+		if !required[lock] {
+			required[lock] = true
+			ordered = append(ordered, lock)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].key < ordered[j].key
+	})
+
+	acquired := []*streamLock{}
+	release := func() {
+		for index := len(acquired) - 1; index >= 0; index-- {
+			acquired[index].release()
+		}
+	}
+	for _, lock := range ordered {
+		err := lock.acquire()
+		if err != nil {
+			release()
+			return nil, err
+		}
+		acquired = append(acquired, lock)
+	}
+
+	return release, nil
+}
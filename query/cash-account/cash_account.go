@@ -0,0 +1,150 @@
+package cash_account
+
+import (
+	"sort"
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+)
+
+const (
+	Deposit    = "DEPOSIT"
+	Withdrawal = "WITHDRAWAL"
+	Buy        = "BUY"
+	Sell       = "SELL"
+	Dividend   = "DIVIDEND"
+	CashInLieu = "CASH_IN_LIEU"
+)
+
+type CashAccountQueryInterface interface {
+	GetCashAccount() (CashAccount, error)
+}
+
+// Amount is positive for money coming in and negative for money going out
+type Transaction struct {
+	Type                 string
+	Ticker               string
+	Date                 string
+	Amount               domain.Money
+	AmountInBaseCurrency domain.Money
+}
+
+type CashAccount struct {
+	Balances     []domain.Money
+	Total        domain.Money
+	Transactions []Transaction
+}
+
+type CashAccountQuery struct {
+	CashEventStream      infrastructure.EventStream
+	PortfolioEventStream infrastructure.EventStream
+	DividendEventStream  infrastructure.EventStream
+	ExchangeRates        query.ExchangeRateProvider
+	BaseCurrency         string
+}
+
+// balances are kept per currency, a transaction without exchange rate to the base currency fails the query with UnknownExchangeRateError.
+// A stored event that can't be decoded fails the query, the balances would be wrong without it.
+func (cashAccountQuery *CashAccountQuery) GetCashAccount() (CashAccount, error) {
+	transactions := []Transaction{}
+	for _, eventStream := range []infrastructure.EventStream{cashAccountQuery.CashEventStream, cashAccountQuery.PortfolioEventStream, cashAccountQuery.DividendEventStream} {
+		storedEvents, err := eventStream.Get()
+		if err != nil {
+			return CashAccount{}, err
+		}
+		for _, storedEvent := range storedEvents {
+			domainEvent, err := event.Decode(storedEvent)
+			if err != nil {
+				return CashAccount{}, err
+			}
+			transaction, ok := toTransaction(domainEvent)
+			if !ok {
+				continue
+			}
+			transaction.AmountInBaseCurrency, err = cashAccountQuery.inBaseCurrency(transaction.Amount)
+			if err != nil {
+				return CashAccount{}, err
+			}
+			transactions = append(transactions, transaction)
+		}
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date < transactions[j].Date
+	})
+
+	balances := map[string]domain.Money{}
+	total := domain.NewMoneyFromFloat(0, cashAccountQuery.BaseCurrency)
+	for _, transaction := range transactions {
+		balance, found := balances[transaction.Amount.Currency()]
+		if !found {
+			balance = domain.NewMoneyFromFloat(0, transaction.Amount.Currency())
+		}
+		balances[transaction.Amount.Currency()], _ = balance.Add(transaction.Amount)
+
+		var err error
+		total, err = total.Add(transaction.AmountInBaseCurrency)
+		if err != nil {
+			return CashAccount{}, err
+		}
+	}
+
+	return CashAccount{sortedBalances(balances), total, transactions}, nil
+}
+
+func toTransaction(domainEvent domain.DomainEvent) (Transaction, bool) {
+	switch domainEvent := domainEvent.(type) {
+	case *cash.CashDepositedEvent:
+		return Transaction{Deposit, "", domainEvent.Date(), domainEvent.Amount(), domain.Money{}}, true
+	case *cash.CashWithdrawnEvent:
+		return Transaction{Withdrawal, "", domainEvent.Date(), negate(domainEvent.Amount()), domain.Money{}}, true
+	case *portfolio.SharesAddedToPortfolioEvent:
+		cost := cash.BuyCost(domainEvent.Price(), domainEvent.Shares(), domainEvent.Fee(), domainEvent.Taxes())
+		return Transaction{Buy, domainEvent.Ticker(), domainEvent.Date(), negate(cost), domain.Money{}}, true
+	case *portfolio.SharesRemovedFromPortfolioEvent:
+		proceeds := cash.SaleProceeds(domainEvent.Price(), domainEvent.Shares(), domainEvent.Fee(), domainEvent.Taxes())
+		return Transaction{Sell, domainEvent.Ticker(), domainEvent.Date(), proceeds, domain.Money{}}, true
+	case *portfolio.SharesConvertedEvent:
+		if !domainEvent.CashInLieu().IsPositive() {
+			return Transaction{}, false
+		}
+		return Transaction{CashInLieu, domainEvent.NewTicker(), domainEvent.Date(), domainEvent.CashInLieu(), domain.Money{}}, true
+	case *dividend.DividendRecordedEvent:
+		return Transaction{Dividend, domainEvent.Ticker(), domainEvent.Date(), domainEvent.Net(), domain.Money{}}, true
+	}
+
+	return Transaction{}, false
+}
+
+func sortedBalances(balances map[string]domain.Money) []domain.Money {
+	currencies := []string{}
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	sorted := []domain.Money{}
+	for _, currency := range currencies {
+		sorted = append(sorted, balances[currency])
+	}
+
+	return sorted
+}
+
+func negate(amount domain.Money) domain.Money {
+	negated, _ := domain.NewMoneyFromFloat(0, amount.Currency()).Sub(amount)
+
+	return negated
+}
+
+func (cashAccountQuery *CashAccountQuery) inBaseCurrency(money domain.Money) (domain.Money, error) {
+	rate, err := cashAccountQuery.ExchangeRates.Rate(money.Currency(), cashAccountQuery.BaseCurrency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return rate.Convert(money)
+}
@@ -0,0 +1,119 @@
+package cash_account_test
+
+import (
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	cash_account "stock-monitor/query/cash-account"
+	"testing"
+)
+
+func makeCashAccountQuery() cash_account.CashAccountQuery {
+	cashEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "1000", "currency": "EUR", "date": "2001-01-01"},
+				map[string]interface{}{"occurred_at": "2001-01-01", "version": 1},
+			},
+			{
+				cash.CashDepositedEventName,
+				map[string]interface{}{"amount": "500", "currency": "USD", "date": "2001-01-01"},
+				map[string]interface{}{"occurred_at": "2001-01-01", "version": 1},
+			},
+			{
+				cash.CashWithdrawnEventName,
+				map[string]interface{}{"amount": "100", "currency": "EUR", "date": "2001-03-01"},
+				map[string]interface{}{"occurred_at": "2001-03-01", "version": 1},
+			},
+		},
+	}
+	portfolioEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "10", "price": "50", "fee": "5", "taxes": "0", "currency": "EUR", "date": "2001-01-02"},
+				map[string]interface{}{"occurred_at": "2001-01-02", "version": 4},
+			},
+			{
+				portfolio.SharesRemovedFromPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "5", "price": "60", "fee": "5", "taxes": "5", "currency": "EUR", "date": "2001-04-01", "lots": ""},
+				map[string]interface{}{"occurred_at": "2001-04-01", "version": 6},
+			},
+		},
+	}
+	dividendEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				dividend.DividendRecordedEventName,
+				map[string]interface{}{"ticker": "MO", "net": "7.5", "gross": "10", "currency": "EUR", "date": "2001-02-01"},
+				map[string]interface{}{"occurred_at": "2001-02-01", "version": 2},
+			},
+		},
+	}
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+
+	return cash_account.CashAccountQuery{&cashEventStream, &portfolioEventStream, &dividendEventStream, exchangeRates, "EUR"}
+}
+
+func TestCashAccountHasBalancePerCurrencyAndTotalInBaseCurrency(t *testing.T) {
+	cashAccountQuery := makeCashAccountQuery()
+
+	got, _ := cashAccountQuery.GetCashAccount()
+
+	if len(got.Balances) != 2 || got.Balances[0].String() != "692.5 EUR" || got.Balances[1].String() != "500 USD" {
+		t.Errorf("Unexpected balances: %#v", got.Balances)
+	}
+	if got.Total.String() != "1092.5 EUR" {
+		t.Errorf("Unexpected total: %#v", got.Total.String())
+	}
+}
+
+func TestCashAccountListsTransactionsByDate(t *testing.T) {
+	cashAccountQuery := makeCashAccountQuery()
+
+	got, _ := cashAccountQuery.GetCashAccount()
+
+	want := []struct {
+		transactionType string
+		date            string
+		amount          string
+	}{
+		{cash_account.Deposit, "2001-01-01", "1000 EUR"},
+		{cash_account.Deposit, "2001-01-01", "500 USD"},
+		{cash_account.Buy, "2001-01-02", "-505 EUR"},
+		{cash_account.Dividend, "2001-02-01", "7.5 EUR"},
+		{cash_account.Withdrawal, "2001-03-01", "-100 EUR"},
+		{cash_account.Sell, "2001-04-01", "290 EUR"},
+	}
+	if len(got.Transactions) != len(want) {
+		t.Fatalf("Unexpected transactions: %#v", got.Transactions)
+	}
+	for index, transaction := range got.Transactions {
+		if transaction.Type != want[index].transactionType || transaction.Date != want[index].date || transaction.Amount.String() != want[index].amount {
+			t.Errorf("Unexpected transaction. Expected:%#v Got:%#v", want[index], transaction)
+		}
+	}
+}
+
+func TestCashAccountFailsWhenAnEventOfAnyStreamCanNotBeDecoded(t *testing.T) {
+	for name, unknown := range map[string]infrastructure.Event{
+		"cash":      {"Cash.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}},
+		"portfolio": {"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}},
+		"dividend":  {"Dividend.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cashAccountQuery := makeCashAccountQuery()
+			eventStreams := map[string]infrastructure.EventStream{"cash": cashAccountQuery.CashEventStream, "portfolio": cashAccountQuery.PortfolioEventStream, "dividend": cashAccountQuery.DividendEventStream}
+			eventStreams[name].(*infrastructure.InMemoryEventStream).Events = append(eventStreams[name].(*infrastructure.InMemoryEventStream).Events, unknown)
+
+			_, err := cashAccountQuery.GetCashAccount()
+
+			if err == nil {
+				t.Errorf("Expected cash account to fail for an event that can't be decoded")
+			}
+		})
+	}
+}
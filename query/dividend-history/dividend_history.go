@@ -1,27 +1,34 @@
 package dividend_history
 
 import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
 	"stock-monitor/domain/dividend"
 	"stock-monitor/infrastructure"
+	"stock-monitor/query"
 	"time"
 )
 
 type DividendHistoryQueryInterface interface {
-	GetDividends(filter Filter) []Dividend
-	GetSum(filter Filter) float32
+	GetDividends(filter Filter) ([]Dividend, error)
+	GetSum(filter Filter) (domain.Money, error)
 }
 
 type Dividend struct {
-	Ticker string
-	Net    float32
-	Gross  float32
-	Date   string
+	Ticker              string
+	Net                 domain.Money
+	Gross               domain.Money
+	Date                string
+	NetInBaseCurrency   domain.Money
+	GrossInBaseCurrency domain.Money
 }
 
 type DividendHistoryQuery struct {
-	EventStream  infrastructure.EventStream
-	yearFilter   int
-	tickerFilter string
+	EventStream   infrastructure.EventStream
+	ExchangeRates query.ExchangeRateProvider
+	BaseCurrency  string
+	yearFilter    int
+	tickerFilter  string
 }
 
 type Filter struct {
@@ -41,63 +48,98 @@ func (filter *Filter) ByTicker(ticker string) {
 	filter.ticker = ticker
 }
 
-func NewDividendHistoryQuery(eventStream infrastructure.EventStream) DividendHistoryQuery {
-	return DividendHistoryQuery{eventStream, 0, ""}
+func NewDividendHistoryQuery(eventStream infrastructure.EventStream, exchangeRates query.ExchangeRateProvider, baseCurrency string) DividendHistoryQuery {
+	return DividendHistoryQuery{eventStream, exchangeRates, baseCurrency, 0, ""}
 }
 
-func (dividendHistoryQuery *DividendHistoryQuery) GetDividends(filter Filter) []Dividend {
-	dividends := []Dividend{}
-
-	for _, event := range dividendHistoryQuery.EventStream.Get() {
-		if event.Name == dividend.DividendRecordedEventName {
-			if !dividendMatchesYearFilter(event.Payload["date"].(string), filter) {
-				continue
-			}
-			ticker := event.Payload["ticker"].(string)
-			if !dividendMatchesTickerFilter(ticker, filter) {
-				continue
-			}
-			net := getFloatValue(event.Payload["net"])
-			gross := getFloatValue(event.Payload["gross"])
-			date := event.Payload["date"].(string)
-			d := Dividend{ticker, net, gross, date}
-			dividends = append(dividends, d)
+func (dividendHistoryQuery *DividendHistoryQuery) GetDividends(filter Filter) ([]Dividend, error) {
+	recordedEvents, err := dividendHistoryQuery.getMatchingDividendEvents(filter)
+	if err != nil {
+		return nil, err
+	}
 
-			continue
+	dividends := []Dividend{}
+	for _, recordedEvent := range recordedEvents {
+		netInBaseCurrency, err := dividendHistoryQuery.inBaseCurrency(recordedEvent.Net())
+		if err != nil {
+			return nil, err
+		}
+		grossInBaseCurrency, err := dividendHistoryQuery.inBaseCurrency(recordedEvent.Gross())
+		if err != nil {
+			return nil, err
 		}
+		d := Dividend{
+			recordedEvent.Ticker(),
+			recordedEvent.Net(),
+			recordedEvent.Gross(),
+			recordedEvent.Date(),
+			netInBaseCurrency,
+			grossInBaseCurrency,
+		}
+		dividends = append(dividends, d)
 	}
 
-	return dividends
+	return dividends, nil
 }
 
-func (dividendHistoryQuery *DividendHistoryQuery) GetSum(filter Filter) float32 {
-	dividends := float32(0.0)
-
-	for _, event := range dividendHistoryQuery.EventStream.Get() {
-		if event.Name == dividend.DividendRecordedEventName {
-			if !dividendMatchesYearFilter(event.Payload["date"].(string), filter) {
-				continue
-			}
-			ticker := event.Payload["ticker"].(string)
-			if !dividendMatchesTickerFilter(ticker, filter) {
-				continue
-			}
-			dividends += getFloatValue(event.Payload["net"])
+// a dividend without exchange rate to the base currency fails the sum with UnknownExchangeRateError
+func (dividendHistoryQuery *DividendHistoryQuery) GetSum(filter Filter) (domain.Money, error) {
+	recordedEvents, err := dividendHistoryQuery.getMatchingDividendEvents(filter)
+	if err != nil {
+		return domain.Money{}, err
+	}
 
-			continue
+	dividends := domain.NewMoneyFromFloat(0, dividendHistoryQuery.BaseCurrency)
+	for _, recordedEvent := range recordedEvents {
+		net, err := dividendHistoryQuery.inBaseCurrency(recordedEvent.Net())
+		if err != nil {
+			return domain.Money{}, err
+		}
+		dividends, err = dividends.Add(net)
+		if err != nil {
+			return domain.Money{}, err
 		}
 	}
 
-	return dividends
+	return dividends, nil
+}
+
+func (dividendHistoryQuery *DividendHistoryQuery) inBaseCurrency(money domain.Money) (domain.Money, error) {
+	rate, err := dividendHistoryQuery.ExchangeRates.Rate(money.Currency(), dividendHistoryQuery.BaseCurrency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return rate.Convert(money)
 }
 
-func getFloatValue(value interface{}) float32 {
-	floatValue, ok := value.(float32)
-	if !ok {
-		floatValue = float32(value.(float64))
+// a stored event that can't be decoded fails the query, its dividend would be missing from the sum
+func (dividendHistoryQuery *DividendHistoryQuery) getMatchingDividendEvents(filter Filter) ([]*dividend.DividendRecordedEvent, error) {
+	recordedEvents := []*dividend.DividendRecordedEvent{}
+
+	storedEvents, err := dividendHistoryQuery.EventStream.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return nil, err
+		}
+		recordedEvent, ok := domainEvent.(*dividend.DividendRecordedEvent)
+		if !ok {
+			continue
+		}
+		if !dividendMatchesYearFilter(recordedEvent.Date(), filter) {
+			continue
+		}
+		if !dividendMatchesTickerFilter(recordedEvent.Ticker(), filter) {
+			continue
+		}
+		recordedEvents = append(recordedEvents, recordedEvent)
 	}
 
-	return floatValue
+	return recordedEvents, nil
 }
 
 func dividendMatchesYearFilter(date string, filter Filter) bool {
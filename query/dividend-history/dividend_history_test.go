@@ -2,8 +2,10 @@ package dividend_history_test
 
 import (
 	"reflect"
+	"stock-monitor/domain"
 	"stock-monitor/domain/dividend"
 	"stock-monitor/infrastructure"
+	"stock-monitor/query"
 	"stock-monitor/query/dividend-history"
 	"testing"
 )
@@ -27,12 +29,12 @@ func TestDividendHistoryProvidesAllDividends(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
-	got := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
+	got, _ := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
 	want := []dividend_history.Dividend{
-		{"MO", 12.34, 23.45, "2001-01-02"},
-		{"PG", 12.34, 23.45, "2001-01-02"},
-		{"MCD", 12.34, 23.45, "2001-01-02"},
+		{"MO", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
+		{"PG", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
+		{"MCD", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -49,10 +51,10 @@ func TestDividendHistoryCanHandleFloat32Values(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
-	got := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
+	got, _ := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
 	want := []dividend_history.Dividend{
-		{"MO", 12.34, 23.45, "2001-01-02"},
+		{"MO", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -79,14 +81,14 @@ func TestDividendHistoryCanBeFilteredByYear(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
 	filter := dividend_history.NewFilter()
 	filter.ByYear(2001)
 
-	got := dividendHistoryQuery.GetDividends(filter)
+	got, _ := dividendHistoryQuery.GetDividends(filter)
 	want := []dividend_history.Dividend{
-		{"MO", 12.34, 23.45, "2001-01-02"},
-		{"PG", 12.34, 23.45, "2001-01-02"},
+		{"MO", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
+		{"PG", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -113,13 +115,13 @@ func TestDividendHistoryCanBeFilteredByTicker(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
 	filter := dividend_history.NewFilter()
 	filter.ByTicker("PG")
 
-	got := dividendHistoryQuery.GetDividends(filter)
+	got, _ := dividendHistoryQuery.GetDividends(filter)
 	want := []dividend_history.Dividend{
-		{"PG", 12.34, 23.45, "2001-01-02"},
+		{"PG", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -151,15 +153,15 @@ func TestDividendHistoryCanBeFilteredByTickerAndYear(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
 	filter := dividend_history.NewFilter()
 	filter.ByTicker("PG")
 	filter.ByYear(2001)
 
-	got := dividendHistoryQuery.GetDividends(filter)
+	got, _ := dividendHistoryQuery.GetDividends(filter)
 	want := []dividend_history.Dividend{
-		{"PG", 12.34, 23.45, "2001-01-02"},
-		{"PG", 12.34, 23.45, "2001-02-02"},
+		{"PG", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
+		{"PG", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR"), "2001-02-02", domain.NewMoneyFromFloat(12.34, "EUR"), domain.NewMoneyFromFloat(23.45, "EUR")},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -186,11 +188,11 @@ func TestDividendHistoryProvidesSumOfDividendsInNet(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
-	got := dividendHistoryQuery.GetSum(dividend_history.NewFilter())
-	want := float32(60.06)
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
+	got, _ := dividendHistoryQuery.GetSum(dividend_history.NewFilter())
+	want := domain.NewMoneyFromFloat(60.06, "EUR")
 
-	if got != want {
+	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Dividend sum not matching: %#v, want: %#v", got, want)
 	}
 }
@@ -214,14 +216,14 @@ func TestDividendHistorySumCanBeFilteredByYear(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
 	filter := dividend_history.NewFilter()
 	filter.ByYear(2001)
 
-	got := dividendHistoryQuery.GetSum(filter)
-	want := float32(30.03)
+	got, _ := dividendHistoryQuery.GetSum(filter)
+	want := domain.NewMoneyFromFloat(30.03, "EUR")
 
-	if got != want {
+	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Dividend sum not matching: %#v, want: %#v", got, want)
 	}
 }
@@ -245,14 +247,14 @@ func TestDividendHistorySumCanBeFilteredByTicker(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
 	filter := dividend_history.NewFilter()
 	filter.ByTicker("PG")
 
-	got := dividendHistoryQuery.GetSum(filter)
-	want := float32(20.02)
+	got, _ := dividendHistoryQuery.GetSum(filter)
+	want := domain.NewMoneyFromFloat(20.02, "EUR")
 
-	if got != want {
+	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Dividend sum not matching: %#v, want: %#v", got, want)
 	}
 }
@@ -281,15 +283,87 @@ func TestDividendHistorySumCanBeFilteredByTickerAndYear(t *testing.T) {
 		},
 	}
 
-	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events})
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR")
 	filter := dividend_history.NewFilter()
 	filter.ByTicker("PG")
 	filter.ByYear(2001)
 
-	got := dividendHistoryQuery.GetSum(filter)
-	want := float32(51.04)
+	got, _ := dividendHistoryQuery.GetSum(filter)
+	want := domain.NewMoneyFromFloat(51.04, "EUR")
 
-	if got != want {
+	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Dividend sum not matching: %#v, want: %#v", got, want)
 	}
 }
+
+func TestDividendsAreReportedInBaseCurrency(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			dividend.DividendRecordedEventName,
+			map[string]interface{}{"ticker": "MO", "net": "10", "gross": "12.5", "currency": "USD", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 2},
+		},
+		{
+			dividend.DividendRecordedEventName,
+			map[string]interface{}{"ticker": "SAP", "net": "20", "gross": "25", "currency": "EUR", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 2},
+		},
+	}
+
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, exchangeRates, "EUR")
+	got, _ := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
+	want := []dividend_history.Dividend{
+		{"MO", domain.NewMoneyFromFloat(10, "USD"), domain.NewMoneyFromFloat(12.5, "USD"), "2001-01-02", domain.NewMoneyFromFloat(8, "EUR"), domain.NewMoneyFromFloat(10, "EUR")},
+		{"SAP", domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(25, "EUR"), "2001-01-02", domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(25, "EUR")},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Dividends unequal got: %#v, want: %#v", got, want)
+	}
+
+	gotSum, _ := dividendHistoryQuery.GetSum(dividend_history.NewFilter())
+	wantSum := domain.NewMoneyFromFloat(28, "EUR")
+
+	if reflect.DeepEqual(gotSum, wantSum) == false {
+		t.Errorf("Dividend sum not matching: %#v, want: %#v", gotSum.String(), wantSum.String())
+	}
+}
+
+func TestDividendHistoryFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{[]infrastructure.Event{{"Dividend.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}}, query.FakeExchangeRateProvider{}, "EUR")
+
+	_, err := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
+	_, sumErr := dividendHistoryQuery.GetSum(dividend_history.NewFilter())
+
+	if err == nil || sumErr == nil {
+		t.Errorf("Expected dividends and sum to fail for an event that can't be decoded")
+	}
+}
+
+func TestDividendHistoryFailsWithoutExchangeRateToBaseCurrency(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			dividend.DividendRecordedEventName,
+			map[string]interface{}{"ticker": "VOD", "net": "10", "gross": "12.5", "currency": "GBP", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 2},
+		},
+	}
+
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+	dividendHistoryQuery := dividend_history.NewDividendHistoryQuery(&infrastructure.InMemoryEventStream{events}, exchangeRates, "EUR")
+
+	_, err := dividendHistoryQuery.GetDividends(dividend_history.NewFilter())
+
+	_, ok := err.(*query.UnknownExchangeRateError)
+	if !ok {
+		t.Errorf("Expected UnknownExchangeRateError from the dividends but got %#v", err)
+	}
+
+	_, err = dividendHistoryQuery.GetSum(dividend_history.NewFilter())
+
+	_, ok = err.(*query.UnknownExchangeRateError)
+	if !ok {
+		t.Errorf("Expected UnknownExchangeRateError from the sum but got %#v", err)
+	}
+}
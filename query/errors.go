@@ -0,0 +1,54 @@
+package query
+
+import "strconv"
+
+type UnknownExchangeRateError struct {
+	from string
+	to   string
+}
+
+type UnknownPriceError struct {
+	ticker string
+	date   string
+}
+
+type InvalidPriceCsvError struct {
+	line   int
+	reason string
+}
+
+type UnreadableEventStreamError struct {
+	err error
+}
+
+func NewUnknownExchangeRateError(from string, to string) *UnknownExchangeRateError {
+	return &UnknownExchangeRateError{from: from, to: to}
+}
+
+func NewUnknownPriceError(ticker string, date string) *UnknownPriceError {
+	return &UnknownPriceError{ticker: ticker, date: date}
+}
+
+func NewInvalidPriceCsvError(line int, reason string) *InvalidPriceCsvError {
+	return &InvalidPriceCsvError{line: line, reason: reason}
+}
+
+func NewUnreadableEventStreamError(err error) *UnreadableEventStreamError {
+	return &UnreadableEventStreamError{err: err}
+}
+
+func (e *UnknownExchangeRateError) Error() string {
+	return "no exchange rate found. from: " + e.from + " to: " + e.to
+}
+
+func (e *UnknownPriceError) Error() string {
+	return "no price found. ticker: " + e.ticker + " date: " + e.date
+}
+
+func (e *InvalidPriceCsvError) Error() string {
+	return "invalid price csv. line: " + strconv.Itoa(e.line) + " " + e.reason
+}
+
+func (e *UnreadableEventStreamError) Error() string {
+	return "event stream can't be read. " + e.err.Error()
+}
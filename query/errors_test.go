@@ -0,0 +1,51 @@
+package query_test
+
+import (
+	"errors"
+	"stock-monitor/query"
+	"testing"
+)
+
+func TestUnknownExchangeRateError(t *testing.T) {
+	err := query.NewUnknownExchangeRateError("USD", "EUR")
+
+	expected := "no exchange rate found. from: USD to: EUR"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestUnknownPriceError(t *testing.T) {
+	err := query.NewUnknownPriceError("MO", "2000-01-01")
+
+	expected := "no price found. ticker: MO date: 2000-01-01"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestInvalidPriceCsvError(t *testing.T) {
+	err := query.NewInvalidPriceCsvError(3, "invalid date: foo")
+
+	expected := "invalid price csv. line: 3 invalid date: foo"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
+
+func TestUnreadableEventStreamError(t *testing.T) {
+	err := query.NewUnreadableEventStreamError(errors.New("Error text"))
+
+	expected := "event stream can't be read. Error text"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
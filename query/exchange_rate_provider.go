@@ -0,0 +1,70 @@
+package query
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"stock-monitor/domain"
+)
+
+type ExchangeRateProvider interface {
+	Rate(from string, to string) (domain.ExchangeRate, error)
+}
+
+// rates are quoted against one reference currency, e.g. {"EUR": "1", "USD": "1.08"}
+type FakeExchangeRateProvider struct {
+	RateMap map[string]string
+}
+
+func (provider FakeExchangeRateProvider) Rate(from string, to string) (domain.ExchangeRate, error) {
+	return crossRate(provider.RateMap, from, to)
+}
+
+// reads rates quoted against one reference currency from a json file, e.g. {"EUR": 1, "USD": 1.08}
+type FileExchangeRateProvider struct {
+	path string
+}
+
+func NewFileExchangeRateProvider(path string) FileExchangeRateProvider {
+	return FileExchangeRateProvider{path: path}
+}
+
+func (provider FileExchangeRateProvider) Rate(from string, to string) (domain.ExchangeRate, error) {
+	if from == to {
+		return domain.NewExchangeRate(from, to, "1")
+	}
+
+	content, err := ioutil.ReadFile(provider.path)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+
+	storedRates := map[string]json.Number{}
+	err = json.Unmarshal(content, &storedRates)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+
+	rates := map[string]string{}
+	for currency, rate := range storedRates {
+		rates[currency] = rate.String()
+	}
+
+	return crossRate(rates, from, to)
+}
+
+func crossRate(rates map[string]string, from string, to string) (domain.ExchangeRate, error) {
+	if from == to {
+		return domain.NewExchangeRate(from, to, "1")
+	}
+
+	fromRate, found := rates[from]
+	if !found {
+		return domain.ExchangeRate{}, NewUnknownExchangeRateError(from, to)
+	}
+	toRate, found := rates[to]
+	if !found {
+		return domain.ExchangeRate{}, NewUnknownExchangeRateError(from, to)
+	}
+
+	return domain.NewCrossExchangeRate(from, to, fromRate, toRate)
+}
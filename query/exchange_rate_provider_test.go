@@ -0,0 +1,47 @@
+package query_test
+
+import (
+	"io/ioutil"
+	"os"
+	"stock-monitor/query"
+	"testing"
+)
+
+func TestFileExchangeRateProviderReadsRatesFromFile(t *testing.T) {
+	file, _ := ioutil.TempFile("", "exchange_rates_*.json")
+	defer os.Remove(file.Name())
+	file.WriteString(`{"EUR": 1, "USD": 1.25, "GBP": "0.85"}`)
+	file.Close()
+
+	provider := query.NewFileExchangeRateProvider(file.Name())
+	rate, err := provider.Rate("USD", "EUR")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if rate.Rate() != "0.8" {
+		t.Errorf("Unexpected rate. Expected:%#v Got:%#v", "0.8", rate.Rate())
+	}
+}
+
+func TestSameCurrencyNeedsNoExchangeRate(t *testing.T) {
+	provider := query.NewFileExchangeRateProvider("does-not-exist.json")
+	rate, err := provider.Rate("EUR", "EUR")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if rate.Rate() != "1" {
+		t.Errorf("Unexpected rate. Expected:%#v Got:%#v", "1", rate.Rate())
+	}
+}
+
+func TestUnknownCurrenciesHaveNoExchangeRate(t *testing.T) {
+	provider := query.FakeExchangeRateProvider{map[string]string{"EUR": "1"}}
+	_, err := provider.Rate("USD", "EUR")
+
+	_, ok := err.(*query.UnknownExchangeRateError)
+	if !ok {
+		t.Errorf("Expected UnknownExchangeRateError but got %#v", err)
+	}
+}
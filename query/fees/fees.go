@@ -0,0 +1,95 @@
+package fees
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/query"
+	orderHistory "stock-monitor/query/order-history"
+	"time"
+)
+
+type FeesQueryInterface interface {
+	GetFees(year int) (Fees, error)
+}
+
+type OrderFees struct {
+	OrderType           string
+	Ticker              string
+	Aliases             []string
+	Date                string
+	Fee                 domain.Money
+	Taxes               domain.Money
+	FeeInBaseCurrency   domain.Money
+	TaxesInBaseCurrency domain.Money
+}
+
+type Fees struct {
+	Orders     []OrderFees
+	TotalFees  domain.Money
+	TotalTaxes domain.Money
+}
+
+type FeesQuery struct {
+	Orders        orderHistory.OrderHistoryQueryInterface
+	ExchangeRates query.ExchangeRateProvider
+	BaseCurrency  string
+}
+
+// year 0 selects all orders, an amount without exchange rate to the base currency fails the query with UnknownExchangeRateError
+func (feesQuery *FeesQuery) GetFees(year int) (Fees, error) {
+	fees := Fees{[]OrderFees{}, domain.NewMoneyFromFloat(0, feesQuery.BaseCurrency), domain.NewMoneyFromFloat(0, feesQuery.BaseCurrency)}
+
+	orders, err := feesQuery.Orders.GetOrders()
+	if err != nil {
+		return Fees{}, err
+	}
+
+	for _, order := range orders {
+		if order.Fee.IsZero() && order.Taxes.IsZero() {
+			continue
+		}
+		orderDate, _ := time.Parse("2006-01-02", order.Date)
+		if year != 0 && orderDate.Year() != year {
+			continue
+		}
+
+		feeInBaseCurrency, err := feesQuery.inBaseCurrency(order.Fee)
+		if err != nil {
+			return Fees{}, err
+		}
+		taxesInBaseCurrency, err := feesQuery.inBaseCurrency(order.Taxes)
+		if err != nil {
+			return Fees{}, err
+		}
+		orderFees := OrderFees{
+			order.OrderType,
+			order.Ticker,
+			order.Aliases,
+			order.Date,
+			order.Fee,
+			order.Taxes,
+			feeInBaseCurrency,
+			taxesInBaseCurrency,
+		}
+		fees.Orders = append(fees.Orders, orderFees)
+
+		fees.TotalFees, err = fees.TotalFees.Add(feeInBaseCurrency)
+		if err != nil {
+			return Fees{}, err
+		}
+		fees.TotalTaxes, err = fees.TotalTaxes.Add(taxesInBaseCurrency)
+		if err != nil {
+			return Fees{}, err
+		}
+	}
+
+	return fees, nil
+}
+
+func (feesQuery *FeesQuery) inBaseCurrency(money domain.Money) (domain.Money, error) {
+	rate, err := feesQuery.ExchangeRates.Rate(money.Currency(), feesQuery.BaseCurrency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return rate.Convert(money)
+}
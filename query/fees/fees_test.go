@@ -0,0 +1,74 @@
+package fees_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/fees"
+	orderHistory "stock-monitor/query/order-history"
+	"testing"
+)
+
+func events() []infrastructure.Event {
+	return []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "10", "fee": "5", "taxes": "0", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 4},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "KO", "price": "50", "fee": "0", "taxes": "0", "currency": "USD", "shares": "4", "date": "2002-02-01"},
+			map[string]interface{}{"occurred_at": "2002-02-01", "version": 4},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "KO", "price": "75", "fee": "2.5", "taxes": "10", "currency": "USD", "shares": "4", "date": "2002-03-01", "lots": ""},
+			map[string]interface{}{"occurred_at": "2002-03-01", "version": 6},
+		},
+	}
+}
+
+func TestFeesAreListedPerOrderAndTotaledInBaseCurrency(t *testing.T) {
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+	feesQuery := fees.FeesQuery{&orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events()}}, exchangeRates, "EUR"}
+
+	got, _ := feesQuery.GetFees(0)
+	want := fees.Fees{
+		[]fees.OrderFees{
+			{"BUY", "MO", []string{}, "2001-01-01", domain.NewMoneyFromFloat(5, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(5, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+			{"SELL", "KO", []string{}, "2002-03-01", domain.NewMoneyFromFloat(2.5, "USD"), domain.NewMoneyFromFloat(10, "USD"), domain.NewMoneyFromFloat(2, "EUR"), domain.NewMoneyFromFloat(8, "EUR")},
+		},
+		domain.NewMoneyFromFloat(7, "EUR"),
+		domain.NewMoneyFromFloat(8, "EUR"),
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Fees unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestFeesCanBeFilteredByYear(t *testing.T) {
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+	feesQuery := fees.FeesQuery{&orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events()}}, exchangeRates, "EUR"}
+
+	got, _ := feesQuery.GetFees(2001)
+
+	if len(got.Orders) != 1 || got.Orders[0].Ticker != "MO" {
+		t.Errorf("Expected only the fees of MO but got %#v", got.Orders)
+	}
+	if reflect.DeepEqual(got.TotalFees, domain.NewMoneyFromFloat(5, "EUR")) == false {
+		t.Errorf("Unexpected total fees: %#v", got.TotalFees.String())
+	}
+}
+
+func TestFeesFailWhenAnEventCanNotBeDecoded(t *testing.T) {
+	feesQuery := fees.FeesQuery{&orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{append(events(), infrastructure.Event{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}})}}, query.FakeExchangeRateProvider{}, "EUR"}
+	_, err := feesQuery.GetFees(0)
+
+	if err == nil {
+		t.Errorf("Expected fees to fail for an event that can't be decoded")
+	}
+}
@@ -0,0 +1,13 @@
+package lots
+
+type UnknownMatchingMethodError struct {
+	method string
+}
+
+func NewUnknownMatchingMethodError(method string) *UnknownMatchingMethodError {
+	return &UnknownMatchingMethodError{method: method}
+}
+
+func (e *UnknownMatchingMethodError) Error() string {
+	return "unknown lot matching method. method: " + e.method + " (use fifo, lifo or average)"
+}
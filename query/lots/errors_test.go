@@ -0,0 +1,17 @@
+package lots_test
+
+import (
+	"stock-monitor/query/lots"
+	"testing"
+)
+
+func TestUnknownMatchingMethodError(t *testing.T) {
+	err := lots.NewUnknownMatchingMethodError("random")
+
+	expected := "unknown lot matching method. method: random (use fifo, lifo or average)"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
@@ -0,0 +1,26 @@
+package lots
+
+import "time"
+
+const (
+	ShortTerm = "short"
+	LongTerm  = "long"
+)
+
+// shares held for more than one year are held long term
+type HoldingPeriod struct {
+	Days int
+	Term string
+}
+
+func NewHoldingPeriod(buyDate string, endDate string) HoldingPeriod {
+	bought, _ := time.Parse("2006-01-02", buyDate)
+	ended, _ := time.Parse("2006-01-02", endDate)
+
+	days := int(ended.Sub(bought).Hours() / 24)
+	if ended.After(bought.AddDate(1, 0, 0)) {
+		return HoldingPeriod{days, LongTerm}
+	}
+
+	return HoldingPeriod{days, ShortTerm}
+}
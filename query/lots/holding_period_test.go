@@ -0,0 +1,25 @@
+package lots_test
+
+import (
+	"reflect"
+	"stock-monitor/query/lots"
+	"testing"
+)
+
+func TestSharesHeldForOneYearAreShortTerm(t *testing.T) {
+	got := lots.NewHoldingPeriod("2020-03-01", "2021-03-01")
+	want := lots.HoldingPeriod{365, lots.ShortTerm}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Holding period unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestSharesHeldForMoreThanOneYearAreLongTerm(t *testing.T) {
+	got := lots.NewHoldingPeriod("2020-03-01", "2021-03-02")
+	want := lots.HoldingPeriod{366, lots.LongTerm}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Holding period unequal got: %#v, want: %#v", got, want)
+	}
+}
@@ -0,0 +1,326 @@
+package lots
+
+import (
+	"sort"
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+)
+
+type MatchingMethod string
+
+const (
+	FirstInFirstOut MatchingMethod = "fifo"
+	LastInFirstOut  MatchingMethod = "lifo"
+	AverageCost     MatchingMethod = "average"
+)
+
+func ParseMatchingMethod(method string) (MatchingMethod, error) {
+	switch MatchingMethod(method) {
+	case FirstInFirstOut, LastInFirstOut, AverageCost:
+		return MatchingMethod(method), nil
+	}
+
+	return "", NewUnknownMatchingMethodError(method)
+}
+
+// Id numbers the buys of the portfolio, Price is the price per share, adjusted for splits, Fees are the fees and taxes of the buy
+type Lot struct {
+	Id             int
+	Ticker         string
+	BuyDate        string
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fees           domain.Money
+}
+
+func (lot Lot) CostBasis() domain.Money {
+	costBasis, _ := lot.Price.MulQuantity(lot.NumberOfShares).Add(lot.Fees)
+
+	return costBasis
+}
+
+type ClosedLot struct {
+	Lot           Lot
+	SellDate      string
+	SellPrice     domain.Money
+	HoldingPeriod HoldingPeriod
+}
+
+type Sale struct {
+	Ticker         string
+	Aliases        []string
+	Date           string
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fees           domain.Money
+	MatchedLots    []Lot
+}
+
+// Ledger keeps every buy as a lot and matches sells against the lots with the given method
+type Ledger struct {
+	method     MatchingMethod
+	openLots   map[string][]Lot
+	closedLots []ClosedLot
+	sales      []Sale
+	lastLotId  int
+}
+
+func NewLedger(method MatchingMethod) *Ledger {
+	return &Ledger{method, map[string][]Lot{}, []ClosedLot{}, []Sale{}, 0}
+}
+
+// a stored event that can't be decoded fails the projection, the lots would be wrong without it
+func Project(eventStream infrastructure.EventStream, method MatchingMethod) (*Ledger, error) {
+	ledger := NewLedger(method)
+	storedEvents, err := eventStream.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return nil, err
+		}
+		ledger.Apply(domainEvent)
+	}
+
+	return ledger, nil
+}
+
+func (ledger *Ledger) Apply(domainEvent domain.DomainEvent) {
+	switch domainEvent := domainEvent.(type) {
+	case *portfolio.SharesAddedToPortfolioEvent:
+		ledger.lastLotId++
+		fees, _ := domainEvent.Fee().Add(domainEvent.Taxes())
+		ledger.buy(Lot{ledger.lastLotId, domainEvent.Ticker(), domainEvent.Date(), domainEvent.Shares(), domainEvent.Price(), fees})
+	case *portfolio.SharesRemovedFromPortfolioEvent:
+		fees, _ := domainEvent.Fee().Add(domainEvent.Taxes())
+		ledger.sell(domainEvent.Ticker(), domainEvent.Shares(), domainEvent.Price(), fees, domainEvent.Date(), domainEvent.Lots())
+	case *portfolio.TickerRenamedEvent:
+		ledger.rename(domainEvent.Old(), domainEvent.New())
+	case *portfolio.StockSplitEvent:
+		ledger.split(domainEvent.Ticker(), domainEvent.RatioFrom(), domainEvent.RatioTo())
+	case *portfolio.SharesConvertedEvent:
+		ledger.convert(domainEvent.Ticker(), domainEvent.NewTicker(), domainEvent.RatioFrom(), domainEvent.RatioTo(), domainEvent.FractionalShares(), domainEvent.CashInLieu(), domainEvent.Date())
+	case *portfolio.SharesSpunOffEvent:
+		ledger.spinOff(domainEvent.Ticker(), domainEvent.NewTicker(), domainEvent.RatioFrom(), domainEvent.RatioTo(), domainEvent.CostBasisPercentage())
+	}
+}
+
+func (ledger *Ledger) OpenLots(ticker string) []Lot {
+	return ledger.openLots[ticker]
+}
+
+func (ledger *Ledger) Tickers() []string {
+	tickers := []string{}
+	for ticker := range ledger.openLots {
+		tickers = append(tickers, ticker)
+	}
+
+	return tickers
+}
+
+func (ledger *Ledger) ClosedLots() []ClosedLot {
+	return ledger.closedLots
+}
+
+func (ledger *Ledger) Sales() []Sale {
+	return ledger.sales
+}
+
+func (ledger *Ledger) buy(lot Lot) {
+	openLots := ledger.openLots[lot.Ticker]
+	if ledger.method == AverageCost && len(openLots) == 1 {
+		averaged, err := averageLot(openLots[0], lot)
+		if err == nil {
+			ledger.openLots[lot.Ticker] = []Lot{averaged}
+			return
+		}
+	}
+
+	ledger.openLots[lot.Ticker] = append(openLots, lot)
+}
+
+// selected lots are matched first, shares without matching lot are sold without cost basis
+func (ledger *Ledger) sell(ticker string, shares domain.Quantity, price domain.Money, fees domain.Money, date string, selectedLots []portfolio.SelectedLot) {
+	openLots := ledger.openLots[ticker]
+	matchedLots := []Lot{}
+	remaining := shares
+
+	for _, selectedLot := range selectedLots {
+		for index := range openLots {
+			if openLots[index].Id != selectedLot.LotId() {
+				continue
+			}
+			wanted := selectedLot.Shares()
+			if remaining.LessThan(wanted) {
+				wanted = remaining
+			}
+			var matched Lot
+			openLots, matched = ledger.closeLot(openLots, index, wanted, price, date)
+			matchedLots = append(matchedLots, matched)
+			remaining = remaining.Sub(matched.NumberOfShares)
+			break
+		}
+	}
+
+	for remaining.IsPositive() && len(openLots) > 0 {
+		index := 0
+		if ledger.method == LastInFirstOut {
+			index = len(openLots) - 1
+		}
+
+		var matched Lot
+		openLots, matched = ledger.closeLot(openLots, index, remaining, price, date)
+		matchedLots = append(matchedLots, matched)
+		remaining = remaining.Sub(matched.NumberOfShares)
+	}
+
+	if len(openLots) == 0 {
+		delete(ledger.openLots, ticker)
+	} else {
+		ledger.openLots[ticker] = openLots
+	}
+
+	ledger.sales = append(ledger.sales, Sale{ticker, []string{}, date, shares, price, fees, matchedLots})
+}
+
+// closes up to the given shares of the lot at index and returns the remaining open lots, fees are split by shares
+func (ledger *Ledger) closeLot(openLots []Lot, index int, shares domain.Quantity, price domain.Money, date string) ([]Lot, Lot) {
+	lot := openLots[index]
+
+	matched := lot
+	if shares.LessThan(lot.NumberOfShares) {
+		matched.NumberOfShares = shares
+		matched.Fees = lot.Fees.MulQuantity(shares).DivQuantity(lot.NumberOfShares)
+		openLots[index].NumberOfShares = lot.NumberOfShares.Sub(shares)
+		openLots[index].Fees, _ = lot.Fees.Sub(matched.Fees)
+	} else {
+		openLots = append(openLots[:index], openLots[index+1:]...)
+	}
+	ledger.closedLots = append(ledger.closedLots, ClosedLot{matched, date, price, NewHoldingPeriod(matched.BuyDate, date)})
+
+	return openLots, matched
+}
+
+func (ledger *Ledger) rename(old string, new string) {
+	openLots, found := ledger.openLots[old]
+	if found {
+		delete(ledger.openLots, old)
+		for key := range openLots {
+			openLots[key].Ticker = new
+		}
+		ledger.openLots[new] = append(ledger.openLots[new], openLots...)
+	}
+
+	for key, closedLot := range ledger.closedLots {
+		if closedLot.Lot.Ticker == old {
+			ledger.closedLots[key].Lot.Ticker = new
+		}
+	}
+
+	for key, sale := range ledger.sales {
+		if sale.Ticker == old {
+			ledger.sales[key].Ticker = new
+			ledger.sales[key].Aliases = append(ledger.sales[key].Aliases, old)
+		}
+	}
+}
+
+func (ledger *Ledger) split(ticker string, ratioFrom int, ratioTo int) {
+	for key, lot := range ledger.openLots[ticker] {
+		ledger.openLots[ticker][key].NumberOfShares = lot.NumberOfShares.MulRatio(ratioTo, ratioFrom)
+		ledger.openLots[ticker][key].Price = lot.Price.MulRatio(ratioFrom, ratioTo)
+	}
+}
+
+// converted lots keep their cost basis, the fractional shares paid in cash are sold from the converted lots only
+func (ledger *Ledger) convert(ticker string, newTicker string, ratioFrom int, ratioTo int, fractionalShares domain.Quantity, cashInLieu domain.Money, date string) {
+	convertedLots := ledger.openLots[ticker]
+	delete(ledger.openLots, ticker)
+	for key, lot := range convertedLots {
+		convertedLots[key].Ticker = newTicker
+		convertedLots[key].NumberOfShares = lot.NumberOfShares.MulRatio(ratioTo, ratioFrom)
+		convertedLots[key].Price = lot.Price.MulRatio(ratioFrom, ratioTo)
+	}
+
+	heldLots, held := ledger.openLots[newTicker]
+	ledger.openLots[newTicker] = convertedLots
+	if fractionalShares.IsPositive() {
+		fees := domain.NewMoneyFromFloat(0, cashInLieu.Currency())
+		ledger.sell(newTicker, fractionalShares, cashInLieu.DivQuantity(fractionalShares), fees, date, []portfolio.SelectedLot{})
+	}
+	convertedLots = ledger.openLots[newTicker]
+	delete(ledger.openLots, newTicker)
+	if held {
+		ledger.openLots[newTicker] = heldLots
+	}
+
+	if ledger.method == AverageCost {
+		for _, lot := range convertedLots {
+			ledger.buy(lot)
+		}
+		return
+	}
+
+	mergedLots := append(ledger.openLots[newTicker], convertedLots...)
+	if len(mergedLots) == 0 {
+		return
+	}
+	sort.SliceStable(mergedLots, func(i, j int) bool {
+		return mergedLots[i].Id < mergedLots[j].Id
+	})
+	ledger.openLots[newTicker] = mergedLots
+}
+
+// every open lot of ticker passes the percentage of its cost basis, fees included, to a lot of newTicker with the same id and buy date
+func (ledger *Ledger) spinOff(ticker string, newTicker string, ratioFrom int, ratioTo int, costBasisPercentage domain.Percentage) {
+	spunOffLots := []Lot{}
+	for key, lot := range ledger.openLots[ticker] {
+		shares := lot.NumberOfShares.MulRatio(ratioTo, ratioFrom)
+		// a lot too small to receive a share keeps its whole cost basis
+		if !shares.IsPositive() {
+			continue
+		}
+		costBasis := lot.Price.MulQuantity(lot.NumberOfShares).MulPercentage(costBasisPercentage)
+		fees := lot.Fees.MulPercentage(costBasisPercentage)
+
+		remainingCostBasis, _ := lot.Price.MulQuantity(lot.NumberOfShares).Sub(costBasis)
+		ledger.openLots[ticker][key].Price = remainingCostBasis.DivQuantity(lot.NumberOfShares)
+		ledger.openLots[ticker][key].Fees, _ = lot.Fees.Sub(fees)
+
+		spunOffLots = append(spunOffLots, Lot{lot.Id, newTicker, lot.BuyDate, shares, costBasis.DivQuantity(shares), fees})
+	}
+
+	if ledger.method == AverageCost {
+		for _, lot := range spunOffLots {
+			ledger.buy(lot)
+		}
+		return
+	}
+
+	mergedLots := append(ledger.openLots[newTicker], spunOffLots...)
+	if len(mergedLots) == 0 {
+		return
+	}
+	sort.SliceStable(mergedLots, func(i, j int) bool {
+		return mergedLots[i].Id < mergedLots[j].Id
+	})
+	ledger.openLots[newTicker] = mergedLots
+}
+
+func averageLot(pooled Lot, lot Lot) (Lot, error) {
+	shares := pooled.NumberOfShares.Add(lot.NumberOfShares)
+	price, err := pooled.Price.MulQuantity(pooled.NumberOfShares).Add(lot.Price.MulQuantity(lot.NumberOfShares))
+	if err != nil {
+		return Lot{}, err
+	}
+	fees, err := pooled.Fees.Add(lot.Fees)
+	if err != nil {
+		return Lot{}, err
+	}
+
+	return Lot{pooled.Id, pooled.Ticker, pooled.BuyDate, shares, price.DivQuantity(shares), fees}, nil
+}
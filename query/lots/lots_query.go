@@ -0,0 +1,53 @@
+package lots
+
+import (
+	"sort"
+	"stock-monitor/infrastructure"
+)
+
+type LotsQueryInterface interface {
+	GetLots(method MatchingMethod, ticker string, date string) (Lots, error)
+}
+
+type OpenLot struct {
+	Lot           Lot
+	HoldingPeriod HoldingPeriod
+}
+
+type Lots struct {
+	OpenLots   []OpenLot
+	ClosedLots []ClosedLot
+}
+
+type LotsQuery struct {
+	EventStream infrastructure.EventStream
+}
+
+// the holding period of open lots is measured until date, an empty ticker selects all lots
+func (lotsQuery *LotsQuery) GetLots(method MatchingMethod, ticker string, date string) (Lots, error) {
+	ledger, err := Project(lotsQuery.EventStream, method)
+	if err != nil {
+		return Lots{}, err
+	}
+	lots := Lots{[]OpenLot{}, []ClosedLot{}}
+
+	tickers := ledger.Tickers()
+	sort.Strings(tickers)
+	for _, openTicker := range tickers {
+		if ticker != "" && openTicker != ticker {
+			continue
+		}
+		for _, lot := range ledger.OpenLots(openTicker) {
+			lots.OpenLots = append(lots.OpenLots, OpenLot{lot, NewHoldingPeriod(lot.BuyDate, date)})
+		}
+	}
+
+	for _, closedLot := range ledger.ClosedLots() {
+		if ticker != "" && closedLot.Lot.Ticker != ticker {
+			continue
+		}
+		lots.ClosedLots = append(lots.ClosedLots, closedLot)
+	}
+
+	return lots, nil
+}
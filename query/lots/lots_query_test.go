@@ -0,0 +1,58 @@
+package lots_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query/lots"
+	"testing"
+)
+
+func TestLotsQueryProvidesOpenAndClosedLots(t *testing.T) {
+	events := append(buyAndSellEvents(), infrastructure.Event{
+		portfolio.SharesAddedToPortfolioEventName,
+		map[string]interface{}{"ticker": "PG", "price": "50", "currency": "EUR", "shares": "1", "date": "2001-03-02"},
+		map[string]interface{}{"occurred_at": "2001-03-02", "version": 3},
+	})
+	lotsQuery := lots.LotsQuery{&infrastructure.InMemoryEventStream{events}}
+
+	got, _ := lotsQuery.GetLots(lots.FirstInFirstOut, "MO", "2002-03-01")
+	want := lots.Lots{
+		[]lots.OpenLot{
+			{lots.Lot{2, "MO", "2001-02-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}, lots.HoldingPeriod{393, lots.LongTerm}},
+		},
+		[]lots.ClosedLot{
+			{lots.Lot{1, "MO", "2001-01-01", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}, "2001-03-01", domain.NewMoneyFromFloat(30, "EUR"), lots.HoldingPeriod{59, lots.ShortTerm}},
+			{lots.Lot{2, "MO", "2001-02-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}, "2001-03-01", domain.NewMoneyFromFloat(30, "EUR"), lots.HoldingPeriod{28, lots.ShortTerm}},
+		},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Lots unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestClosedLotsSurviveTickerRenames(t *testing.T) {
+	events := append(buyAndSellEvents(), infrastructure.Event{
+		portfolio.TickerRenamedEventName,
+		map[string]interface{}{"old": "MO", "new": "FOO"},
+		map[string]interface{}{"occurred_at": "2001-04-01"},
+	})
+	lotsQuery := lots.LotsQuery{&infrastructure.InMemoryEventStream{events}}
+
+	got, _ := lotsQuery.GetLots(lots.FirstInFirstOut, "FOO", "2001-04-01")
+
+	if len(got.OpenLots) != 1 || len(got.ClosedLots) != 2 {
+		t.Errorf("Expected one open and two closed lots of FOO but got %#v", got)
+	}
+}
+
+func TestLotsFailWhenAnEventCanNotBeDecoded(t *testing.T) {
+	lotsQuery := lots.LotsQuery{&infrastructure.InMemoryEventStream{[]infrastructure.Event{{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}}}
+	_, err := lotsQuery.GetLots(lots.FirstInFirstOut, "", "2002-03-01")
+
+	if err == nil {
+		t.Errorf("Expected lots to fail for an event that can't be decoded")
+	}
+}
@@ -0,0 +1,286 @@
+package lots_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query/lots"
+	"testing"
+)
+
+func buyAndSellEvents() []infrastructure.Event {
+	return []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "10", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 3},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "20", "currency": "EUR", "shares": "10", "date": "2001-02-01"},
+			map[string]interface{}{"occurred_at": "2001-02-01", "version": 3},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "currency": "EUR", "shares": "15", "date": "2001-03-01"},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 4},
+		},
+	}
+}
+
+func TestSharesAreSoldFirstInFirstOut(t *testing.T) {
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{buyAndSellEvents()}, lots.FirstInFirstOut)
+
+	gotMatched := ledger.Sales()[0].MatchedLots
+	wantMatched := []lots.Lot{
+		{1, "MO", "2001-01-01", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+		{2, "MO", "2001-02-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+	}
+	gotOpen := ledger.OpenLots("MO")
+	wantOpen := []lots.Lot{{2, "MO", "2001-02-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}}
+
+	if reflect.DeepEqual(gotMatched, wantMatched) == false {
+		t.Errorf("Matched lots unequal got: %#v, want: %#v", gotMatched, wantMatched)
+	}
+	if reflect.DeepEqual(gotOpen, wantOpen) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", gotOpen, wantOpen)
+	}
+}
+
+func TestSharesAreSoldLastInFirstOut(t *testing.T) {
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{buyAndSellEvents()}, lots.LastInFirstOut)
+
+	gotMatched := ledger.Sales()[0].MatchedLots
+	wantMatched := []lots.Lot{
+		{2, "MO", "2001-02-01", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+		{1, "MO", "2001-01-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+	}
+	gotOpen := ledger.OpenLots("MO")
+	wantOpen := []lots.Lot{{1, "MO", "2001-01-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}}
+
+	if reflect.DeepEqual(gotMatched, wantMatched) == false {
+		t.Errorf("Matched lots unequal got: %#v, want: %#v", gotMatched, wantMatched)
+	}
+	if reflect.DeepEqual(gotOpen, wantOpen) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", gotOpen, wantOpen)
+	}
+}
+
+func TestSelectedLotsAreSoldFirst(t *testing.T) {
+	events := buyAndSellEvents()
+	events[2] = infrastructure.Event{
+		portfolio.SharesRemovedFromPortfolioEventName,
+		map[string]interface{}{"ticker": "MO", "price": "30", "currency": "EUR", "shares": "15", "date": "2001-03-01", "lots": "2:8"},
+		map[string]interface{}{"occurred_at": "2001-03-01", "version": 5},
+	}
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{events}, lots.FirstInFirstOut)
+
+	gotMatched := ledger.Sales()[0].MatchedLots
+	wantMatched := []lots.Lot{
+		{2, "MO", "2001-02-01", domain.NewQuantityFromInt(8), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+		{1, "MO", "2001-01-01", domain.NewQuantityFromInt(7), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+	}
+	gotOpen := ledger.OpenLots("MO")
+	wantOpen := []lots.Lot{
+		{1, "MO", "2001-01-01", domain.NewQuantityFromInt(3), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+		{2, "MO", "2001-02-01", domain.NewQuantityFromInt(2), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+	}
+
+	if reflect.DeepEqual(gotMatched, wantMatched) == false {
+		t.Errorf("Matched lots unequal got: %#v, want: %#v", gotMatched, wantMatched)
+	}
+	if reflect.DeepEqual(gotOpen, wantOpen) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", gotOpen, wantOpen)
+	}
+}
+
+func TestSharesAreSoldAtAverageCost(t *testing.T) {
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{buyAndSellEvents()}, lots.AverageCost)
+
+	gotMatched := ledger.Sales()[0].MatchedLots
+	wantMatched := []lots.Lot{{1, "MO", "2001-01-01", domain.NewQuantityFromInt(15), domain.NewMoneyFromFloat(15, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}}
+	gotOpen := ledger.OpenLots("MO")
+	wantOpen := []lots.Lot{{1, "MO", "2001-01-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(15, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}}
+
+	if reflect.DeepEqual(gotMatched, wantMatched) == false {
+		t.Errorf("Matched lots unequal got: %#v, want: %#v", gotMatched, wantMatched)
+	}
+	if reflect.DeepEqual(gotOpen, wantOpen) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", gotOpen, wantOpen)
+	}
+}
+
+func TestLotsAreAdjustedForSplitsAndRenames(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "40", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 3},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "MO", "ratio_from": 1, "ratio_to": 4, "date": "2001-02-01"},
+			map[string]interface{}{"occurred_at": "2001-02-01"},
+		},
+		{
+			portfolio.TickerRenamedEventName,
+			map[string]interface{}{"old": "MO", "new": "FOO"},
+			map[string]interface{}{"occurred_at": "2001-03-01"},
+		},
+	}
+
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{events}, lots.FirstInFirstOut)
+
+	got := ledger.OpenLots("FOO")
+	want := []lots.Lot{{1, "FOO", "2001-01-01", domain.NewQuantityFromInt(40), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")}}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", got, want)
+	}
+	if len(ledger.OpenLots("MO")) != 0 {
+		t.Errorf("Expected no open lots for MO but got %#v", ledger.OpenLots("MO"))
+	}
+}
+
+func TestConvertedLotsJoinTheNewTickerAndFractionalSharesAreSoldForCashInLieu(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 3},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "PM", "price": "20", "currency": "EUR", "shares": "4", "date": "2001-02-01"},
+			map[string]interface{}{"occurred_at": "2001-02-01", "version": 3},
+		},
+		{
+			portfolio.SharesConvertedEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 4, "ratio_to": 3, "fractional_shares": "0.5", "cash_in_lieu": "10", "currency": "EUR", "date": "2001-03-01"},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 1},
+		},
+	}
+
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{events}, lots.FirstInFirstOut)
+
+	halfShare, _ := domain.NewQuantity("0.5")
+	gotSale := ledger.Sales()[0]
+	wantSale := lots.Sale{"PM", []string{}, "2001-03-01", halfShare, domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), []lots.Lot{
+		{1, "PM", "2001-01-01", halfShare, domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+	}}
+	gotOpen := ledger.OpenLots("PM")
+	wantOpen := []lots.Lot{
+		{1, "PM", "2001-01-01", domain.NewQuantityFromInt(7), domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+		{2, "PM", "2001-02-01", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+	}
+
+	if reflect.DeepEqual(gotSale, wantSale) == false {
+		t.Errorf("Sale unequal got: %#v, want: %#v", gotSale, wantSale)
+	}
+	if reflect.DeepEqual(gotOpen, wantOpen) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", gotOpen, wantOpen)
+	}
+	if len(ledger.OpenLots("MO")) != 0 {
+		t.Errorf("Expected no open lots for MO but got %#v", ledger.OpenLots("MO"))
+	}
+}
+
+func TestSpunOffLotsTakeTheirShareOfTheCostBasis(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "5", "taxes": "0", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 4},
+		},
+		{
+			portfolio.SharesSpunOffEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 2, "ratio_to": 1, "cost_basis_percentage": "20", "date": "2001-03-01"},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 1},
+		},
+	}
+
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{events}, lots.FirstInFirstOut)
+
+	parent := ledger.OpenLots("MO")[0]
+	spunOff := ledger.OpenLots("PM")[0]
+
+	if parent.Id != 1 || parent.NumberOfShares.String() != "10" || parent.Price.String() != "24 EUR" || parent.Fees.String() != "4 EUR" {
+		t.Errorf("Unexpected parent lot: %#v", parent)
+	}
+	if spunOff.Id != 1 || spunOff.BuyDate != "2001-01-01" || spunOff.NumberOfShares.String() != "5" || spunOff.Price.String() != "12 EUR" || spunOff.Fees.String() != "1 EUR" {
+		t.Errorf("Unexpected spun off lot: %#v", spunOff)
+	}
+}
+
+func TestLotsTooSmallToReceiveASpunOffShareKeepTheirCostBasis(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "0", "taxes": "0", "currency": "EUR", "shares": "0.00000001", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 4},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "0", "taxes": "0", "currency": "EUR", "shares": "1000", "date": "2001-02-01"},
+			map[string]interface{}{"occurred_at": "2001-02-01", "version": 4},
+		},
+		{
+			portfolio.SharesSpunOffEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 1000, "ratio_to": 1, "cost_basis_percentage": "20", "date": "2001-03-01"},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 1},
+		},
+	}
+
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{events}, lots.FirstInFirstOut)
+
+	parent := ledger.OpenLots("MO")
+	spunOff := ledger.OpenLots("PM")
+
+	if len(parent) != 2 || parent[0].Price.String() != "30 EUR" || parent[1].Price.String() != "24 EUR" {
+		t.Errorf("Unexpected parent lots: %#v", parent)
+	}
+	if len(spunOff) != 1 || spunOff[0].Id != 2 || spunOff[0].NumberOfShares.String() != "1" || spunOff[0].Price.String() != "6000 EUR" {
+		t.Errorf("Unexpected spun off lots: %#v", spunOff)
+	}
+}
+
+func TestUnknownMatchingMethodIsRejected(t *testing.T) {
+	_, err := lots.ParseMatchingMethod("random")
+
+	_, ok := err.(*lots.UnknownMatchingMethodError)
+	if !ok {
+		t.Errorf("Expected UnknownMatchingMethodError but got %#v", err)
+	}
+}
+
+func TestFeesAreSplitWhenLotsArePartiallySold(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "10", "fee": "2", "taxes": "1", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 4},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "1", "taxes": "0", "currency": "EUR", "shares": "4", "date": "2001-03-01", "lots": ""},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 6},
+		},
+	}
+	ledger, _ := lots.Project(&infrastructure.InMemoryEventStream{events}, lots.FirstInFirstOut)
+
+	gotMatched := ledger.Sales()[0].MatchedLots
+	wantMatched := []lots.Lot{{1, "MO", "2001-01-01", domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(1.2, "EUR")}}
+	gotOpen := ledger.OpenLots("MO")
+	wantOpen := []lots.Lot{{1, "MO", "2001-01-01", domain.NewQuantityFromInt(6), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(1.8, "EUR")}}
+
+	if reflect.DeepEqual(gotMatched, wantMatched) == false {
+		t.Errorf("Matched lots unequal got: %#v, want: %#v", gotMatched, wantMatched)
+	}
+	if reflect.DeepEqual(gotOpen, wantOpen) == false {
+		t.Errorf("Open lots unequal got: %#v, want: %#v", gotOpen, wantOpen)
+	}
+	if reflect.DeepEqual(ledger.Sales()[0].Fees, domain.NewMoneyFromFloat(1, "EUR")) == false {
+		t.Errorf("Unexpected fees of sale: %#v", ledger.Sales()[0].Fees.String())
+	}
+}
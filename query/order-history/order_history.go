@@ -1,75 +1,107 @@
 package orderHistory
 
 import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 )
 
 type OrderHistoryQueryInterface interface {
-	GetOrders() []Order
+	GetOrders() ([]Order, error)
 }
 
 type Order struct {
-	OrderType      string
-	Ticker         string
-	Aliases        []string
-	NumberOfShares int
-	Price          float32
-	Date           string
+	OrderType              string
+	Ticker                 string
+	Aliases                []string
+	NumberOfShares         domain.Quantity
+	Price                  domain.Money
+	Fee                    domain.Money
+	Taxes                  domain.Money
+	AdjustedNumberOfShares domain.Quantity
+	AdjustedPrice          domain.Money
+	Date                   string
 }
 
 type OrderHistoryQuery struct {
 	EventStream infrastructure.EventStream
 }
 
-func (orderHistoryQuery *OrderHistoryQuery) GetOrders() []Order {
+// a stored event that can't be decoded fails the query, later orders would be adjusted wrongly without it
+func (orderHistoryQuery *OrderHistoryQuery) GetOrders() ([]Order, error) {
 	orders := []Order{}
-	renames := []infrastructure.Event{}
-	for _, event := range orderHistoryQuery.EventStream.Get() {
-		if event.Name == portfolio.SharesAddedToPortfolioEventName {
-			ticker, shares, price, date := extractEventData(event)
-			order := Order{"BUY", ticker, []string{}, shares, price, date}
-			orders = append(orders, order)
+	storedEvents, err := orderHistoryQuery.EventStream.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return nil, err
+		}
+		date, _ := storedEvent.MetaData["occurred_at"].(string)
 
-			continue
+		switch domainEvent := domainEvent.(type) {
+		case *portfolio.SharesAddedToPortfolioEvent:
+			orders = append(orders, newOrder("BUY", domainEvent.Ticker(), domainEvent.Shares(), domainEvent.Price(), domainEvent.Fee(), domainEvent.Taxes(), date))
+		case *portfolio.SharesRemovedFromPortfolioEvent:
+			orders = append(orders, newOrder("SELL", domainEvent.Ticker(), domainEvent.Shares(), domainEvent.Price(), domainEvent.Fee(), domainEvent.Taxes(), date))
+		case *portfolio.TickerRenamedEvent:
+			for key, order := range orders {
+				if order.Ticker == domainEvent.Old() {
+					orders[key].Ticker = domainEvent.New()
+					orders[key].Aliases = append(orders[key].Aliases, domainEvent.Old())
+				}
+			}
+		case *portfolio.StockSplitEvent:
+			for key, order := range orders {
+				if order.Ticker == domainEvent.Ticker() {
+					orders[key].AdjustedNumberOfShares = order.AdjustedNumberOfShares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom())
+					orders[key].AdjustedPrice = order.AdjustedPrice.MulRatio(domainEvent.RatioFrom(), domainEvent.RatioTo())
+				}
+			}
+		case *portfolio.SharesConvertedEvent:
+			for key, order := range orders {
+				if order.Ticker == domainEvent.Ticker() {
+					orders[key].Ticker = domainEvent.NewTicker()
+					orders[key].Aliases = append(orders[key].Aliases, domainEvent.Ticker())
+					orders[key].AdjustedNumberOfShares = order.AdjustedNumberOfShares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom())
+					orders[key].AdjustedPrice = order.AdjustedPrice.MulRatio(domainEvent.RatioFrom(), domainEvent.RatioTo())
+				}
+			}
+		case *portfolio.SharesSpunOffEvent:
+			held, currency := heldShares(orders, domainEvent.Ticker())
+			zero := domain.NewMoneyFromFloat(0, currency)
+			shares := held.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom())
+			orders = append(orders, newOrder("SPIN_OFF", domainEvent.NewTicker(), shares, zero, zero, zero, date))
 		}
+	}
+
+	return orders, nil
+}
 
-		if event.Name == portfolio.SharesRemovedFromPortfolioEventName {
-			ticker, shares, price, date := extractEventData(event)
-			order := Order{"SELL", ticker, []string{}, shares, price, date}
-			orders = append(orders, order)
+func newOrder(orderType string, ticker string, shares domain.Quantity, price domain.Money, fee domain.Money, taxes domain.Money, date string) Order {
+	return Order{orderType, ticker, []string{}, shares, price, fee, taxes, shares, price, date}
+}
 
+// the shares of ticker held after the given orders, split adjusted, and the currency they were bought in
+func heldShares(orders []Order, ticker string) (domain.Quantity, string) {
+	held := domain.NewQuantityFromInt(0)
+	currency := ""
+	for _, order := range orders {
+		if order.Ticker != ticker {
 			continue
 		}
-
-		if event.Name == portfolio.TickerRenamedEventName {
-			renames = append(renames, event)
+		if order.OrderType == "SELL" {
+			held = held.Sub(order.AdjustedNumberOfShares)
 			continue
 		}
-	}
-
-	for _, renameEvent := range renames {
-		oldSymbol := renameEvent.Payload["old"].(string)
-		newSymbol := renameEvent.Payload["new"].(string)
-		for key, order := range orders {
-			if order.Ticker == oldSymbol {
-				orders[key].Ticker = newSymbol
-				orders[key].Aliases = append(orders[key].Aliases, oldSymbol)
-			}
+		held = held.Add(order.AdjustedNumberOfShares)
+		if currency == "" {
+			currency = order.Price.Currency()
 		}
 	}
 
-	return orders
-}
-
-func extractEventData(event infrastructure.Event) (string, int, float32, string) {
-	ticker := event.Payload["ticker"].(string)
-	shares := event.Payload["shares"].(int)
-	price, ok := event.Payload["price"].(float32)
-	if !ok {
-		price = float32(event.Payload["price"].(float64))
-	}
-	date, ok := event.MetaData["occurred_at"].(string)
-
-	return ticker, shares, price, date
+	return held, currency
 }
@@ -2,6 +2,7 @@ package orderHistory_test
 
 import (
 	"reflect"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 	orderHistory "stock-monitor/query/order-history"
@@ -28,11 +29,11 @@ func TestOrderHistoryProvidesAllOrders(t *testing.T) {
 	}
 
 	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
-	got := orderHistoryQuery.GetOrders()
+	got, _ := orderHistoryQuery.GetOrders()
 	want := []orderHistory.Order{
-		{"BUY", "MO", []string{}, 10, 20.45, "2001-01-02"},
-		{"BUY", "PG", []string{}, 20, 40.00, "2001-01-03"},
-		{"SELL", "MO", []string{}, 5, 40.00, "2001-01-04"},
+		{"BUY", "MO", []string{}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), "2001-01-02"},
+		{"BUY", "PG", []string{}, domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(40.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-03"},
+		{"SELL", "MO", []string{}, domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-04"},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -50,9 +51,9 @@ func TestOrderHistoryCanHandleOrdersWithoutDate(t *testing.T) {
 	}
 
 	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
-	got := orderHistoryQuery.GetOrders()
+	got, _ := orderHistoryQuery.GetOrders()
 	want := []orderHistory.Order{
-		{"BUY", "MO", []string{}, 10, 20.45, ""},
+		{"BUY", "MO", []string{}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), ""},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -80,10 +81,10 @@ func TestOrderHistoryContainsRenamedTickers(t *testing.T) {
 	}
 
 	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
-	got := orderHistoryQuery.GetOrders()
+	got, _ := orderHistoryQuery.GetOrders()
 	want := []orderHistory.Order{
-		{"BUY", "FOO", []string{"MO"}, 10, 20.45, "2001-01-02"},
-		{"SELL", "FOO", []string{"MO"}, 5, 40.00, "2001-01-02"},
+		{"BUY", "FOO", []string{"MO"}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), "2001-01-02"},
+		{"SELL", "FOO", []string{"MO"}, domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-02"},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -116,10 +117,10 @@ func TestMultipleRenamesOnTheSameTickerAreHandled(t *testing.T) {
 	}
 
 	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
-	got := orderHistoryQuery.GetOrders()
+	got, _ := orderHistoryQuery.GetOrders()
 	want := []orderHistory.Order{
-		{"BUY", "BAR", []string{"MO", "FOO"}, 10, 20.45, "2001-01-02"},
-		{"SELL", "BAR", []string{"MO", "FOO"}, 5, 40.00, "2001-01-02"},
+		{"BUY", "BAR", []string{"MO", "FOO"}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), "2001-01-02"},
+		{"SELL", "BAR", []string{"MO", "FOO"}, domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-02"},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
@@ -152,14 +153,138 @@ func TestOrdersAfterRenameDoNotContainPreviousTickers(t *testing.T) {
 	}
 
 	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
-	got := orderHistoryQuery.GetOrders()
+	got, _ := orderHistoryQuery.GetOrders()
 	want := []orderHistory.Order{
-		{"BUY", "FOO", []string{"MO"}, 10, 20.45, "2001-01-02"},
-		{"SELL", "FOO", []string{"MO"}, 5, 40.00, "2001-01-02"},
-		{"BUY", "FOO", []string{}, 10, 20.45, "2001-01-03"},
+		{"BUY", "FOO", []string{"MO"}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), "2001-01-02"},
+		{"SELL", "FOO", []string{"MO"}, domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(40.00, "EUR"), "2001-01-02"},
+		{"BUY", "FOO", []string{}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "EUR"), "2001-01-03"},
 	}
 
 	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Orders unequal got: %#v, want: %#v", got, want)
 	}
 }
+
+func TestOrderHistoryContainsSplitAdjustedOrders(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 40.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+		{
+			portfolio.TickerRenamedEventName,
+			map[string]interface{}{"old": "MO", "new": "FOO"},
+			map[string]interface{}{"occurred_at": "2001-01-03"},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "FOO", "ratio_from": 1, "ratio_to": 4, "date": "2001-01-04"},
+			map[string]interface{}{"occurred_at": "2001-01-04"},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "FOO", "price": 12.00, "shares": 20},
+			map[string]interface{}{"occurred_at": "2001-01-05"},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "FOO", "ratio_from": 2, "ratio_to": 1, "date": "2001-01-06"},
+			map[string]interface{}{"occurred_at": "2001-01-06"},
+		},
+	}
+
+	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
+	got, _ := orderHistoryQuery.GetOrders()
+	want := []orderHistory.Order{
+		{"BUY", "FOO", []string{"MO"}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(40.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(20.00, "EUR"), "2001-01-02"},
+		{"SELL", "FOO", []string{}, domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(12.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(24.00, "EUR"), "2001-01-05"},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Orders unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestOrderHistoryContainsConvertedOrders(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 30.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+		{
+			portfolio.SharesConvertedEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 2, "ratio_to": 3, "fractional_shares": "0", "cash_in_lieu": "0", "currency": "", "date": "2001-01-03"},
+			map[string]interface{}{"occurred_at": "2001-01-03", "version": 1},
+		},
+	}
+
+	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
+	got, _ := orderHistoryQuery.GetOrders()
+	want := []orderHistory.Order{
+		{"BUY", "PM", []string{"MO"}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(30.00, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewQuantityFromInt(15), domain.NewMoneyFromFloat(20.00, "EUR"), "2001-01-02"},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Orders unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestOrderHistoryContainsSpinOffs(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "0", "taxes": "0", "currency": "USD", "shares": "10", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 4},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 40.00, "shares": 2},
+			map[string]interface{}{"occurred_at": "2001-01-03"},
+		},
+		{
+			portfolio.SharesSpunOffEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 2, "ratio_to": 1, "cost_basis_percentage": "20", "date": "2001-01-04"},
+			map[string]interface{}{"occurred_at": "2001-01-04", "version": 1},
+		},
+	}
+
+	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
+	orders, _ := orderHistoryQuery.GetOrders()
+	got := orders[2]
+	want := orderHistory.Order{"SPIN_OFF", "PM", []string{}, domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(0, "USD"), domain.NewMoneyFromFloat(0, "USD"), domain.NewMoneyFromFloat(0, "USD"), domain.NewQuantityFromInt(4), domain.NewMoneyFromFloat(0, "USD"), "2001-01-04"}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Orders unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestOrderHistoryContainsFeesAndTaxes(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "20.45", "fee": "4.95", "taxes": "0.5", "currency": "USD", "shares": "10", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 4},
+		},
+	}
+
+	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{events}}
+	got, _ := orderHistoryQuery.GetOrders()
+	want := []orderHistory.Order{
+		{"BUY", "MO", []string{}, domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "USD"), domain.NewMoneyFromFloat(4.95, "USD"), domain.NewMoneyFromFloat(0.5, "USD"), domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(20.45, "USD"), "2001-01-02"},
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Orders unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestOrderHistoryFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	orderHistoryQuery := orderHistory.OrderHistoryQuery{&infrastructure.InMemoryEventStream{[]infrastructure.Event{{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}}}
+	_, err := orderHistoryQuery.GetOrders()
+
+	if err == nil {
+		t.Errorf("Expected order history to fail for an event that can't be decoded")
+	}
+}
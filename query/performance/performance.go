@@ -0,0 +1,188 @@
+package performance
+
+import (
+	"math"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/valuation"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type PerformanceQueryInterface interface {
+	GetPerformance(from string, to string) (Performance, error)
+}
+
+// Inflow is the money spent on buys less the proceeds of sales, the InternalRateOfReturn is annualized
+// and nil when it is undefined, i.e. the period has no money both going in and coming out
+type Performance struct {
+	From                 string
+	To                   string
+	StartValue           domain.Money
+	EndValue             domain.Money
+	Inflow               domain.Money
+	Dividends            domain.Money
+	TimeWeightedReturn   domain.Percentage
+	InternalRateOfReturn *domain.Percentage
+}
+
+type PerformanceQuery struct {
+	PortfolioEventStream infrastructure.EventStream
+	DividendEventStream  infrastructure.EventStream
+	Prices               query.PriceHistoryProvider
+	ExchangeRates        query.ExchangeRateProvider
+	BaseCurrency         string
+}
+
+// an empty from starts with the first order, an empty to ends today
+func (performanceQuery *PerformanceQuery) GetPerformance(from string, to string) (Performance, error) {
+	portfolioValuation, err := valuation.NewValuation([]infrastructure.EventStream{performanceQuery.PortfolioEventStream}, []infrastructure.EventStream{performanceQuery.DividendEventStream}, performanceQuery.Prices, performanceQuery.ExchangeRates, performanceQuery.BaseCurrency)
+	if err != nil {
+		return Performance{}, err
+	}
+	if from == "" {
+		from = valuation.FirstOrderDate(portfolioValuation.PortfolioEvents)
+	}
+	if to == "" {
+		to = time.Now().Format(dateLayout)
+	}
+	if from == "" {
+		from = to
+	}
+
+	flows, err := portfolioValuation.CashFlows(from, to)
+	if err != nil {
+		return Performance{}, err
+	}
+
+	startValue, err := portfolioValuation.ValueAt(dayBefore(from))
+	if err != nil {
+		return Performance{}, err
+	}
+	endValue, err := portfolioValuation.ValueAt(to)
+	if err != nil {
+		return Performance{}, err
+	}
+
+	timeWeightedReturn, err := timeWeightedReturn(&portfolioValuation, flows, startValue, to)
+	if err != nil {
+		return Performance{}, err
+	}
+
+	inflow := domain.NewMoneyFromFloat(0, performanceQuery.BaseCurrency)
+	dividends := domain.NewMoneyFromFloat(0, performanceQuery.BaseCurrency)
+	for _, flow := range flows {
+		inflow, err = inflow.Add(flow.Bought)
+		if err != nil {
+			return Performance{}, err
+		}
+		inflow, err = inflow.Sub(flow.Sold)
+		if err != nil {
+			return Performance{}, err
+		}
+		dividends, err = dividends.Add(flow.Dividends)
+		if err != nil {
+			return Performance{}, err
+		}
+	}
+
+	var internalRate *domain.Percentage
+	if rate, defined := internalRateOfReturn(flows, startValue, endValue, from, to); defined {
+		percentage := domain.NewPercentageFromRatio(rate)
+		internalRate = &percentage
+	}
+
+	return Performance{
+		from,
+		to,
+		startValue,
+		endValue,
+		inflow,
+		dividends,
+		domain.NewPercentageFromRatio(timeWeightedReturn),
+		internalRate,
+	}, nil
+}
+
+// buys are invested at the start of their day, sales and dividends are paid out at its end
+func timeWeightedReturn(portfolioValuation *valuation.Valuation, flows []valuation.CashFlow, startValue domain.Money, to string) (float64, error) {
+	factor := 1.0
+	previousValue := startValue.Float64()
+
+	if len(flows) == 0 || flows[len(flows)-1].Date != to {
+		flows = append(flows, valuation.CashFlow{to, domain.Money{}, domain.Money{}, domain.Money{}})
+	}
+	for _, flow := range flows {
+		value, err := portfolioValuation.ValueAt(flow.Date)
+		if err != nil {
+			return 0, err
+		}
+
+		invested := previousValue + flow.Bought.Float64()
+		if invested > 0 {
+			factor *= (value.Float64() + flow.Sold.Float64() + flow.Dividends.Float64()) / invested
+		}
+		previousValue = value.Float64()
+	}
+
+	return factor - 1, nil
+}
+
+// solves the rate at which the discounted start value and buys equal the discounted sales, dividends and end value,
+// there is none unless money goes in and comes out
+func internalRateOfReturn(flows []valuation.CashFlow, startValue domain.Money, endValue domain.Money, from string, to string) (float64, bool) {
+	start, _ := time.Parse(dateLayout, from)
+	years := func(date string) float64 {
+		parsed, _ := time.Parse(dateLayout, date)
+		return parsed.Sub(start).Hours() / 24 / 365
+	}
+
+	amounts := []float64{-startValue.Float64(), endValue.Float64()}
+	times := []float64{0, years(to)}
+	for _, flow := range flows {
+		amounts = append(amounts, flow.Sold.Float64()+flow.Dividends.Float64()-flow.Bought.Float64())
+		times = append(times, years(flow.Date))
+	}
+	paidIn, paidOut := false, false
+	for _, amount := range amounts {
+		paidIn = paidIn || amount < 0
+		paidOut = paidOut || amount > 0
+	}
+	if !paidIn || !paidOut {
+		return 0, false
+	}
+
+	netPresentValue := func(rate float64) float64 {
+		sum := 0.0
+		for index, amount := range amounts {
+			sum += amount / math.Pow(1+rate, times[index])
+		}
+		return sum
+	}
+
+	low, high := -0.9999, 1.0
+	for netPresentValue(high) > 0 && high < 1e6 {
+		high *= 2
+	}
+	if netPresentValue(low)*netPresentValue(high) > 0 {
+		return 0, false
+	}
+	for iteration := 0; iteration < 200; iteration++ {
+		middle := (low + high) / 2
+		if netPresentValue(middle) > 0 {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+
+	return (low + high) / 2, true
+}
+
+func dayBefore(date string) string {
+	parsed, _ := time.Parse(dateLayout, date)
+
+	return parsed.AddDate(0, 0, -1).Format(dateLayout)
+}
@@ -0,0 +1,127 @@
+package performance_test
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/performance"
+	"testing"
+)
+
+func makePerformanceQuery() performance.PerformanceQuery {
+	portfolioEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "10", "price": "10", "fee": "0", "taxes": "0", "currency": "EUR", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 4},
+			},
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "10", "price": "12", "fee": "0", "taxes": "0", "currency": "EUR", "date": "2000-07-01"},
+				map[string]interface{}{"occurred_at": "2000-07-01", "version": 4},
+			},
+		},
+	}
+	dividendEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				dividend.DividendRecordedEventName,
+				map[string]interface{}{"ticker": "MO", "net": "5", "gross": "5", "currency": "EUR", "date": "2000-12-01"},
+				map[string]interface{}{"occurred_at": "2000-12-01", "version": 2},
+			},
+		},
+	}
+	prices := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{
+		"MO": {
+			"2000-01-01": domain.NewMoneyFromFloat(10, "EUR"),
+			"2000-07-01": domain.NewMoneyFromFloat(12, "EUR"),
+			"2001-01-01": domain.NewMoneyFromFloat(11, "EUR"),
+		},
+	}}
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1"}}
+
+	return performance.PerformanceQuery{&portfolioEventStream, &dividendEventStream, prices, exchangeRates, "EUR"}
+}
+
+func TestPerformanceSinceInception(t *testing.T) {
+	performanceQuery := makePerformanceQuery()
+
+	got, err := performanceQuery.GetPerformance("", "2001-01-01")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if got.From != "2000-01-01" || got.StartValue.String() != "0 EUR" || got.EndValue.String() != "220 EUR" {
+		t.Errorf("Unexpected period. Got:%#v", got)
+	}
+	if got.Inflow.String() != "220 EUR" || got.Dividends.String() != "5 EUR" {
+		t.Errorf("Unexpected cash flows. Inflow:%#v Dividends:%#v", got.Inflow.String(), got.Dividends.String())
+	}
+	if got.TimeWeightedReturn.String() != "2.08" {
+		t.Errorf("Unexpected time weighted return. Expected:%#v Got:%#v", "2.08", got.TimeWeightedReturn.String())
+	}
+	if got.InternalRateOfReturn == nil || got.InternalRateOfReturn.String() != "3.13" {
+		t.Errorf("Unexpected internal rate of return. Expected:%#v Got:%#v", "3.13", got.InternalRateOfReturn)
+	}
+}
+
+func TestPerformanceOfPeriodStartsWithValueOfHeldShares(t *testing.T) {
+	performanceQuery := makePerformanceQuery()
+
+	got, err := performanceQuery.GetPerformance("2000-07-02", "2001-01-01")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if got.StartValue.String() != "240 EUR" || got.Inflow.String() != "0 EUR" {
+		t.Errorf("Unexpected start of period. Got:%#v", got)
+	}
+	if got.TimeWeightedReturn.String() != "-6.42" {
+		t.Errorf("Unexpected time weighted return. Expected:%#v Got:%#v", "-6.42", got.TimeWeightedReturn.String())
+	}
+}
+
+func TestPerformanceWithoutMoneyGoingInAndComingOutHasNoInternalRateOfReturn(t *testing.T) {
+	performanceQuery := makePerformanceQuery()
+
+	got, err := performanceQuery.GetPerformance("1999-01-01", "1999-12-31")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if got.InternalRateOfReturn != nil {
+		t.Errorf("Unexpected internal rate of return. Expected:nil Got:%#v", got.InternalRateOfReturn.String())
+	}
+}
+
+func TestPerformanceNeedsPricesOfHeldShares(t *testing.T) {
+	performanceQuery := makePerformanceQuery()
+
+	_, err := performanceQuery.GetPerformance("1999-01-01", "1999-12-31")
+	if err != nil {
+		t.Errorf("Unexpected error for period without shares: %#v", err)
+	}
+
+	performanceQuery.Prices = query.FakePriceHistoryProvider{map[string]map[string]domain.Money{}}
+	_, err = performanceQuery.GetPerformance("", "2001-01-01")
+
+	_, ok := err.(*query.UnknownPriceError)
+	if !ok {
+		t.Errorf("Expected UnknownPriceError but got %#v", err)
+	}
+}
+
+func TestPerformanceFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	performanceQuery := makePerformanceQuery()
+	performanceQuery.PortfolioEventStream.(*infrastructure.InMemoryEventStream).Events = append(performanceQuery.PortfolioEventStream.(*infrastructure.InMemoryEventStream).Events, infrastructure.Event{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}})
+
+	_, err := performanceQuery.GetPerformance("", "")
+
+	_, ok := err.(*query.UnreadableEventStreamError)
+	if !ok {
+		t.Errorf("Expected UnreadableEventStreamError but got %#v", err)
+	}
+}
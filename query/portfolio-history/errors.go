@@ -0,0 +1,13 @@
+package portfolio_history
+
+type UnknownIntervalError struct {
+	interval string
+}
+
+func NewUnknownIntervalError(interval string) *UnknownIntervalError {
+	return &UnknownIntervalError{interval: interval}
+}
+
+func (e *UnknownIntervalError) Error() string {
+	return "unknown interval. interval: " + e.interval + " (use day, week or month)"
+}
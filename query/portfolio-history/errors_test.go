@@ -0,0 +1,17 @@
+package portfolio_history_test
+
+import (
+	portfolio_history "stock-monitor/query/portfolio-history"
+	"testing"
+)
+
+func TestUnknownIntervalError(t *testing.T) {
+	err := portfolio_history.NewUnknownIntervalError("hour")
+
+	expected := "unknown interval. interval: hour (use day, week or month)"
+	got := err.Error()
+
+	if expected != got {
+		t.Errorf("Unexpected error text. Expected:%#v Got:%#v", expected, got)
+	}
+}
@@ -0,0 +1,113 @@
+package portfolio_history
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/valuation"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type Interval string
+
+const (
+	Day   Interval = "day"
+	Week  Interval = "week"
+	Month Interval = "month"
+)
+
+func ParseInterval(interval string) (Interval, error) {
+	switch Interval(interval) {
+	case Day, Week, Month:
+		return Interval(interval), nil
+	}
+
+	return "", NewUnknownIntervalError(interval)
+}
+
+type PortfolioHistoryQueryInterface interface {
+	GetHistory(from string, to string, interval Interval) ([]Point, error)
+}
+
+// Invested is the money spent on buys less the proceeds of sales and Dividends the net dividends, both since the first order
+type Point struct {
+	Date      string
+	Value     domain.Money
+	Invested  domain.Money
+	Dividends domain.Money
+}
+
+// the history of several portfolios is the history of their combined orders and dividends
+type PortfolioHistoryQuery struct {
+	PortfolioEventStreams []infrastructure.EventStream
+	DividendEventStreams  []infrastructure.EventStream
+	Prices                query.PriceHistoryProvider
+	ExchangeRates         query.ExchangeRateProvider
+	BaseCurrency          string
+}
+
+// points are placed at from, at the end of every day, week (sunday) or month in between and at to,
+// an empty from starts with the first order, an empty to ends today
+func (historyQuery *PortfolioHistoryQuery) GetHistory(from string, to string, interval Interval) ([]Point, error) {
+	portfolioValuation, err := valuation.NewValuation(historyQuery.PortfolioEventStreams, historyQuery.DividendEventStreams, historyQuery.Prices, historyQuery.ExchangeRates, historyQuery.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if to == "" {
+		to = time.Now().Format(dateLayout)
+	}
+	if from == "" {
+		from = valuation.FirstOrderDate(portfolioValuation.PortfolioEvents)
+	}
+	if from == "" {
+		return []Point{}, nil
+	}
+
+	flows, err := portfolioValuation.CashFlows("", to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []Point{}
+	invested := domain.NewMoneyFromFloat(0, historyQuery.BaseCurrency)
+	dividends := domain.NewMoneyFromFloat(0, historyQuery.BaseCurrency)
+	for _, date := range pointDates(from, to, interval) {
+		for len(flows) > 0 && flows[0].Date <= date {
+			invested, _ = invested.Add(flows[0].Bought)
+			invested, _ = invested.Sub(flows[0].Sold)
+			dividends, _ = dividends.Add(flows[0].Dividends)
+			flows = flows[1:]
+		}
+
+		value, err := portfolioValuation.ValueAt(date)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, Point{date, value, invested, dividends})
+	}
+
+	return points, nil
+}
+
+func pointDates(from string, to string, interval Interval) []string {
+	start, _ := time.Parse(dateLayout, from)
+	end, _ := time.Parse(dateLayout, to)
+
+	dates := []string{from}
+	for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if interval == Week && day.Weekday() != time.Sunday {
+			continue
+		}
+		if interval == Month && day.AddDate(0, 0, 1).Day() != 1 {
+			continue
+		}
+		dates = append(dates, day.Format(dateLayout))
+	}
+	if end.After(start) {
+		dates = append(dates, to)
+	}
+
+	return dates
+}
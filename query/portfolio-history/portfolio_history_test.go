@@ -0,0 +1,117 @@
+package portfolio_history_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	portfolio_history "stock-monitor/query/portfolio-history"
+	"testing"
+)
+
+func makeHistoryQuery() portfolio_history.PortfolioHistoryQuery {
+	portfolioEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "10", "price": "10", "fee": "0", "taxes": "0", "currency": "EUR", "date": "2000-01-03"},
+				map[string]interface{}{"occurred_at": "2000-01-03", "version": 4},
+			},
+			{
+				portfolio.SharesRemovedFromPortfolioEventName,
+				map[string]interface{}{"ticker": "MO", "shares": "5", "price": "12", "fee": "0", "taxes": "0", "currency": "EUR", "date": "2000-02-10", "lots": ""},
+				map[string]interface{}{"occurred_at": "2000-02-10", "version": 6},
+			},
+		},
+	}
+	dividendEventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				dividend.DividendRecordedEventName,
+				map[string]interface{}{"ticker": "MO", "net": "5", "gross": "5", "currency": "EUR", "date": "2000-01-20"},
+				map[string]interface{}{"occurred_at": "2000-01-20", "version": 2},
+			},
+		},
+	}
+	prices := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{
+		"MO": {
+			"2000-01-03": domain.NewMoneyFromFloat(10, "EUR"),
+			"2000-01-10": domain.NewMoneyFromFloat(11, "EUR"),
+			"2000-02-01": domain.NewMoneyFromFloat(12, "EUR"),
+		},
+	}}
+
+	return portfolio_history.PortfolioHistoryQuery{[]infrastructure.EventStream{&portfolioEventStream}, []infrastructure.EventStream{&dividendEventStream}, prices, query.FakeExchangeRateProvider{map[string]string{"EUR": "1"}}, "EUR"}
+}
+
+func TestMonthlyHistoryHasValueInvestedMoneyAndDividends(t *testing.T) {
+	historyQuery := makeHistoryQuery()
+
+	got, err := historyQuery.GetHistory("", "2000-02-15", portfolio_history.Month)
+
+	want := []portfolio_history.Point{
+		{"2000-01-03", domain.NewMoneyFromFloat(100, "EUR"), domain.NewMoneyFromFloat(100, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+		{"2000-01-31", domain.NewMoneyFromFloat(110, "EUR"), domain.NewMoneyFromFloat(100, "EUR"), domain.NewMoneyFromFloat(5, "EUR")},
+		{"2000-02-15", domain.NewMoneyFromFloat(60, "EUR"), domain.NewMoneyFromFloat(40, "EUR"), domain.NewMoneyFromFloat(5, "EUR")},
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Unexpected points. Expected:%#v Got:%#v", want, got)
+	}
+	for index := range want {
+		if got[index].Date != want[index].Date || got[index].Value.String() != want[index].Value.String() || got[index].Invested.String() != want[index].Invested.String() || got[index].Dividends.String() != want[index].Dividends.String() {
+			t.Errorf("Unexpected point. Expected:%#v Got:%#v", want[index], got[index])
+		}
+	}
+}
+
+func TestHistoryPointsFollowTheInterval(t *testing.T) {
+	historyQuery := makeHistoryQuery()
+
+	for interval, want := range map[portfolio_history.Interval][]string{
+		portfolio_history.Day:  {"2000-01-03", "2000-01-04", "2000-01-05"},
+		portfolio_history.Week: {"2000-01-03", "2000-01-09", "2000-01-16", "2000-01-18"},
+	} {
+		to := want[len(want)-1]
+		points, err := historyQuery.GetHistory("2000-01-03", to, interval)
+
+		got := []string{}
+		for _, point := range points {
+			got = append(got, point.Date)
+		}
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+		if reflect.DeepEqual(got, want) == false {
+			t.Errorf("Unexpected dates of %s. Expected:%#v Got:%#v", interval, want, got)
+		}
+	}
+}
+
+func TestIntervalsCanBeParsed(t *testing.T) {
+	interval, err := portfolio_history.ParseInterval("week")
+	if err != nil || interval != portfolio_history.Week {
+		t.Errorf("Unexpected interval: %#v %#v", interval, err)
+	}
+
+	_, err = portfolio_history.ParseInterval("hour")
+	if _, ok := err.(*portfolio_history.UnknownIntervalError); !ok {
+		t.Errorf("Expected UnknownIntervalError but got %#v", err)
+	}
+}
+
+func TestPortfolioHistoryFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	historyQuery := makeHistoryQuery()
+	historyQuery.DividendEventStreams = append(historyQuery.DividendEventStreams, &infrastructure.InMemoryEventStream{[]infrastructure.Event{{"Dividend.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}})
+
+	_, err := historyQuery.GetHistory("", "", portfolio_history.Day)
+
+	_, ok := err.(*query.UnreadableEventStreamError)
+	if !ok {
+		t.Errorf("Expected UnreadableEventStreamError but got %#v", err)
+	}
+}
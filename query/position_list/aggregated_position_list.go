@@ -0,0 +1,68 @@
+package position_list
+
+// AggregatedPositionListQuery combines the positions of several portfolios by ticker
+type AggregatedPositionListQuery struct {
+	Queries []PositionListQuery
+}
+
+func (aggregatedQuery *AggregatedPositionListQuery) GetPositions() (map[string]Position, error) {
+	positionLists := []map[string]Position{}
+	for _, positionListQuery := range aggregatedQuery.Queries {
+		positionList, err := positionListQuery.GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		positionLists = append(positionLists, positionList)
+	}
+
+	return combine(positionLists)
+}
+
+func (aggregatedQuery *AggregatedPositionListQuery) GetPositionsAsOf(date string) (map[string]Position, error) {
+	positionLists := []map[string]Position{}
+	for _, positionListQuery := range aggregatedQuery.Queries {
+		positionList, err := positionListQuery.GetPositionsAsOf(date)
+		if err != nil {
+			return nil, err
+		}
+		positionLists = append(positionLists, positionList)
+	}
+
+	return combine(positionLists)
+}
+
+// a ticker held in different currencies by two portfolios fails the combination instead of keeping only one of the values
+func combine(positionLists []map[string]Position) (map[string]Position, error) {
+	positions := map[string]Position{}
+
+	for _, positionList := range positionLists {
+		for ticker, position := range positionList {
+			aggregated, found := positions[ticker]
+			if !found {
+				positions[ticker] = position
+				continue
+			}
+
+			currentValue, err := aggregated.CurrentValue.Add(position.CurrentValue)
+			if err != nil {
+				return nil, err
+			}
+			currentValueInBaseCurrency, err := aggregated.CurrentValueInBaseCurrency.Add(position.CurrentValueInBaseCurrency)
+			if err != nil {
+				return nil, err
+			}
+			costBasis, err := aggregated.CostBasis.Add(position.CostBasis)
+			if err != nil {
+				return nil, err
+			}
+			costBasisInBaseCurrency, err := aggregated.CostBasisInBaseCurrency.Add(position.CostBasisInBaseCurrency)
+			if err != nil {
+				return nil, err
+			}
+
+			positions[ticker] = NewPosition(ticker, aggregated.Shares.Add(position.Shares), currentValue, currentValueInBaseCurrency, costBasis, costBasisInBaseCurrency)
+		}
+	}
+
+	return positions, nil
+}
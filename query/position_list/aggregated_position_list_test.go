@@ -0,0 +1,81 @@
+package position_list_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/lots"
+	positionList "stock-monitor/query/position_list"
+	"testing"
+)
+
+func TestAggregatedPositionListCombinesPositionsOfAllPortfolios(t *testing.T) {
+	personalEvents := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.45, "shares": 10},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "PG", "price": 40.00, "shares": 5},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+	}
+	retirementEvents := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 30.00, "shares": 15},
+			map[string]interface{}{"occurred_at": "2001-01-03"},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 10.00, "PG": 20.00}}
+
+	aggregatedQuery := positionList.AggregatedPositionListQuery{[]positionList.PositionListQuery{
+		&positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{personalEvents}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}},
+		&positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{retirementEvents}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}},
+	}}
+	got, _ := aggregatedQuery.GetPositions()
+	want := map[string]positionList.Position{
+		"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(25), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(654.50, "EUR"), domain.NewMoneyFromFloat(654.50, "EUR")),
+		"PG": positionList.NewPosition("PG", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(100.00, "EUR"), domain.NewMoneyFromFloat(100.00, "EUR"), domain.NewMoneyFromFloat(200.00, "EUR"), domain.NewMoneyFromFloat(200.00, "EUR")),
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestAggregatedPositionListFailsForATickerHeldInDifferentCurrencies(t *testing.T) {
+	euroEvents := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "20", "currency": "EUR", "shares": "10", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 3},
+		},
+	}
+	dollarEvents := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "25", "currency": "USD", "shares": "10", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 3},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 30.00}}
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+
+	aggregatedQuery := positionList.AggregatedPositionListQuery{[]positionList.PositionListQuery{
+		&positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{euroEvents}, valueTracker, exchangeRates, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}},
+		&positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{dollarEvents}, valueTracker, exchangeRates, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}},
+	}}
+	_, err := aggregatedQuery.GetPositions()
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
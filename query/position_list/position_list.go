@@ -1,91 +1,270 @@
 package position_list
 
 import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 	"stock-monitor/query"
+	"stock-monitor/query/lots"
 )
 
 type PositionListQuery interface {
-	GetPositions() map[string]Position
+	GetPositions() (map[string]Position, error)
+	GetPositionsAsOf(date string) (map[string]Position, error)
 }
 
 type Position struct {
-	Ticker       string
-	Shares       int
-	CurrentValue float32
+	Ticker                     string
+	Shares                     domain.Quantity
+	CurrentValue               domain.Money
+	CurrentValueInBaseCurrency domain.Money
+	AverageBuyPrice            domain.Money
+	CostBasis                  domain.Money
+	CostBasisInBaseCurrency    domain.Money
+	UnrealizedGain             domain.Money
+	UnrealizedGainPercentage   domain.Percentage
 }
 
+type Totals struct {
+	Value                    domain.Money
+	CostBasis                domain.Money
+	UnrealizedGain           domain.Money
+	UnrealizedGainPercentage domain.Percentage
+}
+
+// the cost basis is taken from the open lots remaining after matching sells with LotMatchingMethod,
+// positions of past days are valued with the closing prices of Prices
 type EventStreamedPositionListQuery struct {
-	EventStream  infrastructure.EventStream
-	ValueTracker query.ValueTracker
+	EventStream       infrastructure.EventStream
+	ValueTracker      query.ValueTracker
+	ExchangeRates     query.ExchangeRateProvider
+	BaseCurrency      string
+	LotMatchingMethod lots.MatchingMethod
+	Prices            query.PriceHistoryProvider
+}
+
+type valuedPositionResult struct {
+	position Position
+	err      error
+}
+
+type projectedPosition struct {
+	shares    domain.Quantity
+	currency  string
+	costBasis domain.Money
+}
+
+func NewPosition(ticker string, shares domain.Quantity, currentValue domain.Money, currentValueInBaseCurrency domain.Money, costBasis domain.Money, costBasisInBaseCurrency domain.Money) Position {
+	averageBuyPrice := domain.Money{}
+	if shares.IsPositive() {
+		averageBuyPrice = costBasis.DivQuantity(shares)
+	}
+	unrealizedGain, _ := currentValue.Sub(costBasis)
+	unrealizedGainPercentage, _ := unrealizedGain.PercentageOf(costBasis)
+
+	return Position{
+		ticker,
+		shares,
+		currentValue,
+		currentValueInBaseCurrency,
+		averageBuyPrice,
+		costBasis,
+		costBasisInBaseCurrency,
+		unrealizedGain,
+		unrealizedGainPercentage,
+	}
 }
 
-func (positionListQuery *EventStreamedPositionListQuery) GetPositions() map[string]Position {
+func (positionListQuery *EventStreamedPositionListQuery) GetPositions() (map[string]Position, error) {
 	positions := map[string]Position{}
-	positionChannel := make(chan Position)
+	positionChannel := make(chan valuedPositionResult)
 
-	positionProjection := runPositionListProjection(positionListQuery.EventStream)
+	storedEvents, err := positionListQuery.EventStream.Get()
+	if err != nil {
+		return nil, query.NewUnreadableEventStreamError(err)
+	}
+	positionProjection, err := runPositionListProjection(storedEvents, positionListQuery.LotMatchingMethod)
+	if err != nil {
+		return nil, err
+	}
 
-	for ticker, shares := range positionProjection {
-		go func(ticker string, shares int) {
-			currentValue := positionListQuery.ValueTracker.Current(ticker) * float32(shares)
-			positionChannel <- Position{ticker, shares, currentValue}
-			positions[ticker] = Position{ticker, shares, currentValue}
-		}(ticker, shares)
+	for ticker, projected := range positionProjection {
+		go func(ticker string, projected projectedPosition) {
+			currentValue := domain.NewMoneyFromFloat32(positionListQuery.ValueTracker.Current(ticker, projected.currency), projected.currency).MulQuantity(projected.shares)
+			position, err := positionListQuery.valuedPosition(ticker, projected, currentValue)
+			positionChannel <- valuedPositionResult{position, err}
+		}(ticker, projected)
 	}
 
 	for i := 0; i < len(positionProjection); i++ {
-		position := <-positionChannel
-		positions[position.Ticker] = position
+		result := <-positionChannel
+		if result.err != nil {
+			err = result.err
+			continue
+		}
+		positions[result.position.Ticker] = result.position
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return positions
+	return positions, nil
 }
 
-func runPositionListProjection(eventStream infrastructure.EventStream) map[string]int {
-	positions := map[string]int{}
+// replays the events that occurred until the end of date and values them at the close of that day
+func (positionListQuery *EventStreamedPositionListQuery) GetPositionsAsOf(date string) (map[string]Position, error) {
+	positions := map[string]Position{}
 
-	for _, event := range eventStream.Get() {
-		if event.Name == portfolio.SharesAddedToPortfolioEventName {
-			ticker := event.Payload["ticker"].(string)
-			shares := event.Payload["shares"].(int)
-			currentShares, found := positions[ticker]
-			if !found {
-				positions[ticker] = shares
-			} else {
-				positions[ticker] = currentShares + shares
-			}
+	allEvents, err := positionListQuery.EventStream.Get()
+	if err != nil {
+		return nil, query.NewUnreadableEventStreamError(err)
+	}
 
+	storedEvents := []infrastructure.Event{}
+	for _, storedEvent := range allEvents {
+		occurredAt, _ := storedEvent.MetaData["occurred_at"].(string)
+		if occurredAt > date {
 			continue
 		}
+		storedEvents = append(storedEvents, storedEvent)
+	}
 
-		if event.Name == portfolio.SharesRemovedFromPortfolioEventName {
-			ticker := event.Payload["ticker"].(string)
-			shares := event.Payload["shares"].(int)
-			currentShares := positions[ticker]
+	positionProjection, err := runPositionListProjection(storedEvents, positionListQuery.LotMatchingMethod)
+	if err != nil {
+		return nil, err
+	}
 
-			if currentShares == shares {
-				delete(positions, ticker)
-				continue
-			}
+	for ticker, projected := range positionProjection {
+		price, err := positionListQuery.Prices.Close(ticker, date)
+		if err != nil {
+			return nil, err
+		}
+		position, err := positionListQuery.valuedPosition(ticker, projected, price.MulQuantity(projected.shares))
+		if err != nil {
+			return nil, err
+		}
+		positions[ticker] = position
+	}
 
-			positions[ticker] = currentShares - shares
-			continue
+	return positions, nil
+}
+
+// a position in another currency than the base currency fails the totals instead of being left out
+func CalculateTotals(positions map[string]Position, baseCurrency string) (Totals, error) {
+	value := domain.NewMoneyFromFloat(0, baseCurrency)
+	costBasis := domain.NewMoneyFromFloat(0, baseCurrency)
+	for _, position := range positions {
+		var err error
+		value, err = value.Add(position.CurrentValueInBaseCurrency)
+		if err != nil {
+			return Totals{}, err
+		}
+		costBasis, err = costBasis.Add(position.CostBasisInBaseCurrency)
+		if err != nil {
+			return Totals{}, err
 		}
+	}
+
+	unrealizedGain, _ := value.Sub(costBasis)
+	unrealizedGainPercentage, _ := unrealizedGain.PercentageOf(costBasis)
 
-		if event.Name == portfolio.TickerRenamedEventName {
-			oldSymbol := event.Payload["old"].(string)
-			newSymbol := event.Payload["new"].(string)
+	return Totals{value, costBasis, unrealizedGain, unrealizedGainPercentage}, nil
+}
 
-			currentShares := positions[oldSymbol]
-			delete(positions, oldSymbol)
+// a position without exchange rate to the base currency fails with UnknownExchangeRateError naming the pair
+func (positionListQuery *EventStreamedPositionListQuery) valuedPosition(ticker string, projected projectedPosition, value domain.Money) (Position, error) {
+	valueInBaseCurrency, err := positionListQuery.inBaseCurrency(value)
+	if err != nil {
+		return Position{}, err
+	}
+	costBasisInBaseCurrency, err := positionListQuery.inBaseCurrency(projected.costBasis)
+	if err != nil {
+		return Position{}, err
+	}
 
-			positions[newSymbol] = currentShares
+	return NewPosition(ticker, projected.shares, value, valueInBaseCurrency, projected.costBasis, costBasisInBaseCurrency), nil
+}
 
-			continue
+func (positionListQuery *EventStreamedPositionListQuery) inBaseCurrency(money domain.Money) (domain.Money, error) {
+	rate, err := positionListQuery.ExchangeRates.Rate(money.Currency(), positionListQuery.BaseCurrency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return rate.Convert(money)
+}
+
+// a stored event that can't be decoded fails the projection, the positions would be wrong without it
+func runPositionListProjection(storedEvents []infrastructure.Event, method lots.MatchingMethod) (map[string]projectedPosition, error) {
+	positions := map[string]projectedPosition{}
+	ledger := lots.NewLedger(method)
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return nil, query.NewUnreadableEventStreamError(err)
+		}
+		ledger.Apply(domainEvent)
+
+		switch domainEvent := domainEvent.(type) {
+		case *portfolio.SharesAddedToPortfolioEvent:
+			current := positions[domainEvent.Ticker()]
+			positions[domainEvent.Ticker()] = projectedPosition{current.shares.Add(domainEvent.Shares()), domainEvent.Price().Currency(), domain.Money{}}
+		case *portfolio.SharesRemovedFromPortfolioEvent:
+			ticker := domainEvent.Ticker()
+			current := positions[ticker]
+			if current.shares.Equal(domainEvent.Shares()) {
+				delete(positions, ticker)
+				continue
+			}
+			positions[ticker] = projectedPosition{current.shares.Sub(domainEvent.Shares()), current.currency, domain.Money{}}
+		case *portfolio.TickerRenamedEvent:
+			current := positions[domainEvent.Old()]
+			delete(positions, domainEvent.Old())
+			positions[domainEvent.New()] = current
+		case *portfolio.StockSplitEvent:
+			current, found := positions[domainEvent.Ticker()]
+			if !found {
+				continue
+			}
+			positions[domainEvent.Ticker()] = projectedPosition{current.shares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom()), current.currency, domain.Money{}}
+		case *portfolio.SharesConvertedEvent:
+			current, found := positions[domainEvent.Ticker()]
+			if !found {
+				continue
+			}
+			delete(positions, domainEvent.Ticker())
+			converted := current.shares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom()).Sub(domainEvent.FractionalShares())
+			held, found := positions[domainEvent.NewTicker()]
+			if found {
+				current.currency = held.currency
+			}
+			positions[domainEvent.NewTicker()] = projectedPosition{held.shares.Add(converted), current.currency, domain.Money{}}
+		case *portfolio.SharesSpunOffEvent:
+			parent, found := positions[domainEvent.Ticker()]
+			if !found {
+				continue
+			}
+			spunOff := parent.shares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom())
+			held, found := positions[domainEvent.NewTicker()]
+			if found {
+				parent.currency = held.currency
+			}
+			positions[domainEvent.NewTicker()] = projectedPosition{held.shares.Add(spunOff), parent.currency, domain.Money{}}
+		}
+	}
+
+	for ticker, position := range positions {
+		position.costBasis = domain.NewMoneyFromFloat(0, position.currency)
+		for _, lot := range ledger.OpenLots(ticker) {
+			sum, err := position.costBasis.Add(lot.CostBasis())
+			if err != nil {
+				continue
+			}
+			position.costBasis = sum
 		}
+		positions[ticker] = position
 	}
 
-	return positions
+	return positions, nil
 }
@@ -2,9 +2,11 @@ package position_list_test
 
 import (
 	"reflect"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 	"stock-monitor/query"
+	"stock-monitor/query/lots"
 	positionList "stock-monitor/query/position_list"
 	"testing"
 )
@@ -30,9 +32,9 @@ func TestPositionListProvidesCompleteListOfPositions(t *testing.T) {
 
 	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 10.00}}
 
-	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker}
-	got := positionListQuery.GetPositions()
-	want := map[string]positionList.Position{"MO": {"MO", 25, 250.00}}
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(25), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(902.25, "EUR"), domain.NewMoneyFromFloat(902.25, "EUR"))}
 
 	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
@@ -53,8 +55,9 @@ func TestPositionIsRemovedWhenCompletelySold(t *testing.T) {
 		},
 	}
 
-	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, query.FakeValueTracker{}}
-	_, found := positionListQuery.GetPositions()["MO"]
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, query.FakeValueTracker{}, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	_, found := got["MO"]
 
 	if found {
 		t.Errorf("Expected no position of MO in portfolio but found one")
@@ -87,9 +90,9 @@ func TestPositionListHandlesTickerRenames(t *testing.T) {
 
 	valueTracker := query.FakeValueTracker{map[string]float32{"FOO": 10.00}}
 
-	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker}
-	got := positionListQuery.GetPositions()
-	want := map[string]positionList.Position{"FOO": {"FOO", 25, 250.00}}
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{"FOO": positionList.NewPosition("FOO", domain.NewQuantityFromInt(25), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(902.25, "EUR"), domain.NewMoneyFromFloat(902.25, "EUR"))}
 
 	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
@@ -132,9 +135,128 @@ func TestPositionListHandlesMultipleTickerRenames(t *testing.T) {
 
 	valueTracker := query.FakeValueTracker{map[string]float32{"BAR": 10.00}}
 
-	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker}
-	got := positionListQuery.GetPositions()
-	want := map[string]positionList.Position{"BAR": {"BAR", 35, 350.00}}
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{"BAR": positionList.NewPosition("BAR", domain.NewQuantityFromInt(35), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(1106.75, "EUR"), domain.NewMoneyFromFloat(1106.75, "EUR"))}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestPositionListHandlesStockSplits(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 40.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2002-01-01"},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "MO", "ratio_from": 1, "ratio_to": 4, "date": "2002-01-02"},
+			map[string]interface{}{"occurred_at": "2002-01-02"},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 10.00, "shares": 5},
+			map[string]interface{}{"occurred_at": "2002-01-03"},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "PG", "ratio_from": 1, "ratio_to": 2, "date": "2002-01-04"},
+			map[string]interface{}{"occurred_at": "2002-01-04"},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 10.00}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(35), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(350.00, "EUR"))}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestPositionListHandlesConversions(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 30.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+		{
+			portfolio.SharesConvertedEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 4, "ratio_to": 3, "fractional_shares": "0.5", "cash_in_lieu": "10", "currency": "EUR", "date": "2001-01-03"},
+			map[string]interface{}{"occurred_at": "2001-01-03", "version": 1},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"PM": 50.00}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{"PM": positionList.NewPosition("PM", domain.NewQuantityFromInt(7), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(350.00, "EUR"), domain.NewMoneyFromFloat(280.00, "EUR"), domain.NewMoneyFromFloat(280.00, "EUR"))}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestPositionListHandlesSpinOffs(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 30.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+		{
+			portfolio.SharesSpunOffEventName,
+			map[string]interface{}{"ticker": "MO", "new_ticker": "PM", "ratio_from": 2, "ratio_to": 1, "cost_basis_percentage": "20", "date": "2001-01-03"},
+			map[string]interface{}{"occurred_at": "2001-01-03", "version": 1},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 40.00, "PM": 50.00}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{
+		"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(400.00, "EUR"), domain.NewMoneyFromFloat(400.00, "EUR"), domain.NewMoneyFromFloat(240.00, "EUR"), domain.NewMoneyFromFloat(240.00, "EUR")),
+		"PM": positionList.NewPosition("PM", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(250.00, "EUR"), domain.NewMoneyFromFloat(60.00, "EUR"), domain.NewMoneyFromFloat(60.00, "EUR")),
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestPositionListHandlesFractionalShares(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.00, "shares": "0.4213", "date": "2002-01-01"},
+			map[string]interface{}{"occurred_at": "2002-01-01", "version": 2},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.00, "shares": 1},
+			map[string]interface{}{"occurred_at": "2002-01-02"},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.00, "shares": "0.2213", "date": "2002-01-03"},
+			map[string]interface{}{"occurred_at": "2002-01-03", "version": 3},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 10.00}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	shares, _ := domain.NewQuantity("1.2")
+	want := map[string]positionList.Position{"MO": positionList.NewPosition("MO", shares, domain.NewMoneyFromFloat(12.00, "EUR"), domain.NewMoneyFromFloat(12.00, "EUR"), domain.NewMoneyFromFloat(24.00, "EUR"), domain.NewMoneyFromFloat(24.00, "EUR"))}
 
 	if reflect.DeepEqual(got, want) == false {
 		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
@@ -162,6 +284,193 @@ func BenchmarkPositionListQuery_GetPositions(b *testing.B) {
 
 	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 10.00, "PG": 20.00, "GIS": 30.00}}
 
-	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker}
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
 	positionListQuery.GetPositions()
 }
+
+func TestPositionValuesAreConvertedToBaseCurrency(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "40", "currency": "USD", "shares": "10", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 3},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"MO": 50.00}}
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, exchangeRates, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	got, _ := positionListQuery.GetPositions()
+	want := map[string]positionList.Position{"MO": positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(500.00, "USD"), domain.NewMoneyFromFloat(400.00, "EUR"), domain.NewMoneyFromFloat(400.00, "USD"), domain.NewMoneyFromFloat(320.00, "EUR"))}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Positions unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestPositionsProvideUnrealizedGain(t *testing.T) {
+	position := positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(500.00, "USD"), domain.NewMoneyFromFloat(400.00, "EUR"), domain.NewMoneyFromFloat(400.00, "USD"), domain.NewMoneyFromFloat(320.00, "EUR"))
+
+	if reflect.DeepEqual(position.AverageBuyPrice, domain.NewMoneyFromFloat(40.00, "USD")) == false {
+		t.Errorf("Unexpected average buy price: %#v", position.AverageBuyPrice.String())
+	}
+	if reflect.DeepEqual(position.UnrealizedGain, domain.NewMoneyFromFloat(100.00, "USD")) == false {
+		t.Errorf("Unexpected unrealized gain: %#v", position.UnrealizedGain.String())
+	}
+	if position.UnrealizedGainPercentage.String() != "25" {
+		t.Errorf("Unexpected unrealized gain percentage: %#v", position.UnrealizedGainPercentage.String())
+	}
+}
+
+func TestTotalsAreSummedInBaseCurrency(t *testing.T) {
+	positions := map[string]positionList.Position{
+		"MO":  positionList.NewPosition("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(500.00, "USD"), domain.NewMoneyFromFloat(400.00, "EUR"), domain.NewMoneyFromFloat(400.00, "USD"), domain.NewMoneyFromFloat(320.00, "EUR")),
+		"SAP": positionList.NewPosition("SAP", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(100.50, "EUR"), domain.NewMoneyFromFloat(100.50, "EUR"), domain.NewMoneyFromFloat(80.50, "EUR"), domain.NewMoneyFromFloat(80.50, "EUR")),
+	}
+
+	got, err := positionList.CalculateTotals(positions, "EUR")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got.Value, domain.NewMoneyFromFloat(500.50, "EUR")) == false {
+		t.Errorf("Unexpected total value: %#v", got.Value.String())
+	}
+	if reflect.DeepEqual(got.CostBasis, domain.NewMoneyFromFloat(400.50, "EUR")) == false {
+		t.Errorf("Unexpected total cost basis: %#v", got.CostBasis.String())
+	}
+	if reflect.DeepEqual(got.UnrealizedGain, domain.NewMoneyFromFloat(100.00, "EUR")) == false {
+		t.Errorf("Unexpected total unrealized gain: %#v", got.UnrealizedGain.String())
+	}
+	if got.UnrealizedGainPercentage.String() != "24.97" {
+		t.Errorf("Unexpected total unrealized gain percentage: %#v", got.UnrealizedGainPercentage.String())
+	}
+}
+
+func TestTotalsFailForPositionsNotInBaseCurrency(t *testing.T) {
+	positions := map[string]positionList.Position{
+		"SAP": positionList.NewPosition("SAP", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(100.50, "EUR"), domain.NewMoneyFromFloat(100.50, "EUR"), domain.NewMoneyFromFloat(80.50, "EUR"), domain.NewMoneyFromFloat(80.50, "EUR")),
+		"VOD": positionList.NewPosition("VOD", domain.NewQuantityFromInt(1), domain.NewMoneyFromFloat(1.00, "GBP"), domain.NewMoneyFromFloat(1.00, "GBP"), domain.NewMoneyFromFloat(1.00, "GBP"), domain.NewMoneyFromFloat(1.00, "GBP")),
+	}
+
+	_, err := positionList.CalculateTotals(positions, "EUR")
+
+	_, ok := err.(*domain.CurrencyMismatchError)
+	if !ok {
+		t.Errorf("Expected CurrencyMismatchError but got %#v", err)
+	}
+}
+
+func TestPositionsAsOfReplayOnlyEventsUntilTheDate(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2022-06-01"},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 30.00, "shares": 5},
+			map[string]interface{}{"occurred_at": "2022-12-31"},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 40.00, "shares": 15},
+			map[string]interface{}{"occurred_at": "2023-01-05"},
+		},
+	}
+	prices := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{"MO": {
+		"2022-12-30": domain.NewMoneyFromFloat(25, "EUR"),
+		"2023-01-05": domain.NewMoneyFromFloat(40, "EUR"),
+	}}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, query.FakeValueTracker{}, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, prices}
+	got, err := positionListQuery.GetPositionsAsOf("2022-12-31")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	position := got["MO"]
+	if len(got) != 1 || position.Shares.String() != "15" || position.CurrentValue.String() != "375 EUR" || position.CostBasis.String() != "350 EUR" {
+		t.Errorf("Positions unequal got: %#v", got)
+	}
+}
+
+func TestPositionsAsOfFailWithoutClosingPrice(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2022-06-01"},
+		},
+	}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, query.FakeValueTracker{}, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	_, err := positionListQuery.GetPositionsAsOf("2022-12-31")
+
+	if err == nil {
+		t.Errorf("Expected error for missing closing price")
+	}
+}
+
+func TestPositionListFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	events := []infrastructure.Event{
+		{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}},
+	}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, query.FakeValueTracker{}, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	_, err := positionListQuery.GetPositions()
+
+	if err == nil {
+		t.Errorf("Expected an error for an event that can't be decoded")
+	}
+}
+
+type currencyValueTracker struct {
+	currencies map[string]string
+}
+
+func (valueTracker currencyValueTracker) Current(ticker string, currency string) float32 {
+	valueTracker.currencies[ticker] = currency
+	return 10
+}
+
+func TestValuesAreTrackedInTheCurrencyOfTheTicker(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 20.45, "shares": 10, "currency": "USD"},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+	}
+
+	valueTracker := currencyValueTracker{map[string]string{}}
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, query.FakeExchangeRateProvider{}, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	positionListQuery.GetPositions()
+
+	if valueTracker.currencies["MO"] != "USD" {
+		t.Errorf("Unexpected currency. Expected:%#v Got:%#v", "USD", valueTracker.currencies["MO"])
+	}
+}
+
+func TestPositionsFailWithoutExchangeRateToBaseCurrency(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "VOD", "price": "1", "currency": "GBP", "shares": "10", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 3},
+		},
+	}
+
+	valueTracker := query.FakeValueTracker{map[string]float32{"VOD": 1.10}}
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+
+	positionListQuery := positionList.EventStreamedPositionListQuery{&infrastructure.InMemoryEventStream{events}, valueTracker, exchangeRates, "EUR", lots.FirstInFirstOut, query.FakePriceHistoryProvider{}}
+	_, err := positionListQuery.GetPositions()
+
+	_, ok := err.(*query.UnknownExchangeRateError)
+	if !ok {
+		t.Errorf("Expected UnknownExchangeRateError but got %#v", err)
+	}
+}
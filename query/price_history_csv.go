@@ -0,0 +1,60 @@
+package query
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadDailyPricesCsv reads daily OHLC rows with a header like "Date,Open,High,Low,Close,Volume",
+// columns are found by name, only date and close are required
+func ReadDailyPricesCsv(reader io.Reader, currency string) ([]DailyPrice, error) {
+	rows, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, NewInvalidPriceCsvError(1, "header missing")
+	}
+
+	columns := map[string]int{}
+	for index, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	for _, required := range []string{"date", "close"} {
+		if _, found := columns[required]; !found {
+			return nil, NewInvalidPriceCsvError(1, "column missing: "+required)
+		}
+	}
+
+	prices := []DailyPrice{}
+	for index, row := range rows[1:] {
+		line := index + 2
+		value := func(column string) string {
+			position, found := columns[column]
+			if !found || position >= len(row) {
+				return strings.TrimSpace(row[columns["close"]])
+			}
+			return strings.TrimSpace(row[position])
+		}
+
+		date := value("date")
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, NewInvalidPriceCsvError(line, "invalid date: "+date)
+		}
+		// rows of days without trading are left out, e.g. "null" in exports of yahoo finance
+		if _, err := strconv.ParseFloat(value("close"), 64); err != nil {
+			continue
+		}
+
+		price, err := newDailyPrice(date, value("open"), value("high"), value("low"), value("close"), currency)
+		if err != nil {
+			return nil, NewInvalidPriceCsvError(line, err.Error())
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,53 @@
+package query_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/query"
+	"strings"
+	"testing"
+)
+
+func TestDailyPricesAreReadFromCsv(t *testing.T) {
+	csv := "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"2000-01-03,9.5,10.5,9,10,9.8,1000\n" +
+		"2000-01-04,null,null,null,null,null,null\n" +
+		"2000-01-05,10,11,10,11,10.8,1200\n"
+
+	got, err := query.ReadDailyPricesCsv(strings.NewReader(csv), "USD")
+
+	want := []query.DailyPrice{
+		{"2000-01-03", domain.NewMoneyFromFloat(9.5, "USD"), domain.NewMoneyFromFloat(10.5, "USD"), domain.NewMoneyFromFloat(9, "USD"), domain.NewMoneyFromFloat(10, "USD")},
+		{"2000-01-05", domain.NewMoneyFromFloat(10, "USD"), domain.NewMoneyFromFloat(11, "USD"), domain.NewMoneyFromFloat(10, "USD"), domain.NewMoneyFromFloat(11, "USD")},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected prices. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestCsvWithOnlyClosesUsesCloseForTheWholeDay(t *testing.T) {
+	got, err := query.ReadDailyPricesCsv(strings.NewReader("date,close\n2000-01-03,10\n"), "EUR")
+
+	price := domain.NewMoneyFromFloat(10, "EUR")
+	want := []query.DailyPrice{{"2000-01-03", price, price, price, price}}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected prices. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestInvalidCsvIsRejectedWithLine(t *testing.T) {
+	for _, csv := range []string{"Date,Open\n2000-01-03,10\n", "Date,Close\n2000-01-03,10\n03.01.2000,11\n"} {
+		_, err := query.ReadDailyPricesCsv(strings.NewReader(csv), "EUR")
+
+		_, ok := err.(*query.InvalidPriceCsvError)
+		if !ok {
+			t.Errorf("Expected InvalidPriceCsvError but got %#v", err)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package query
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"sync"
+	"time"
+)
+
+type PriceHistoryProvider interface {
+	// Close is the last closing price of the ticker on or before the date
+	Close(ticker string, date string) (domain.Money, error)
+	// Closes are the daily prices of the ticker from and including the first until and including the last date
+	Closes(ticker string, from string, to string) ([]DailyPrice, error)
+}
+
+type PriceStore interface {
+	PriceHistoryProvider
+	// Save adds the daily prices of the ticker, prices of days already stored are replaced
+	Save(ticker string, prices []DailyPrice) error
+	// Record merges the price into the stored price of its day, the open is kept, the range widened and the close replaced
+	Record(ticker string, price DailyPrice) error
+}
+
+type DailyPrice struct {
+	Date  string
+	Open  domain.Money
+	High  domain.Money
+	Low   domain.Money
+	Close domain.Money
+}
+
+type FakePriceHistoryProvider struct {
+	PriceMap map[string]map[string]domain.Money
+}
+
+func (provider FakePriceHistoryProvider) Close(ticker string, date string) (domain.Money, error) {
+	prices := map[string]DailyPrice{}
+	for priceDate, price := range provider.PriceMap[ticker] {
+		prices[priceDate] = DailyPrice{priceDate, price, price, price, price}
+	}
+
+	return closeOnOrBefore(prices, sortedDates(prices), ticker, date)
+}
+
+func (provider FakePriceHistoryProvider) Closes(ticker string, from string, to string) ([]DailyPrice, error) {
+	prices := map[string]DailyPrice{}
+	for priceDate, price := range provider.PriceMap[ticker] {
+		prices[priceDate] = DailyPrice{priceDate, price, price, price, price}
+	}
+
+	return closesBetween(prices, from, to), nil
+}
+
+type storedPrice struct {
+	Open     string `json:"open"`
+	High     string `json:"high"`
+	Low      string `json:"low"`
+	Close    string `json:"close"`
+	Currency string `json:"currency"`
+}
+
+// keeps daily prices by ticker and date in a json file, e.g. {"MO": {"2023-01-02": {"open": "45", "high": "45.5", "low": "44.8", "close": "45.1", "currency": "USD"}}}
+// the file is only read again after it was changed
+type FilePriceStore struct {
+	path     string
+	mutex    *sync.Mutex
+	prices   map[string]map[string]DailyPrice
+	dates    map[string][]string
+	modified time.Time
+}
+
+func NewFilePriceStore(path string) *FilePriceStore {
+	return &FilePriceStore{path: path, mutex: &sync.Mutex{}}
+}
+
+func (store *FilePriceStore) Close(ticker string, date string) (domain.Money, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err := store.load()
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return closeOnOrBefore(store.prices[ticker], store.dates[ticker], ticker, date)
+}
+
+func (store *FilePriceStore) Closes(ticker string, from string, to string) ([]DailyPrice, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err := store.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return closesBetween(store.prices[ticker], from, to), nil
+}
+
+func (store *FilePriceStore) Save(ticker string, prices []DailyPrice) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err := store.load()
+	if err != nil {
+		return err
+	}
+
+	if store.prices[ticker] == nil {
+		store.prices[ticker] = map[string]DailyPrice{}
+	}
+	for _, price := range prices {
+		store.prices[ticker][price.Date] = price
+	}
+
+	return store.write(ticker)
+}
+
+// the price is merged while the store is locked, so concurrent records of a day don't get lost
+func (store *FilePriceStore) Record(ticker string, price DailyPrice) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err := store.load()
+	if err != nil {
+		return err
+	}
+
+	if store.prices[ticker] == nil {
+		store.prices[ticker] = map[string]DailyPrice{}
+	}
+	store.prices[ticker][price.Date] = mergedPrice(store.prices[ticker][price.Date], price)
+
+	return store.write(ticker)
+}
+
+// prices of another currency replace the recorded one
+func mergedPrice(recorded DailyPrice, price DailyPrice) DailyPrice {
+	if recorded.Date == "" || recorded.Close.Currency() != price.Close.Currency() {
+		return price
+	}
+
+	price.Open = recorded.Open
+	if difference, _ := recorded.High.Sub(price.High); difference.IsPositive() {
+		price.High = recorded.High
+	}
+	if difference, _ := recorded.Low.Sub(price.Low); difference.IsNegative() {
+		price.Low = recorded.Low
+	}
+
+	return price
+}
+
+// writes all prices after the ones of the ticker changed, the store has to be locked
+func (store *FilePriceStore) write(ticker string) error {
+	store.dates[ticker] = sortedDates(store.prices[ticker])
+
+	stored := map[string]map[string]storedPrice{}
+	for storedTicker, dailyPrices := range store.prices {
+		stored[storedTicker] = map[string]storedPrice{}
+		for date, price := range dailyPrices {
+			stored[storedTicker][date] = storedPrice{price.Open.Amount(), price.High.Amount(), price.Low.Amount(), price.Close.Amount(), price.Close.Currency()}
+		}
+	}
+	content, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	err = infrastructure.WriteAtomically(store.path, content)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(store.path)
+	if err != nil {
+		return err
+	}
+	store.modified = info.ModTime()
+
+	return nil
+}
+
+// a missing file is an empty store
+func (store *FilePriceStore) load() error {
+	info, err := os.Stat(store.path)
+	if os.IsNotExist(err) {
+		store.prices = map[string]map[string]DailyPrice{}
+		store.dates = map[string][]string{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if store.prices != nil && info.ModTime().Equal(store.modified) {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(store.path)
+	if err != nil {
+		return err
+	}
+	stored := map[string]map[string]storedPrice{}
+	err = json.Unmarshal(content, &stored)
+	if err != nil {
+		return err
+	}
+
+	prices := map[string]map[string]DailyPrice{}
+	dates := map[string][]string{}
+	for ticker, dailyPrices := range stored {
+		prices[ticker] = map[string]DailyPrice{}
+		for date, price := range dailyPrices {
+			dailyPrice, err := newDailyPrice(date, price.Open, price.High, price.Low, price.Close, price.Currency)
+			if err != nil {
+				return err
+			}
+			prices[ticker][date] = dailyPrice
+		}
+		dates[ticker] = sortedDates(prices[ticker])
+	}
+	store.prices = prices
+	store.dates = dates
+	store.modified = info.ModTime()
+
+	return nil
+}
+
+func newDailyPrice(date string, open string, high string, low string, close string, currency string) (DailyPrice, error) {
+	amounts := []domain.Money{}
+	for _, amount := range []string{open, high, low, close} {
+		money, err := domain.NewMoney(amount, currency)
+		if err != nil {
+			return DailyPrice{}, err
+		}
+		amounts = append(amounts, money)
+	}
+
+	return DailyPrice{date, amounts[0], amounts[1], amounts[2], amounts[3]}, nil
+}
+
+func closeOnOrBefore(prices map[string]DailyPrice, dates []string, ticker string, date string) (domain.Money, error) {
+	// the index of the first date after the given date
+	index := sort.Search(len(dates), func(i int) bool {
+		return dates[i] > date
+	})
+	if index == 0 {
+		return domain.Money{}, NewUnknownPriceError(ticker, date)
+	}
+
+	return prices[dates[index-1]].Close, nil
+}
+
+func sortedDates(prices map[string]DailyPrice) []string {
+	dates := []string{}
+	for date := range prices {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	return dates
+}
+
+func closesBetween(prices map[string]DailyPrice, from string, to string) []DailyPrice {
+	closes := []DailyPrice{}
+	for date, price := range prices {
+		if date >= from && date <= to {
+			closes = append(closes, price)
+		}
+	}
+	sort.Slice(closes, func(i, j int) bool {
+		return closes[i].Date < closes[j].Date
+	})
+
+	return closes
+}
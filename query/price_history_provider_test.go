@@ -0,0 +1,89 @@
+package query_test
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/query"
+	"testing"
+)
+
+func TestFilePriceStoreReadsLastCloseOnOrBeforeDate(t *testing.T) {
+	file, _ := ioutil.TempFile("", "price_history_*.json")
+	defer os.Remove(file.Name())
+	file.WriteString(`{"MO": {"2000-01-03": {"open": "9", "high": "10.5", "low": "9", "close": "10", "currency": "USD"}, "2000-01-05": {"open": "10", "high": "11", "low": "10", "close": "11", "currency": "USD"}}}`)
+	file.Close()
+
+	store := query.NewFilePriceStore(file.Name())
+	price, err := store.Close("MO", "2000-01-04")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if price.String() != "10 USD" {
+		t.Errorf("Unexpected price. Expected:%#v Got:%#v", "10 USD", price.String())
+	}
+}
+
+func TestFilePriceStoreKeepsSavedPrices(t *testing.T) {
+	file, _ := ioutil.TempFile("", "price_history_*.json")
+	os.Remove(file.Name())
+	defer os.Remove(file.Name())
+
+	store := query.NewFilePriceStore(file.Name())
+	price := domain.NewMoneyFromFloat(10, "USD")
+	err := store.Save("MO", []query.DailyPrice{{"2000-01-03", price, price, price, price}, {"2000-01-04", price, price, price, price}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	replaced := domain.NewMoneyFromFloat(12, "USD")
+	store.Save("MO", []query.DailyPrice{{"2000-01-04", replaced, replaced, replaced, replaced}})
+
+	got, err := query.NewFilePriceStore(file.Name()).Closes("MO", "2000-01-01", "2000-01-31")
+
+	want := []query.DailyPrice{{"2000-01-03", price, price, price, price}, {"2000-01-04", replaced, replaced, replaced, replaced}}
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected prices. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestThereIsNoPriceBeforeTheFirstClose(t *testing.T) {
+	provider := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{"MO": {"2000-01-03": domain.NewMoneyFromFloat(10, "USD")}}}
+	_, err := provider.Close("MO", "2000-01-02")
+
+	_, ok := err.(*query.UnknownPriceError)
+	if !ok {
+		t.Errorf("Expected UnknownPriceError but got %#v", err)
+	}
+}
+
+func TestFilePriceStoreMergesConcurrentlyRecordedPrices(t *testing.T) {
+	file, _ := ioutil.TempFile("", "price_history_*.json")
+	os.Remove(file.Name())
+	defer os.Remove(file.Name())
+	store := query.NewFilePriceStore(file.Name())
+
+	done := make(chan bool)
+	for value := 1; value <= 20; value++ {
+		go func(value int) {
+			price := domain.NewMoneyFromFloat(float64(value), "USD")
+			err := store.Record("MO", query.DailyPrice{"2000-01-03", price, price, price, price})
+			if err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+			}
+			done <- true
+		}(value)
+	}
+	for value := 1; value <= 20; value++ {
+		<-done
+	}
+
+	got, _ := query.NewFilePriceStore(file.Name()).Closes("MO", "2000-01-03", "2000-01-03")
+	if len(got) != 1 || got[0].High.String() != "20 USD" || got[0].Low.String() != "1 USD" {
+		t.Errorf("Expected the range of all recorded prices but got %#v", got)
+	}
+}
@@ -0,0 +1,120 @@
+package realized_gains
+
+import (
+	"stock-monitor/domain"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/lots"
+	"time"
+)
+
+type RealizedGainsQueryInterface interface {
+	GetRealizedGains(method lots.MatchingMethod, filter Filter) (RealizedGains, error)
+}
+
+type RealizedGain struct {
+	Ticker         string
+	Aliases        []string
+	Date           string
+	NumberOfShares domain.Quantity
+	Price          domain.Money
+	Fees           domain.Money
+	Proceeds       domain.Money
+	CostBasis      domain.Money
+	Gain           domain.Money
+	MatchedLots    []lots.Lot
+}
+
+type RealizedGains struct {
+	Sales     []RealizedGain
+	TotalGain domain.Money
+}
+
+type RealizedGainsQuery struct {
+	EventStream   infrastructure.EventStream
+	ExchangeRates query.ExchangeRateProvider
+	BaseCurrency  string
+}
+
+type Filter struct {
+	year   int
+	ticker string
+}
+
+func NewFilter() Filter {
+	return Filter{0, ""}
+}
+
+func (filter *Filter) ByYear(year int) {
+	filter.year = year
+}
+
+func (filter *Filter) ByTicker(ticker string) {
+	filter.ticker = ticker
+}
+
+// a gain without exchange rate to the base currency fails the query with UnknownExchangeRateError
+func (realizedGainsQuery *RealizedGainsQuery) GetRealizedGains(method lots.MatchingMethod, filter Filter) (RealizedGains, error) {
+	realizedGains := RealizedGains{[]RealizedGain{}, domain.NewMoneyFromFloat(0, realizedGainsQuery.BaseCurrency)}
+
+	ledger, err := lots.Project(realizedGainsQuery.EventStream, method)
+	if err != nil {
+		return RealizedGains{}, err
+	}
+
+	for _, sale := range ledger.Sales() {
+		if !saleMatchesFilter(sale, filter) {
+			continue
+		}
+
+		realizedGain := newRealizedGain(sale)
+		realizedGains.Sales = append(realizedGains.Sales, realizedGain)
+
+		gain, err := realizedGainsQuery.inBaseCurrency(realizedGain.Gain)
+		if err != nil {
+			return RealizedGains{}, err
+		}
+		realizedGains.TotalGain, err = realizedGains.TotalGain.Add(gain)
+		if err != nil {
+			return RealizedGains{}, err
+		}
+	}
+
+	return realizedGains, nil
+}
+
+// proceeds are net of the fees and taxes of the sale
+func newRealizedGain(sale lots.Sale) RealizedGain {
+	proceeds, _ := sale.Price.MulQuantity(sale.NumberOfShares).Sub(sale.Fees)
+	costBasis := domain.NewMoneyFromFloat(0, proceeds.Currency())
+	for _, lot := range sale.MatchedLots {
+		sum, err := costBasis.Add(lot.CostBasis())
+		if err != nil {
+			continue
+		}
+		costBasis = sum
+	}
+	gain, _ := proceeds.Sub(costBasis)
+
+	return RealizedGain{sale.Ticker, sale.Aliases, sale.Date, sale.NumberOfShares, sale.Price, sale.Fees, proceeds, costBasis, gain, sale.MatchedLots}
+}
+
+func (realizedGainsQuery *RealizedGainsQuery) inBaseCurrency(money domain.Money) (domain.Money, error) {
+	rate, err := realizedGainsQuery.ExchangeRates.Rate(money.Currency(), realizedGainsQuery.BaseCurrency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return rate.Convert(money)
+}
+
+func saleMatchesFilter(sale lots.Sale, filter Filter) bool {
+	saleDate, _ := time.Parse("2006-01-02", sale.Date)
+	if filter.year != 0 && saleDate.Year() != filter.year {
+		return false
+	}
+	if filter.ticker != "" && sale.Ticker != filter.ticker {
+		return false
+	}
+	return true
+}
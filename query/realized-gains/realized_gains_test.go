@@ -0,0 +1,150 @@
+package realized_gains_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/lots"
+	realized_gains "stock-monitor/query/realized-gains"
+	"testing"
+)
+
+func events() []infrastructure.Event {
+	return []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "10", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 3},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "20", "currency": "EUR", "shares": "10", "date": "2001-02-01"},
+			map[string]interface{}{"occurred_at": "2001-02-01", "version": 3},
+		},
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "KO", "price": "50", "currency": "USD", "shares": "4", "date": "2001-02-01"},
+			map[string]interface{}{"occurred_at": "2001-02-01", "version": 3},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "currency": "EUR", "shares": "15", "date": "2001-03-01"},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 4},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "KO", "price": "75", "currency": "USD", "shares": "4", "date": "2002-01-02"},
+			map[string]interface{}{"occurred_at": "2002-01-02", "version": 4},
+		},
+	}
+}
+
+func TestRealizedGainsAreCalculatedPerSale(t *testing.T) {
+	realizedGainsQuery := realized_gains.RealizedGainsQuery{&infrastructure.InMemoryEventStream{events()}, query.FakeExchangeRateProvider{}, "EUR"}
+
+	filter := realized_gains.NewFilter()
+	filter.ByTicker("MO")
+	got, _ := realizedGainsQuery.GetRealizedGains(lots.FirstInFirstOut, filter)
+	want := realized_gains.RealizedGains{
+		[]realized_gains.RealizedGain{
+			{
+				"MO",
+				[]string{},
+				"2001-03-01",
+				domain.NewQuantityFromInt(15),
+				domain.NewMoneyFromFloat(30, "EUR"),
+				domain.NewMoneyFromFloat(0, "EUR"),
+				domain.NewMoneyFromFloat(450, "EUR"),
+				domain.NewMoneyFromFloat(200, "EUR"),
+				domain.NewMoneyFromFloat(250, "EUR"),
+				[]lots.Lot{
+					{1, "MO", "2001-01-01", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+					{2, "MO", "2001-02-01", domain.NewQuantityFromInt(5), domain.NewMoneyFromFloat(20, "EUR"), domain.NewMoneyFromFloat(0, "EUR")},
+				},
+			},
+		},
+		domain.NewMoneyFromFloat(250, "EUR"),
+	}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Realized gains unequal got: %#v, want: %#v", got, want)
+	}
+}
+
+func TestRealizedGainsDependOnMatchingMethod(t *testing.T) {
+	realizedGainsQuery := realized_gains.RealizedGainsQuery{&infrastructure.InMemoryEventStream{events()}, query.FakeExchangeRateProvider{}, "EUR"}
+
+	filter := realized_gains.NewFilter()
+	filter.ByTicker("MO")
+
+	lifoGains, _ := realizedGainsQuery.GetRealizedGains(lots.LastInFirstOut, filter)
+	averageGains, _ := realizedGainsQuery.GetRealizedGains(lots.AverageCost, filter)
+	lifo := lifoGains.TotalGain
+	average := averageGains.TotalGain
+
+	if reflect.DeepEqual(lifo, domain.NewMoneyFromFloat(200, "EUR")) == false {
+		t.Errorf("Unexpected lifo gain: %#v", lifo.String())
+	}
+	if reflect.DeepEqual(average, domain.NewMoneyFromFloat(225, "EUR")) == false {
+		t.Errorf("Unexpected average cost gain: %#v", average.String())
+	}
+}
+
+func TestRealizedGainsCanBeFilteredByYearAndAreTotaledInBaseCurrency(t *testing.T) {
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+	realizedGainsQuery := realized_gains.RealizedGainsQuery{&infrastructure.InMemoryEventStream{events()}, exchangeRates, "EUR"}
+
+	filter := realized_gains.NewFilter()
+	filter.ByYear(2002)
+	got, _ := realizedGainsQuery.GetRealizedGains(lots.FirstInFirstOut, filter)
+
+	if len(got.Sales) != 1 || got.Sales[0].Ticker != "KO" {
+		t.Errorf("Expected only the sale of KO but got %#v", got.Sales)
+	}
+	if reflect.DeepEqual(got.Sales[0].Gain, domain.NewMoneyFromFloat(100, "USD")) == false {
+		t.Errorf("Unexpected gain: %#v", got.Sales[0].Gain.String())
+	}
+	if reflect.DeepEqual(got.TotalGain, domain.NewMoneyFromFloat(80, "EUR")) == false {
+		t.Errorf("Unexpected total gain: %#v", got.TotalGain.String())
+	}
+}
+
+func TestFeesAndTaxesReduceRealizedGains(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "10", "fee": "2", "taxes": "1", "currency": "EUR", "shares": "10", "date": "2001-01-01"},
+			map[string]interface{}{"occurred_at": "2001-01-01", "version": 4},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "1", "taxes": "0", "currency": "EUR", "shares": "4", "date": "2001-03-01", "lots": ""},
+			map[string]interface{}{"occurred_at": "2001-03-01", "version": 6},
+		},
+	}
+	realizedGainsQuery := realized_gains.RealizedGainsQuery{&infrastructure.InMemoryEventStream{events}, query.FakeExchangeRateProvider{}, "EUR"}
+
+	realizedGains, _ := realizedGainsQuery.GetRealizedGains(lots.FirstInFirstOut, realized_gains.NewFilter())
+	got := realizedGains.Sales[0]
+
+	if reflect.DeepEqual(got.Proceeds, domain.NewMoneyFromFloat(119, "EUR")) == false {
+		t.Errorf("Unexpected proceeds: %#v", got.Proceeds.String())
+	}
+	if reflect.DeepEqual(got.CostBasis, domain.NewMoneyFromFloat(41.2, "EUR")) == false {
+		t.Errorf("Unexpected cost basis: %#v", got.CostBasis.String())
+	}
+	if reflect.DeepEqual(got.Gain, domain.NewMoneyFromFloat(77.8, "EUR")) == false {
+		t.Errorf("Unexpected gain: %#v", got.Gain.String())
+	}
+}
+
+func TestRealizedGainsFailWhenAnEventCanNotBeDecoded(t *testing.T) {
+	realizedGainsQuery := realized_gains.RealizedGainsQuery{&infrastructure.InMemoryEventStream{append(events(), infrastructure.Event{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}})}, query.FakeExchangeRateProvider{}, "EUR"}
+	_, err := realizedGainsQuery.GetRealizedGains(lots.FirstInFirstOut, realized_gains.NewFilter())
+
+	if err == nil {
+		t.Errorf("Expected realized gains to fail for an event that can't be decoded")
+	}
+}
@@ -0,0 +1,39 @@
+package savings_plans
+
+import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	"time"
+)
+
+type SavingsPlansQueryInterface interface {
+	GetSavingsPlans() (SavingsPlans, error)
+}
+
+type SavingsPlans struct {
+	Plans         []savings_plan.Plan
+	PendingOrders []savings_plan.PendingOrder
+}
+
+type SavingsPlansQuery struct {
+	EventStream infrastructure.EventStream
+}
+
+// pending orders are the ones due until today, oldest first
+func (savingsPlansQuery *SavingsPlansQuery) GetSavingsPlans() (SavingsPlans, error) {
+	plans := savings_plan.NewSavingsPlans()
+	storedEvents, err := savingsPlansQuery.EventStream.Get()
+	if err != nil {
+		return SavingsPlans{}, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return SavingsPlans{}, err
+		}
+		plans.Apply(domainEvent)
+	}
+
+	return SavingsPlans{plans.Plans(), plans.PendingOrders(time.Now().Format("2006-01-02"))}, nil
+}
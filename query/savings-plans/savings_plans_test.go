@@ -0,0 +1,49 @@
+package savings_plans_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/savings_plan"
+	"stock-monitor/infrastructure"
+	savings_plans "stock-monitor/query/savings-plans"
+	"testing"
+)
+
+func TestSavingsPlansListPlansAndTheirPendingOrders(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				savings_plan.SavingsPlanDefinedEventName,
+				map[string]interface{}{"id": 1, "ticker": "MO", "amount": "100", "currency": "EUR", "shares": "0", "interval": "monthly", "execution_day": 15, "start": "2000-01-01", "end": "2000-03-31"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 1},
+			},
+			{
+				savings_plan.OrderExecutedEventName,
+				map[string]interface{}{"plan_id": 1, "due_date": "2000-02-15", "shares": "4", "price": "25", "currency": "EUR"},
+				map[string]interface{}{"occurred_at": "2000-02-16", "version": 1},
+			},
+		},
+	}
+	query := savings_plans.SavingsPlansQuery{&eventStream}
+
+	got, _ := query.GetSavingsPlans()
+
+	amount := domain.NewMoneyFromFloat(100, "EUR")
+	shares := domain.NewQuantityFromInt(0)
+	want := savings_plans.SavingsPlans{
+		[]savings_plan.Plan{{1, "MO", amount, shares, savings_plan.Monthly, 15, "2000-01-01", "2000-03-31"}},
+		[]savings_plan.PendingOrder{{1, "MO", "2000-01-15", amount, shares}, {1, "MO", "2000-03-15", amount, shares}},
+	}
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected savings plans. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestSavingsPlansFailWhenAnEventCanNotBeDecoded(t *testing.T) {
+	query := savings_plans.SavingsPlansQuery{&infrastructure.InMemoryEventStream{[]infrastructure.Event{{"SavingsPlan.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}}}
+	_, err := query.GetSavingsPlans()
+
+	if err == nil {
+		t.Errorf("Expected savings plans to fail for an event that can't be decoded")
+	}
+}
@@ -1,6 +1,8 @@
 package totalInvestedMoney
 
 import (
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 )
@@ -9,32 +11,49 @@ type TotalInvestedMoneyQuery struct {
 	EventStream infrastructure.EventStream
 }
 
-func (totalInvestedMoneyQuery *TotalInvestedMoneyQuery) GetTotalInvestedMoney() float32 {
-	invested := float32(0.0)
-	for _, event := range totalInvestedMoneyQuery.EventStream.Get() {
-
-		if event.Name == portfolio.SharesAddedToPortfolioEventName {
-			shares, price := extractEventData(event)
-			invested += price * float32(shares)
-			continue
+func (totalInvestedMoneyQuery *TotalInvestedMoneyQuery) GetTotalInvestedMoney() (domain.Money, error) {
+	invested := domain.NewMoneyFromFloat(0, domain.DefaultCurrency)
+	storedEvents, err := totalInvestedMoneyQuery.EventStream.Get()
+	if err != nil {
+		return domain.Money{}, err
+	}
+	for _, storedEvent := range storedEvents {
+		domainEvent, err := event.Decode(storedEvent)
+		if err != nil {
+			return domain.Money{}, err
 		}
 
-		if event.Name == portfolio.SharesRemovedFromPortfolioEventName {
-			shares, price := extractEventData(event)
-			invested -= price * float32(shares)
-			continue
+		// fees and taxes are paid on top of buys and reduce the proceeds of sells
+		switch domainEvent := domainEvent.(type) {
+		case *portfolio.SharesAddedToPortfolioEvent:
+			sum, err := invested.Add(orderTotal(domainEvent.Price().MulQuantity(domainEvent.Shares()), domainEvent.Fee(), domainEvent.Taxes()))
+			if err == nil {
+				invested = sum
+			}
+		case *portfolio.SharesRemovedFromPortfolioEvent:
+			proceeds, _ := domainEvent.Price().MulQuantity(domainEvent.Shares()).Sub(orderTotal(domainEvent.Fee(), domainEvent.Taxes()))
+			sum, err := invested.Sub(proceeds)
+			if err == nil {
+				invested = sum
+			}
+		case *portfolio.SharesConvertedEvent:
+			sum, err := invested.Sub(domainEvent.CashInLieu())
+			if err == nil {
+				invested = sum
+			}
 		}
 	}
 
-	return invested
+	return invested, nil
 }
 
-func extractEventData(event infrastructure.Event) (int, float32) {
-	shares := event.Payload["shares"].(int)
-	price, ok := event.Payload["price"].(float32)
-	if !ok {
-		price = float32(event.Payload["price"].(float64))
+func orderTotal(amount domain.Money, charges ...domain.Money) domain.Money {
+	for _, charge := range charges {
+		sum, err := amount.Add(charge)
+		if err == nil {
+			amount = sum
+		}
 	}
 
-	return shares, price
+	return amount
 }
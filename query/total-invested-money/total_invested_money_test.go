@@ -1,6 +1,8 @@
 package totalInvestedMoney_test
 
 import (
+	"reflect"
+	"stock-monitor/domain"
 	"stock-monitor/domain/portfolio"
 	"stock-monitor/infrastructure"
 	totalInvestedMoney "stock-monitor/query/total-invested-money"
@@ -28,10 +30,72 @@ func TestPortfolioGivesTotalAmountOfInvestedMoney(t *testing.T) {
 
 	totalInvestedMoneyQuery := totalInvestedMoney.TotalInvestedMoneyQuery{&infrastructure.InMemoryEventStream{events}}
 
-	got := totalInvestedMoneyQuery.GetTotalInvestedMoney()
-	expected := float32(300)
+	got, _ := totalInvestedMoneyQuery.GetTotalInvestedMoney()
+	expected := domain.NewMoneyFromFloat(300, "EUR")
 
-	if got != expected {
+	if reflect.DeepEqual(got, expected) == false {
 		t.Errorf("Expected total invested money: %v, got %v", expected, got)
 	}
 }
+
+func TestStockSplitsDoNotChangeInvestedMoney(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 40.00, "shares": 10},
+			map[string]interface{}{"occurred_at": "2001-01-02"},
+		},
+		{
+			portfolio.StockSplitEventName,
+			map[string]interface{}{"ticker": "MO", "ratio_from": 1, "ratio_to": 4, "date": "2001-01-03"},
+			map[string]interface{}{"occurred_at": "2001-01-03"},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": 10.00, "shares": 20},
+			map[string]interface{}{"occurred_at": "2001-01-04"},
+		},
+	}
+
+	totalInvestedMoneyQuery := totalInvestedMoney.TotalInvestedMoneyQuery{&infrastructure.InMemoryEventStream{events}}
+
+	got, _ := totalInvestedMoneyQuery.GetTotalInvestedMoney()
+	expected := domain.NewMoneyFromFloat(200, "EUR")
+
+	if reflect.DeepEqual(got, expected) == false {
+		t.Errorf("Expected total invested money: %v, got %v", expected, got)
+	}
+}
+
+func TestFeesAndTaxesAreIncludedInInvestedMoney(t *testing.T) {
+	events := []infrastructure.Event{
+		{
+			portfolio.SharesAddedToPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "20", "fee": "5", "taxes": "1", "currency": "EUR", "shares": "20", "date": "2001-01-02"},
+			map[string]interface{}{"occurred_at": "2001-01-02", "version": 4},
+		},
+		{
+			portfolio.SharesRemovedFromPortfolioEventName,
+			map[string]interface{}{"ticker": "MO", "price": "30", "fee": "5", "taxes": "10", "currency": "EUR", "shares": "10", "date": "2001-01-03", "lots": ""},
+			map[string]interface{}{"occurred_at": "2001-01-03", "version": 6},
+		},
+	}
+
+	totalInvestedMoneyQuery := totalInvestedMoney.TotalInvestedMoneyQuery{&infrastructure.InMemoryEventStream{events}}
+
+	got, _ := totalInvestedMoneyQuery.GetTotalInvestedMoney()
+	expected := domain.NewMoneyFromFloat(121, "EUR")
+
+	if reflect.DeepEqual(got, expected) == false {
+		t.Errorf("Expected total invested money: %v, got %v", expected, got)
+	}
+}
+
+func TestTotalInvestedMoneyFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	totalInvestedMoneyQuery := totalInvestedMoney.TotalInvestedMoneyQuery{&infrastructure.InMemoryEventStream{[]infrastructure.Event{{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}}}
+	_, err := totalInvestedMoneyQuery.GetTotalInvestedMoney()
+
+	if err == nil {
+		t.Errorf("Expected total invested money to fail for an event that can't be decoded")
+	}
+}
@@ -0,0 +1,239 @@
+package valuation
+
+import (
+	"sort"
+	"stock-monitor/application/event"
+	"stock-monitor/domain"
+	"stock-monitor/domain/cash"
+	"stock-monitor/domain/dividend"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+)
+
+// Valuation values the shares held on past days with historical closing prices in the base currency
+type Valuation struct {
+	PortfolioEvents []domain.DomainEvent
+	DividendEvents  []domain.DomainEvent
+	Prices          query.PriceHistoryProvider
+	ExchangeRates   query.ExchangeRateProvider
+	BaseCurrency    string
+}
+
+// CashFlow sums the money spent on buys, received from sales and paid out as dividends on one day
+type CashFlow struct {
+	Date      string
+	Bought    domain.Money
+	Sold      domain.Money
+	Dividends domain.Money
+}
+
+// the events of several portfolios are valued as one portfolio
+func NewValuation(portfolioEventStreams []infrastructure.EventStream, dividendEventStreams []infrastructure.EventStream, prices query.PriceHistoryProvider, exchangeRates query.ExchangeRateProvider, baseCurrency string) (Valuation, error) {
+	portfolioEvents, err := Decode(portfolioEventStreams...)
+	if err != nil {
+		return Valuation{}, err
+	}
+	dividendEvents, err := Decode(dividendEventStreams...)
+	if err != nil {
+		return Valuation{}, err
+	}
+
+	return Valuation{portfolioEvents, dividendEvents, prices, exchangeRates, baseCurrency}, nil
+}
+
+// the value of the shares held at the end of the day, shares without price fail the valuation
+func (valuation *Valuation) ValueAt(date string) (domain.Money, error) {
+	total := domain.NewMoneyFromFloat(0, valuation.BaseCurrency)
+	holdings := HoldingsAt(valuation.PortfolioEvents, date)
+
+	tickers := []string{}
+	for ticker := range holdings {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	for _, ticker := range tickers {
+		price, err := valuation.Prices.Close(ticker, date)
+		if err != nil {
+			return domain.Money{}, err
+		}
+		value, err := valuation.InBaseCurrency(price.MulQuantity(holdings[ticker]))
+		if err != nil {
+			return domain.Money{}, err
+		}
+		total, _ = total.Add(value)
+	}
+
+	return total, nil
+}
+
+// the cash flows from and including the first until and including the last date ordered by date
+func (valuation *Valuation) CashFlows(from string, to string) ([]CashFlow, error) {
+	flowsByDate := map[string]*CashFlow{}
+	flowOn := func(date string) *CashFlow {
+		_, found := flowsByDate[date]
+		if !found {
+			zero := domain.NewMoneyFromFloat(0, valuation.BaseCurrency)
+			flowsByDate[date] = &CashFlow{date, zero, zero, zero}
+		}
+		return flowsByDate[date]
+	}
+
+	domainEvents := append([]domain.DomainEvent{}, valuation.PortfolioEvents...)
+	for _, domainEvent := range append(domainEvents, valuation.DividendEvents...) {
+		date, dated := EventDate(domainEvent)
+		if !dated || date < from || date > to {
+			continue
+		}
+
+		switch domainEvent := domainEvent.(type) {
+		case *portfolio.SharesAddedToPortfolioEvent:
+			cost, err := valuation.InBaseCurrency(cash.BuyCost(domainEvent.Price(), domainEvent.Shares(), domainEvent.Fee(), domainEvent.Taxes()))
+			if err != nil {
+				return nil, err
+			}
+			flowOn(date).Bought, _ = flowOn(date).Bought.Add(cost)
+		case *portfolio.SharesRemovedFromPortfolioEvent:
+			proceeds, err := valuation.InBaseCurrency(cash.SaleProceeds(domainEvent.Price(), domainEvent.Shares(), domainEvent.Fee(), domainEvent.Taxes()))
+			if err != nil {
+				return nil, err
+			}
+			flowOn(date).Sold, _ = flowOn(date).Sold.Add(proceeds)
+		case *portfolio.SharesConvertedEvent:
+			if !domainEvent.CashInLieu().IsPositive() {
+				continue
+			}
+			cashInLieu, err := valuation.InBaseCurrency(domainEvent.CashInLieu())
+			if err != nil {
+				return nil, err
+			}
+			flowOn(date).Sold, _ = flowOn(date).Sold.Add(cashInLieu)
+		case *dividend.DividendRecordedEvent:
+			net, err := valuation.InBaseCurrency(domainEvent.Net())
+			if err != nil {
+				return nil, err
+			}
+			flowOn(date).Dividends, _ = flowOn(date).Dividends.Add(net)
+		}
+	}
+
+	flows := []CashFlow{}
+	for _, flow := range flowsByDate {
+		flows = append(flows, *flow)
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		return flows[i].Date < flows[j].Date
+	})
+
+	return flows, nil
+}
+
+func (valuation *Valuation) InBaseCurrency(money domain.Money) (domain.Money, error) {
+	rate, err := valuation.ExchangeRates.Rate(money.Currency(), valuation.BaseCurrency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	return rate.Convert(money)
+}
+
+// renames are not dated and apply in the order they were recorded
+func HoldingsAt(portfolioEvents []domain.DomainEvent, date string) map[string]domain.Quantity {
+	holdings := map[string]domain.Quantity{}
+	for _, domainEvent := range portfolioEvents {
+		eventDate, dated := EventDate(domainEvent)
+		if dated && eventDate > date {
+			continue
+		}
+
+		switch domainEvent := domainEvent.(type) {
+		case *portfolio.SharesAddedToPortfolioEvent:
+			holdings[domainEvent.Ticker()] = holdings[domainEvent.Ticker()].Add(domainEvent.Shares())
+		case *portfolio.SharesRemovedFromPortfolioEvent:
+			holdings[domainEvent.Ticker()] = holdings[domainEvent.Ticker()].Sub(domainEvent.Shares())
+		case *portfolio.TickerRenamedEvent:
+			shares, found := holdings[domainEvent.Old()]
+			if found {
+				delete(holdings, domainEvent.Old())
+				holdings[domainEvent.New()] = holdings[domainEvent.New()].Add(shares)
+			}
+		case *portfolio.StockSplitEvent:
+			shares, found := holdings[domainEvent.Ticker()]
+			if found {
+				holdings[domainEvent.Ticker()] = shares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom())
+			}
+		case *portfolio.SharesConvertedEvent:
+			shares, found := holdings[domainEvent.Ticker()]
+			if found {
+				delete(holdings, domainEvent.Ticker())
+				converted := shares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom()).Sub(domainEvent.FractionalShares())
+				holdings[domainEvent.NewTicker()] = holdings[domainEvent.NewTicker()].Add(converted)
+			}
+		case *portfolio.SharesSpunOffEvent:
+			shares, found := holdings[domainEvent.Ticker()]
+			if found {
+				holdings[domainEvent.NewTicker()] = holdings[domainEvent.NewTicker()].Add(shares.MulRatio(domainEvent.RatioTo(), domainEvent.RatioFrom()))
+			}
+		}
+	}
+
+	for ticker, shares := range holdings {
+		if !shares.IsPositive() {
+			delete(holdings, ticker)
+		}
+	}
+
+	return holdings
+}
+
+func EventDate(domainEvent domain.DomainEvent) (string, bool) {
+	switch domainEvent := domainEvent.(type) {
+	case *portfolio.SharesAddedToPortfolioEvent:
+		return domainEvent.Date(), true
+	case *portfolio.SharesRemovedFromPortfolioEvent:
+		return domainEvent.Date(), true
+	case *portfolio.StockSplitEvent:
+		return domainEvent.Date(), true
+	case *portfolio.SharesConvertedEvent:
+		return domainEvent.Date(), true
+	case *portfolio.SharesSpunOffEvent:
+		return domainEvent.Date(), true
+	case *dividend.DividendRecordedEvent:
+		return domainEvent.Date(), true
+	}
+
+	return "", false
+}
+
+func FirstOrderDate(portfolioEvents []domain.DomainEvent) string {
+	first := ""
+	for _, domainEvent := range portfolioEvents {
+		added, ok := domainEvent.(*portfolio.SharesAddedToPortfolioEvent)
+		if ok && (first == "" || added.Date() < first) {
+			first = added.Date()
+		}
+	}
+
+	return first
+}
+
+// a stored event that can't be decoded fails the decoding, the valuation would be wrong without it
+func Decode(eventStreams ...infrastructure.EventStream) ([]domain.DomainEvent, error) {
+	domainEvents := []domain.DomainEvent{}
+	for _, eventStream := range eventStreams {
+		storedEvents, err := eventStream.Get()
+		if err != nil {
+			return nil, query.NewUnreadableEventStreamError(err)
+		}
+		for _, storedEvent := range storedEvents {
+			domainEvent, err := event.Decode(storedEvent)
+			if err != nil {
+				return nil, query.NewUnreadableEventStreamError(err)
+			}
+			domainEvents = append(domainEvents, domainEvent)
+		}
+	}
+
+	return domainEvents, nil
+}
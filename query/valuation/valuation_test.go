@@ -0,0 +1,82 @@
+package valuation_test
+
+import (
+	"reflect"
+	"stock-monitor/domain"
+	"stock-monitor/domain/portfolio"
+	"stock-monitor/infrastructure"
+	"stock-monitor/query"
+	"stock-monitor/query/valuation"
+	"testing"
+)
+
+func TestHoldingsFollowSplitsAndRenames(t *testing.T) {
+	added := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	split := portfolio.NewStockSplitEvent("MO", 1, 2, "2000-02-01")
+	renamed := portfolio.NewTickerRenamedEvent("MO", "FOO")
+	removed := portfolio.NewSharesRemovedFromPortfolioEvent("FOO", domain.NewQuantityFromInt(20), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-03-01", []portfolio.SelectedLot{})
+	events := []domain.DomainEvent{&added, &split, &renamed, &removed}
+
+	for date, want := range map[string]map[string]domain.Quantity{
+		"1999-12-31": {},
+		"2000-01-15": {"FOO": domain.NewQuantityFromInt(10)},
+		"2000-02-01": {"FOO": domain.NewQuantityFromInt(20)},
+		"2000-03-01": {},
+	} {
+		got := valuation.HoldingsAt(events, date)
+
+		if reflect.DeepEqual(got, want) == false {
+			t.Errorf("Unexpected holdings on %s. Expected:%#v Got:%#v", date, want, got)
+		}
+	}
+}
+
+func TestHoldingsFollowConversions(t *testing.T) {
+	added := portfolio.NewSharesAddedToPortfolioEvent("MO", domain.NewQuantityFromInt(10), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	held := portfolio.NewSharesAddedToPortfolioEvent("PM", domain.NewQuantityFromInt(2), domain.NewMoneyFromFloat(10, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), domain.NewMoneyFromFloat(0, "EUR"), "2000-01-01")
+	fractionalShares, _ := domain.NewQuantity("0.5")
+	converted := portfolio.NewSharesConvertedEvent("MO", "PM", 4, 3, fractionalShares, domain.NewMoneyFromFloat(5, "EUR"), "2000-02-01")
+	events := []domain.DomainEvent{&added, &held, &converted}
+
+	got := valuation.HoldingsAt(events, "2000-02-01")
+	want := map[string]domain.Quantity{"PM": domain.NewQuantityFromInt(9)}
+
+	if reflect.DeepEqual(got, want) == false {
+		t.Errorf("Unexpected holdings. Expected:%#v Got:%#v", want, got)
+	}
+}
+
+func TestValuesAreConvertedToBaseCurrency(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{
+		[]infrastructure.Event{
+			{
+				portfolio.SharesAddedToPortfolioEventName,
+				map[string]interface{}{"ticker": "KO", "shares": "4", "price": "50", "fee": "0", "taxes": "0", "currency": "USD", "date": "2000-01-01"},
+				map[string]interface{}{"occurred_at": "2000-01-01", "version": 4},
+			},
+		},
+	}
+	prices := query.FakePriceHistoryProvider{map[string]map[string]domain.Money{"KO": {"2000-01-01": domain.NewMoneyFromFloat(50, "USD")}}}
+	exchangeRates := query.FakeExchangeRateProvider{map[string]string{"EUR": "1", "USD": "1.25"}}
+	portfolioValuation, _ := valuation.NewValuation([]infrastructure.EventStream{&eventStream}, []infrastructure.EventStream{}, prices, exchangeRates, "EUR")
+
+	got, err := portfolioValuation.ValueAt("2000-01-05")
+
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if got.String() != "160 EUR" {
+		t.Errorf("Unexpected value. Expected:%#v Got:%#v", "160 EUR", got.String())
+	}
+}
+
+func TestValuationFailsWhenAnEventCanNotBeDecoded(t *testing.T) {
+	eventStream := infrastructure.InMemoryEventStream{[]infrastructure.Event{{"Portfolio.Unknown", map[string]interface{}{}, map[string]interface{}{"occurred_at": "2001-01-02"}}}}
+
+	_, err := valuation.NewValuation([]infrastructure.EventStream{&eventStream}, []infrastructure.EventStream{}, query.FakePriceHistoryProvider{}, query.FakeExchangeRateProvider{}, "EUR")
+
+	_, ok := err.(*query.UnreadableEventStreamError)
+	if !ok {
+		t.Errorf("Expected UnreadableEventStreamError but got %#v", err)
+	}
+}
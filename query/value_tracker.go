@@ -2,19 +2,78 @@ package query
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
+	"stock-monitor/domain"
+	"sync"
 	"time"
 )
 
+// ValueTracker gives the current value of a ticker in the currency it is traded in
 type ValueTracker interface {
-	Current(ticker string) float32
+	Current(ticker string, currency string) float32
+}
+
+// RecordingValueTracker keeps the first value of every tracked ticker per day as daily price in the store
+type RecordingValueTracker struct {
+	ValueTracker ValueTracker
+	Store        PriceStore
+	lock         sync.Mutex
+	recorded     map[string]string
+}
+
+func NewRecordingValueTracker(valueTracker ValueTracker, store PriceStore) *RecordingValueTracker {
+	return &RecordingValueTracker{ValueTracker: valueTracker, Store: store, recorded: map[string]string{}}
+}
+
+func (valueTracker *RecordingValueTracker) Current(ticker string, currency string) float32 {
+	value := valueTracker.ValueTracker.Current(ticker, currency)
+	if value <= 0 {
+		return value
+	}
+
+	// the store rewrites its whole file, so each ticker is recorded only once a day
+	today := time.Now().Format("2006-01-02")
+	if !valueTracker.claim(ticker, today) {
+		return value
+	}
+
+	price := domain.NewMoneyFromFloat32(value, currency)
+	err := valueTracker.Store.Record(ticker, DailyPrice{today, price, price, price, price})
+	if err != nil {
+		valueTracker.release(ticker, today)
+		log.Printf("Snapshot of %s could not be recorded: %s", ticker, err)
+	}
+
+	return value
+}
+
+func (valueTracker *RecordingValueTracker) claim(ticker string, day string) bool {
+	valueTracker.lock.Lock()
+	defer valueTracker.lock.Unlock()
+
+	if valueTracker.recorded[ticker] == day {
+		return false
+	}
+	valueTracker.recorded[ticker] = day
+
+	return true
+}
+
+func (valueTracker *RecordingValueTracker) release(ticker string, day string) {
+	valueTracker.lock.Lock()
+	defer valueTracker.lock.Unlock()
+
+	if valueTracker.recorded[ticker] == day {
+		delete(valueTracker.recorded, ticker)
+	}
 }
 
 type FakeValueTracker struct {
 	ValueMap map[string]float32
 }
 
-func (valueTracker FakeValueTracker) Current(ticker string) float32 {
+func (valueTracker FakeValueTracker) Current(ticker string, currency string) float32 {
 	time.Sleep(40 * time.Millisecond)
 	return valueTracker.ValueMap[ticker]
 }
@@ -31,7 +90,7 @@ type FinnHubResponse struct {
 	Value float32 `json:"c"`
 }
 
-func (valueTracker FinnHubValueTracker) Current(ticker string) float32 {
+func (valueTracker FinnHubValueTracker) Current(ticker string, currency string) float32 {
 	client := &http.Client{}
 	req, _ := http.NewRequest("GET", "https://finnhub.io/api/v1/quote?symbol="+ticker, nil)
 	req.Header.Set("X-Finnhub-Token", valueTracker.apiKey)
@@ -0,0 +1,84 @@
+package query_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"stock-monitor/domain"
+	"stock-monitor/query"
+	"testing"
+	"time"
+)
+
+func TestRecordingValueTrackerKeepsTheFirstSnapshotOfTheDay(t *testing.T) {
+	file, _ := ioutil.TempFile("", "price_history_*.json")
+	os.Remove(file.Name())
+	defer os.Remove(file.Name())
+	store := query.NewFilePriceStore(file.Name())
+	tracker := query.NewRecordingValueTracker(query.FakeValueTracker{map[string]float32{"MO": 10}}, store)
+
+	tracker.Current("MO", "USD")
+	tracker.ValueTracker = query.FakeValueTracker{map[string]float32{"MO": 12}}
+	value := tracker.Current("MO", "USD")
+	tracker.Current("UNKNOWN", "USD")
+
+	today := time.Now().Format("2006-01-02")
+	got, _ := store.Closes("MO", today, today)
+	if value != 12 {
+		t.Errorf("Unexpected value. Expected:%#v Got:%#v", 12, value)
+	}
+	if len(got) != 1 || got[0].Open.String() != "10 USD" || got[0].High.String() != "10 USD" || got[0].Low.String() != "10 USD" || got[0].Close.String() != "10 USD" {
+		t.Errorf("Unexpected snapshot: %#v", got)
+	}
+	if unknown, _ := store.Closes("UNKNOWN", today, today); len(unknown) != 0 {
+		t.Errorf("Unexpected snapshot of ticker without value: %#v", unknown)
+	}
+}
+
+func TestRecordingValueTrackerRecordsEachTickerOnceADay(t *testing.T) {
+	store := &countingPriceStore{}
+	tracker := query.NewRecordingValueTracker(query.FakeValueTracker{map[string]float32{"MO": 10, "KO": 20}}, store)
+
+	tracker.Current("MO", "USD")
+	tracker.Current("MO", "USD")
+	tracker.Current("KO", "USD")
+	tracker.Current("MO", "USD")
+
+	if store.records != 2 {
+		t.Errorf("Unexpected number of records. Expected:%#v Got:%#v", 2, store.records)
+	}
+}
+
+func TestRecordingValueTrackerRetriesFailedSnapshots(t *testing.T) {
+	store := &countingPriceStore{expectedError: errors.New("disk full")}
+	tracker := query.NewRecordingValueTracker(query.FakeValueTracker{map[string]float32{"MO": 10}}, store)
+
+	tracker.Current("MO", "USD")
+	tracker.Current("MO", "USD")
+
+	if store.records != 2 {
+		t.Errorf("Unexpected number of records. Expected:%#v Got:%#v", 2, store.records)
+	}
+}
+
+type countingPriceStore struct {
+	records       int
+	expectedError error
+}
+
+func (store *countingPriceStore) Close(ticker string, date string) (domain.Money, error) {
+	return domain.Money{}, nil
+}
+
+func (store *countingPriceStore) Closes(ticker string, from string, to string) ([]query.DailyPrice, error) {
+	return nil, nil
+}
+
+func (store *countingPriceStore) Save(ticker string, prices []query.DailyPrice) error {
+	return nil
+}
+
+func (store *countingPriceStore) Record(ticker string, price query.DailyPrice) error {
+	store.records++
+	return store.expectedError
+}
@@ -2,31 +2,108 @@ package main
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"stock-monitor/application/shared"
 	"stock-monitor/infrastructure/di"
 	"stock-monitor/infrastructure/handler/add_dividends"
 	"stock-monitor/infrastructure/handler/add_stock"
+	"stock-monitor/infrastructure/handler/convert_stock"
+	"stock-monitor/infrastructure/handler/define_savings_plan"
+	"stock-monitor/infrastructure/handler/deposit_cash"
+	"stock-monitor/infrastructure/handler/execute_savings_plan_order"
+	"stock-monitor/infrastructure/handler/fill_savings_plan_orders"
+	"stock-monitor/infrastructure/handler/portfolio_route"
+	"stock-monitor/infrastructure/handler/reinvest_dividend"
 	"stock-monitor/infrastructure/handler/rename_stock"
 	"stock-monitor/infrastructure/handler/sell_stock"
+	"stock-monitor/infrastructure/handler/show_cash"
 	"stock-monitor/infrastructure/handler/show_dividend_history"
+	"stock-monitor/infrastructure/handler/show_fees"
+	"stock-monitor/infrastructure/handler/show_lots"
 	"stock-monitor/infrastructure/handler/show_order_history"
+	"stock-monitor/infrastructure/handler/show_performance"
 	"stock-monitor/infrastructure/handler/show_portfolio"
+	"stock-monitor/infrastructure/handler/show_portfolio_history"
+	"stock-monitor/infrastructure/handler/show_realized_gains"
+	"stock-monitor/infrastructure/handler/show_savings_plans"
+	"stock-monitor/infrastructure/handler/spin_off"
+	"stock-monitor/infrastructure/handler/split_stock"
+	"stock-monitor/infrastructure/handler/withdraw_cash"
 )
 
 func main() {
 	e := echo.New()
+	e.Use(middleware.Recover())
 
-	positionListQuery := di.MakePositionListQuery()
-	positionListHandler := show_portfolio.ShowPortfolioHandler{positionListQuery}
+	positionListQuery := di.MakeAggregatedPositionListQuery()
+	positionListHandler := show_portfolio.ShowPortfolioHandler{positionListQuery, di.BaseCurrency()}
 	e.GET("/portfolio", positionListHandler.ShowPortfolio)
+	e.GET("/portfolio/totals", positionListHandler.ShowTotals)
 
-	orderHistoryQuery := di.MakeOrderHistoryQuery()
+	portfolioHistoryHandler := show_portfolio_history.ShowPortfolioHistoryHandler{di.MakeAggregatedPortfolioHistoryQuery()}
+	e.GET("/portfolio/history", portfolioHistoryHandler.ShowPortfolioHistory)
+
+	orderHistoryQuery := di.MakeOrderHistoryQuery(shared.DefaultPortfolioId)
 	orderHistoryHandler := show_order_history.ShowOrderHistoryHandler{orderHistoryQuery}
 	e.GET("/order-history", orderHistoryHandler.ShowOrderHistory)
 
-	dividendHistoryQuery := di.MakeDividendHistoryQuery()
+	dividendHistoryQuery := di.MakeDividendHistoryQuery(shared.DefaultPortfolioId)
 	dividendHistoryHandler := show_dividend_history.ShowDividendHistoryHandler{dividendHistoryQuery}
 	e.GET("/dividend-history", dividendHistoryHandler.ShowDividendHistory)
 
+	realizedGainsHandler := show_realized_gains.ShowRealizedGainsHandler{di.MakeRealizedGainsQuery(shared.DefaultPortfolioId), di.LotMatchingMethod()}
+	e.GET("/realized-gains", realizedGainsHandler.ShowRealizedGains)
+
+	lotsHandler := show_lots.ShowLotsHandler{di.MakeLotsQuery(shared.DefaultPortfolioId), di.LotMatchingMethod()}
+	e.GET("/lots", lotsHandler.ShowLots)
+
+	feesHandler := show_fees.ShowFeesHandler{di.MakeFeesQuery(shared.DefaultPortfolioId)}
+	e.GET("/fees", feesHandler.ShowFees)
+
+	cashHandler := show_cash.ShowCashHandler{di.MakeCashAccountQuery(shared.DefaultPortfolioId)}
+	e.GET("/cash", cashHandler.ShowCash)
+
+	performanceHandler := show_performance.ShowPerformanceHandler{di.MakePerformanceQuery(shared.DefaultPortfolioId)}
+	e.GET("/performance", performanceHandler.ShowPerformance)
+
+	savingsPlansHandler := show_savings_plans.ShowSavingsPlansHandler{di.MakeSavingsPlansQuery(shared.DefaultPortfolioId)}
+	e.GET("/savings-plans", savingsPlansHandler.ShowSavingsPlans)
+
+	portfolioRoutes := portfolio_route.NewPortfolioRoutes()
+	for _, portfolioId := range di.PortfolioIds() {
+		portfolioPositionListHandler := show_portfolio.ShowPortfolioHandler{di.MakePositionListQuery(portfolioId), di.BaseCurrency()}
+		portfolioRoutes.Add(portfolioId, "/positions", portfolioPositionListHandler.ShowPortfolio)
+		portfolioRoutes.Add(portfolioId, "/totals", portfolioPositionListHandler.ShowTotals)
+
+		portfolioHistoryHandler := show_portfolio_history.ShowPortfolioHistoryHandler{di.MakePortfolioHistoryQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/history", portfolioHistoryHandler.ShowPortfolioHistory)
+
+		portfolioOrderHistoryHandler := show_order_history.ShowOrderHistoryHandler{di.MakeOrderHistoryQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/order-history", portfolioOrderHistoryHandler.ShowOrderHistory)
+
+		portfolioDividendHistoryHandler := show_dividend_history.ShowDividendHistoryHandler{di.MakeDividendHistoryQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/dividend-history", portfolioDividendHistoryHandler.ShowDividendHistory)
+
+		portfolioRealizedGainsHandler := show_realized_gains.ShowRealizedGainsHandler{di.MakeRealizedGainsQuery(portfolioId), di.LotMatchingMethod()}
+		portfolioRoutes.Add(portfolioId, "/realized-gains", portfolioRealizedGainsHandler.ShowRealizedGains)
+
+		portfolioLotsHandler := show_lots.ShowLotsHandler{di.MakeLotsQuery(portfolioId), di.LotMatchingMethod()}
+		portfolioRoutes.Add(portfolioId, "/lots", portfolioLotsHandler.ShowLots)
+
+		portfolioFeesHandler := show_fees.ShowFeesHandler{di.MakeFeesQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/fees", portfolioFeesHandler.ShowFees)
+
+		portfolioCashHandler := show_cash.ShowCashHandler{di.MakeCashAccountQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/cash", portfolioCashHandler.ShowCash)
+
+		portfolioPerformanceHandler := show_performance.ShowPerformanceHandler{di.MakePerformanceQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/performance", portfolioPerformanceHandler.ShowPerformance)
+
+		portfolioSavingsPlansHandler := show_savings_plans.ShowSavingsPlansHandler{di.MakeSavingsPlansQuery(portfolioId)}
+		portfolioRoutes.Add(portfolioId, "/savings-plans", portfolioSavingsPlansHandler.ShowSavingsPlans)
+	}
+	portfolioRoutes.Register(e)
+
 	portfolioCommandHandler := di.MakePortfolioCommandHandler()
 	addStockHandler := add_stock.AddStockHandler{portfolioCommandHandler}
 	e.POST("/add-stock", addStockHandler.AddStock)
@@ -37,9 +114,38 @@ func main() {
 	renameStockHandler := rename_stock.RenameStockHandler{portfolioCommandHandler}
 	e.POST("/rename-stock", renameStockHandler.RenameStock)
 
+	splitStockHandler := split_stock.SplitStockHandler{portfolioCommandHandler}
+	e.POST("/split-stock", splitStockHandler.SplitStock)
+
+	convertStockHandler := convert_stock.ConvertStockHandler{portfolioCommandHandler}
+	e.POST("/convert-stock", convertStockHandler.ConvertStock)
+
+	spinOffHandler := spin_off.SpinOffHandler{portfolioCommandHandler}
+	e.POST("/spin-off", spinOffHandler.SpinOff)
+
 	dividendCommandHandler := di.MakeDividendCommandHandler()
 	addDividendsHandler := add_dividends.AddDividendsHandler{dividendCommandHandler}
 	e.POST("/add-dividends", addDividendsHandler.AddDividends)
 
+	reinvestDividendHandler := reinvest_dividend.ReinvestDividendHandler{dividendCommandHandler}
+	e.POST("/reinvest-dividend", reinvestDividendHandler.ReinvestDividend)
+
+	cashCommandHandler := di.MakeCashCommandHandler()
+	depositCashHandler := deposit_cash.DepositCashHandler{cashCommandHandler}
+	e.POST("/deposit", depositCashHandler.DepositCash)
+
+	withdrawCashHandler := withdraw_cash.WithdrawCashHandler{cashCommandHandler}
+	e.POST("/withdraw", withdrawCashHandler.WithdrawCash)
+
+	savingsPlanCommandHandler := di.MakeSavingsPlanCommandHandler()
+	defineSavingsPlanHandler := define_savings_plan.DefineSavingsPlanHandler{savingsPlanCommandHandler}
+	e.POST("/savings-plans", defineSavingsPlanHandler.DefineSavingsPlan)
+
+	executeSavingsPlanOrderHandler := execute_savings_plan_order.ExecuteSavingsPlanOrderHandler{savingsPlanCommandHandler}
+	e.POST("/savings-plans/execute", executeSavingsPlanOrderHandler.ExecuteSavingsPlanOrder)
+
+	fillSavingsPlanOrdersHandler := fill_savings_plan_orders.FillSavingsPlanOrdersHandler{savingsPlanCommandHandler}
+	e.POST("/savings-plans/fill", fillSavingsPlanOrdersHandler.FillSavingsPlanOrders)
+
 	e.Logger.Fatal(e.Start(":8080"))
 }